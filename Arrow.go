@@ -0,0 +1,187 @@
+/*
+This file adds Arrow Sudoku: the digit in a circle equals the sum of the digits along its arrow. Digits may repeat
+along an arrow, unless the classic rules forbid it (e.g. two arrow cells in the same row).
+
+Arrows are read from a clue file (see Clues.go) as "arrow", the circle, a colon, and the cells along the arrow:
+
+	arrow C3: C4, C5, D6
+
+Functions:
+- **`NewArrowConstraint`**: Builds the constraint enforcing the sum of every arrow.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Arrow is a circled cell whose digit must equal the sum of the digits along the arrow.
+type Arrow struct {
+	Circle string   // The circled cell (e.g. "C3")
+	Cells  []string // The cells along the arrow, starting next to the circle
+}
+
+// checkArrow checks that arrow fits the board: the circle is on the board and off the arrow, and the smallest
+// possible sum of the arrow (a 1 in every cell) is not larger than the largest digit.
+func checkArrow(arrow Arrow, shape Shape) error {
+	if _, _, ok := shape.ParsePos(arrow.Circle); !ok {
+		return fmt.Errorf("%s is not a cell of the board", arrow.Circle)
+	}
+	for _, pos := range arrow.Cells {
+		if pos == arrow.Circle {
+			return fmt.Errorf("the circle %s can't be on its own arrow", pos)
+		}
+	}
+	if len(arrow.Cells) > shape.Size {
+		return fmt.Errorf("an arrow of %d cells adds up to more than %d", len(arrow.Cells), shape.Size)
+	}
+	return nil
+}
+
+// ArrowConstraint requires the digit of every circle to equal the sum of the digits along its arrow.
+type ArrowConstraint struct {
+	shape  Shape
+	arrows []Arrow
+	byCell map[string][]int    // Indexes of the arrows each cell belongs to (as circle or along the arrow)
+	peers  map[string][]string // The other cells of the arrows containing each cell
+}
+
+// NewArrowConstraint creates the constraint for the given arrows on a board of the given shape.
+func NewArrowConstraint(shape Shape, arrows []Arrow) *ArrowConstraint {
+	c := &ArrowConstraint{
+		shape:  shape,
+		arrows: arrows,
+		byCell: make(map[string][]int),
+		peers:  make(map[string][]string),
+	}
+	for index, arrow := range arrows {
+		cells := append([]string{arrow.Circle}, arrow.Cells...)
+		for _, pos := range cells {
+			c.byCell[pos] = append(c.byCell[pos], index)
+			for _, other := range cells {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *ArrowConstraint) Name() string {
+	return "arrows"
+}
+
+// Arrows returns the arrows of the constraint.
+func (c *ArrowConstraint) Arrows() []Arrow {
+	return c.arrows
+}
+
+// Allows reports whether digit can go at pos while every arrow containing pos can still add up: the circle must
+// be able to hold a value between the smallest and largest sums the arrow can still reach.
+func (c *ArrowConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	for _, index := range c.byCell[pos] {
+		arrow := c.arrows[index]
+		low, high := partialSum(c.shape, grid, arrow.Cells, pos, digit) // Sums the arrow can still reach
+		circleLow, circleHigh := partialSum(c.shape, grid, []string{arrow.Circle}, pos, digit)
+		if circleLow > high || circleHigh < low {
+			return false
+		}
+	}
+	return true
+}
+
+// partialSum returns the smallest and largest sums cells can still reach once digit is placed at pos, counting
+// every other empty cell as anything from 1 to the largest digit (repeats allowed).
+func partialSum(shape Shape, grid map[string]rune, cells []string, pos string, digit rune) (int, int) {
+	low, high := 0, 0
+	for _, cell := range cells {
+		switch val := grid[cell]; {
+		case cell == pos:
+			low, high = low+digitValue(shape, digit), high+digitValue(shape, digit)
+		case val != '.' && val != 0:
+			low, high = low+digitValue(shape, val), high+digitValue(shape, val)
+		default:
+			low, high = low+1, high+shape.Size
+		}
+	}
+	return low, high
+}
+
+// Peers returns the other cells of the arrows containing pos.
+func (c *ArrowConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate narrows the candidates of every arrow by bounds: the circle keeps only the digits between the
+// smallest and largest sums of the arrow, and each cell of the arrow keeps only the digits that let the arrow
+// reach one of the circle's candidates. It returns false if an arrow can no longer add up.
+func (c *ArrowConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, arrow := range c.arrows { // Arrows and their cells are visited in order, so eliminations are deterministic
+		circleLow, circleHigh, ok := valueBounds(c.shape, grid, candidates, arrow.Circle)
+		if !ok {
+			return nil, false
+		}
+		narrowed, low, high, ok := narrowSum(c.shape, grid, candidates, arrow.Cells, circleLow, circleHigh)
+		if !ok { // The circle can't match any sum of the arrow
+			return nil, false
+		}
+		eliminations = append(eliminations, narrowed...)
+		if grid[arrow.Circle] == '.' {
+			for _, digit := range candidates[arrow.Circle] {
+				if value := digitValue(c.shape, digit); value < low || value > high {
+					eliminations = append(eliminations, Elimination{Pos: arrow.Circle, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
+
+// narrowSum removes the candidates of cells that can't be part of a sum between targetLow and targetHigh, given
+// the smallest and largest values of the other cells (repeats allowed). It also returns the smallest and largest
+// sums the cells can reach, and false if none of them is within the target.
+func narrowSum(shape Shape, grid map[string]rune, candidates map[string][]rune, cells []string, targetLow, targetHigh int) ([]Elimination, int, int, bool) {
+	lows := make([]int, len(cells)) // Smallest and largest value of each cell
+	highs := make([]int, len(cells))
+	low, high := 0, 0
+	for i, pos := range cells {
+		var ok bool
+		if lows[i], highs[i], ok = valueBounds(shape, grid, candidates, pos); !ok {
+			return nil, 0, 0, false
+		}
+		low, high = low+lows[i], high+highs[i]
+	}
+	if targetLow > high || targetHigh < low {
+		return nil, low, high, false
+	}
+
+	var eliminations []Elimination
+	for i, pos := range cells {
+		if grid[pos] != '.' {
+			continue
+		}
+		for _, digit := range candidates[pos] {
+			value := digitValue(shape, digit)
+			if value+low-lows[i] > targetHigh || value+high-highs[i] < targetLow { // The other cells can't make up the difference
+				eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+			}
+		}
+	}
+	return eliminations, low, high, true
+}
+
+// valueBounds returns the smallest and largest values pos can take: its digit if filled, or its smallest and
+// largest candidates. It returns false for an empty cell without candidates.
+func valueBounds(shape Shape, grid map[string]rune, candidates map[string][]rune, pos string) (int, int, bool) {
+	if val := grid[pos]; val != '.' && val != 0 {
+		return digitValue(shape, val), digitValue(shape, val), true
+	}
+	low, high := shape.Size+1, 0
+	for _, digit := range candidates[pos] {
+		value := digitValue(shape, digit)
+		low, high = min(low, value), max(high, value)
+	}
+	return low, high, low <= high
+}
@@ -0,0 +1,74 @@
+/*
+This file measures how many guesses a puzzle requires beyond pure logic. A backdoor is a set of cells such that,
+once those cells are filled with their correct digits, the logical techniques of Logic.go can solve the rest of
+the puzzle on their own. The size of the smallest backdoor is the number of guesses a solver who only knows
+those techniques must make, which setters use as a hardness signal alongside the techniques required.
+
+- `Backdoor`: Finds the smallest backdoor of a puzzle, up to a maximum size.
+- `findBackdoor`: Tries every combination of a given number of cells.
+*/
+
+package sudokux
+
+// Backdoor returns the size of the smallest backdoor of the puzzle and the cells forming it, trying sizes up to
+// maxSize. A size of 0 means the puzzle is solvable by logic alone. It returns false if the puzzle has no
+// unique solution or if no backdoor of at most maxSize cells exists.
+func Backdoor(grid map[string]rune, maxSize int) (int, []string, bool) {
+	solution, solved := SolveSudoku(grid) // The correct digit of every cell is needed to fill the backdoor
+	if !solved {
+		return 0, nil, false
+	}
+
+	stuck, _, done := SolveLogically(grid) // Apply logic first: the cells it fills never need guessing
+	if done {
+		return 0, nil, true
+	}
+
+	var empty []string // Cells logic could not fill, in row-major order
+	for _, pos := range ShapeOf(grid).Cells() {
+		if stuck[pos] == '.' {
+			empty = append(empty, pos)
+		}
+	}
+
+	for size := 1; size <= maxSize; size++ { // Try the smallest backdoors first
+		if cells := findBackdoor(stuck, solution, empty, size); cells != nil {
+			return size, cells, true
+		}
+	}
+	return 0, nil, false
+}
+
+// findBackdoor tries every combination of size cells among empty, filling them from the solution, and returns
+// the first combination that lets logic finish the grid (or nil if there is none).
+func findBackdoor(grid, solution map[string]rune, empty []string, size int) []string {
+	chosen := make([]int, size) // Indexes into empty of the cells in the current combination
+	var try func(depth, start int) bool
+	try = func(depth, start int) bool {
+		if depth == size { // A full combination: fill it in and see whether logic finishes the grid
+			guessed := make(map[string]rune)
+			copyGrid(grid, guessed)
+			for _, index := range chosen {
+				guessed[empty[index]] = solution[empty[index]]
+			}
+			_, _, done := SolveLogically(guessed)
+			return done
+		}
+		for index := start; index < len(empty); index++ {
+			chosen[depth] = index
+			if try(depth+1, index+1) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !try(0, 0) {
+		return nil
+	}
+	cells := make([]string, size)
+	for i, index := range chosen {
+		cells[i] = empty[index]
+	}
+	return cells
+}
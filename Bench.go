@@ -0,0 +1,115 @@
+/*
+This file runs several solving engines over the same set of puzzles and compares them. Each engine's time and
+node count are recorded per puzzle, and any puzzle on which the engines disagree (about the number of solutions
+or about the solution itself) is reported, which keeps alternative implementations honest against each other.
+
+- `Bench`: Runs the engines over the puzzles and returns a `BenchReport`.
+- `BenchResult.Stats`: Summarizes the times and node counts of one engine (minimum, median, maximum, total).
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// BenchResult holds the measurements of one engine over a puzzle set.
+type BenchResult struct {
+	Engine     string          // Name of the engine
+	Times      []time.Duration // Time taken on each puzzle, in puzzle order
+	Nodes      []int           // Search nodes visited on each puzzle, in puzzle order
+	Counts     []int           // Number of solutions found on each puzzle (0, 1 or 2)
+	Total      time.Duration   // Time taken over the whole set
+	TotalNodes int             // Search nodes visited over the whole set
+}
+
+// BenchStats summarizes the measurements of one engine over a puzzle set.
+type BenchStats struct {
+	MinTime, MedianTime, MaxTime, TotalTime     time.Duration // Time taken per puzzle, and over the whole set
+	MinNodes, MedianNodes, MaxNodes, TotalNodes int           // Search nodes visited per puzzle, and over the whole set
+}
+
+// Stats returns the minimum, median, and maximum time and node count per puzzle, and their totals. The median of
+// an even number of puzzles is the mean of the two middle ones. All of them are zero for an empty set.
+func (result BenchResult) Stats() BenchStats {
+	stats := BenchStats{TotalTime: result.Total, TotalNodes: result.TotalNodes}
+	if len(result.Times) == 0 {
+		return stats
+	}
+	times, nodes := slices.Clone(result.Times), slices.Clone(result.Nodes)
+	slices.Sort(times)
+	slices.Sort(nodes)
+	n := len(times)
+	stats.MinTime, stats.MedianTime, stats.MaxTime = times[0], (times[(n-1)/2]+times[n/2])/2, times[n-1]
+	stats.MinNodes, stats.MedianNodes, stats.MaxNodes = nodes[0], (nodes[(n-1)/2]+nodes[n/2])/2, nodes[n-1]
+	return stats
+}
+
+// Disagreement describes a puzzle on which an engine's answer differs from the first engine's.
+type Disagreement struct {
+	Puzzle    int    // Index of the puzzle in the set
+	Engine    string // Engine that disagrees
+	Reference string // Engine it was compared against (always the first one)
+	Detail    string // What differs
+}
+
+// BenchReport is the outcome of Bench.
+type BenchReport struct {
+	Results       []BenchResult  // One result per engine, in the order the engines were given
+	Disagreements []Disagreement // Every disagreement found, ordered by puzzle
+}
+
+// Bench runs every engine over every grid and compares their answers. When no engine is given,
+// every registered engine (see Engines) is used. The first engine is the reference that the others are compared against.
+func Bench(grids []Grid, engines ...Engine) BenchReport {
+	if len(engines) == 0 {
+		engines = Engines()
+	}
+
+	report := BenchReport{}
+	solutions := make([][]string, len(engines)) // Canonical solution of each puzzle, per engine
+	for e, engine := range engines {
+		result := BenchResult{Engine: engine.Name()}
+		for _, grid := range grids {
+			start := time.Now()
+			solution, count, nodes := engine.Solve(grid)
+			elapsed := time.Since(start)
+
+			result.Times = append(result.Times, elapsed)
+			result.Nodes = append(result.Nodes, nodes)
+			result.Counts = append(result.Counts, count)
+			result.Total += elapsed
+			result.TotalNodes += nodes
+			if count == 1 { // Only a unique solution is comparable between engines
+				solutions[e] = append(solutions[e], CanonicalString(solution))
+			} else {
+				solutions[e] = append(solutions[e], "")
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	for p := range grids { // Compare every engine with the first one, puzzle by puzzle
+		reference := report.Results[0]
+		for e := 1; e < len(engines); e++ {
+			result := report.Results[e]
+			detail := ""
+			if result.Counts[p] != reference.Counts[p] {
+				detail = fmt.Sprintf("found %d solution(s), %s found %d", result.Counts[p], reference.Engine, reference.Counts[p])
+			} else if solutions[e][p] != solutions[0][p] {
+				detail = fmt.Sprintf("found a different solution than %s", reference.Engine)
+			}
+			if detail != "" {
+				report.Disagreements = append(report.Disagreements, Disagreement{
+					Puzzle:    p,
+					Engine:    result.Engine,
+					Reference: reference.Engine,
+					Detail:    detail,
+				})
+			}
+		}
+	}
+	return report
+}
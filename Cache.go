@@ -0,0 +1,231 @@
+/*
+This file adds an optional solution cache, so that a program solving the same puzzles over and over
+(for example a server handing out popular daily puzzles) can look up a known solution instead of searching again.
+
+Puzzles are keyed by their shape, their variant, and their canonical string: the cells (81 on a classic grid) read row by
+row, with digits kept as they are and every empty cell written as a dot. Two grids with the same clues and the same rules
+therefore always share the same key, while the same clues under other boxes or another variant never do.
+
+- `SolutionCache`: Interface implemented by every cache, so persistent storage can be plugged in.
+- `MemoryCache`: In-memory cache without a size limit, safe for concurrent use.
+- `LRUCache`: In-memory cache of bounded size, dropping the least recently used solutions first.
+- `FileCache`: Persistent cache storing one small file per puzzle in a directory.
+- `CacheKey`: Returns the key of a puzzle.
+- `CanonicalString` / `GridFromString`: Convert between grids and their canonical string.
+- `SolveCached`: Same as `SolveSudoku`, but consults and fills a cache.
+*/
+
+package sudokux
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SolutionCache maps puzzle keys (see CacheKey) to their solutions.
+type SolutionCache interface {
+	Get(key string) (map[string]rune, bool)         // Get returns the cached solution for key, if any
+	Put(key string, solution map[string]rune) error // Put stores the solution for key
+}
+
+// MemoryCache is an in-memory SolutionCache. It holds every solution it is given, so it only suits a bounded set of
+// puzzles (such as a book being solved); a program answering arbitrary puzzles should use an LRUCache instead. It is
+// safe for concurrent use.
+type MemoryCache struct {
+	mu      sync.Mutex        // Guards entries
+	entries map[string]string // Puzzle key -> canonical solution string
+}
+
+// NewMemoryCache creates an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]string)}
+}
+
+// Get returns the cached solution for key, if any.
+func (c *MemoryCache) Get(key string) (map[string]rune, bool) {
+	c.mu.Lock()
+	solution, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	grid, err := GridFromString(solution)
+	return grid, err == nil
+}
+
+// Put stores the solution for key.
+func (c *MemoryCache) Put(key string, solution map[string]rune) error {
+	c.mu.Lock()
+	c.entries[key] = CanonicalString(solution)
+	c.mu.Unlock()
+	return nil
+}
+
+// LRUCache is an in-memory SolutionCache holding at most a fixed number of solutions: once it is full, storing a
+// solution drops the one used least recently. It is safe for concurrent use.
+type LRUCache struct {
+	mu       sync.Mutex               // Guards order and entries
+	capacity int                      // Largest number of solutions held
+	order    *list.List               // Entries from the most to the least recently used
+	entries  map[string]*list.Element // Puzzle key -> its element of order
+}
+
+// lruEntry is an element of LRUCache.order.
+type lruEntry struct {
+	key      string // Puzzle key
+	solution string // Canonical solution string
+}
+
+// NewLRUCache creates an empty LRU cache holding up to capacity solutions (at least one).
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{capacity: max(capacity, 1), order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the cached solution for key, if any, and marks it as the most recently used.
+func (c *LRUCache) Get(key string) (map[string]rune, bool) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	var solution string
+	if ok {
+		c.order.MoveToFront(elem)
+		solution = elem.Value.(*lruEntry).solution
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	grid, err := GridFromString(solution)
+	return grid, err == nil
+}
+
+// Put stores the solution for key, dropping the least recently used solution if the cache is full.
+func (c *LRUCache) Put(key string, solution map[string]rune) error {
+	entry := &lruEntry{key: key, solution: CanonicalString(solution)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// Len returns the number of solutions in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// FileCache is a persistent SolutionCache that stores each solution in its own file inside Dir, named after the
+// SHA-256 hash of its key so that no key can name a file outside Dir.
+type FileCache struct {
+	Dir string // Directory holding the cached solutions
+}
+
+// NewFileCache creates a file cache in dir, creating the directory if needed.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create cache directory: %v", err)
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+// Get returns the cached solution for key, if any.
+func (c *FileCache) Get(key string) (map[string]rune, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	grid, err := GridFromString(strings.TrimSpace(string(data)))
+	return grid, err == nil
+}
+
+// Put stores the solution for key.
+func (c *FileCache) Put(key string, solution map[string]rune) error {
+	return os.WriteFile(c.path(key), []byte(CanonicalString(solution)+"\n"), 0o644)
+}
+
+// path returns the file holding the solution for key.
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// CacheKey returns the key of a puzzle in a SolutionCache: the boxes of its shape, its variant (as accepted by
+// VariantConstraints, "" being the classic rules), and its canonical string, such as "3x3:classic:53..7....6..195...".
+func CacheKey(shape Shape, variant string, grid map[string]rune) string {
+	if variant == "" {
+		variant = VariantClassic
+	}
+	return fmt.Sprintf("%dx%d:%s:%s", shape.BoxRows, shape.BoxCols, variant, CanonicalString(grid))
+}
+
+// CanonicalString returns the normalized form of a grid (81 characters for a classic grid): cells row by row,
+// digits kept as they are and anything else (empty cells, missing cells) written as '.'.
+func CanonicalString(grid map[string]rune) string {
+	shape := ShapeOf(grid)
+	var sb strings.Builder
+	for _, pos := range shape.Cells() { // Loop through all cells in row-major order
+		val := grid[pos]
+		if !shape.IsDigit(val) { // Normalize every kind of empty cell to a dot
+			val = '.'
+		}
+		sb.WriteRune(val)
+	}
+	return sb.String()
+}
+
+// GridFromString builds a grid from a string read row by row (81 characters for a classic grid, 36 for a 6x6
+// grid, 256 for a hexadoku, and so on), where digits are clues and '.' or '0' are empty cells. The grid uses the
+// default boxes for its size.
+func GridFromString(s string) (map[string]rune, error) {
+	var shape Shape
+	found := false
+	for _, candidate := range defaultShapes { // The length of the string gives the board size
+		if len(s) == candidate.Size*candidate.Size {
+			shape, found = candidate, true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("expected as many characters as a supported board has cells (81 for a classic grid), got %d", len(s))
+	}
+	grid := make(map[string]rune)
+	for k := 0; k < len(s); k++ {
+		char := rune(s[k])
+		if char == '0' { // Accept '0' as an alternative empty marker
+			char = '.'
+		}
+		if !shape.IsDigit(char) && char != '.' {
+			return nil, fmt.Errorf("invalid character %q at position %d", char, k+1)
+		}
+		grid[shape.Pos(k/shape.Size, k%shape.Size)] = char // Row letter followed by column number
+	}
+	return grid, nil
+}
+
+// SolveCached behaves like SolveSudoku, but looks the puzzle up in cache first and stores new solutions in it.
+func SolveCached(cache SolutionCache, grid map[string]rune) (map[string]rune, bool) {
+	key := CacheKey(ShapeOf(grid), VariantClassic, grid)
+	if solution, ok := cache.Get(key); ok { // A cache hit skips the search entirely
+		return solution, true
+	}
+	solution, solved := SolveSudoku(grid)
+	if solved { // Only unique solutions are worth remembering
+		cache.Put(key, solution) // A failure to store is not fatal: the solution is still returned
+	}
+	return solution, solved
+}
@@ -0,0 +1,63 @@
+package sudokux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCacheKey checks that the same clues only share a key under the same boxes and variant.
+func TestCacheKey(t *testing.T) {
+	grid, err := GridFromString(easyPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	six := make(map[string]rune)
+	for _, pos := range (Shape{Size: 6, BoxRows: 2, BoxCols: 3}).Cells() {
+		six[pos] = '.'
+	}
+	keys := map[string]string{
+		"classic":          CacheKey(Classic, VariantClassic, grid),
+		"x":                CacheKey(Classic, VariantX, grid),
+		"6x6 with 2x3":     CacheKey(Shape{Size: 6, BoxRows: 2, BoxCols: 3}, "", six),
+		"6x6 with 3x2":     CacheKey(Shape{Size: 6, BoxRows: 3, BoxCols: 2}, "", six),
+		"x and antiknight": CacheKey(Classic, "x,antiknight", grid),
+	}
+	seen := make(map[string]string)
+	for name, key := range keys {
+		if other, ok := seen[key]; ok {
+			t.Errorf("%s and %s share the key %q", name, other, key)
+		}
+		seen[key] = name
+	}
+	if got, want := CacheKey(Classic, "", grid), keys["classic"]; got != want {
+		t.Errorf("CacheKey() with no variant = %q, want %q", got, want)
+	}
+}
+
+// TestFileCache checks that a FileCache keeps every solution inside its directory, whatever the key.
+func TestFileCache(t *testing.T) {
+	root := t.TempDir()
+	cache, err := NewFileCache(filepath.Join(root, "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := GridFromString(easySolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{CacheKey(Classic, "", solution), "../escape", "/etc/escape", ""} {
+		if err := cache.Put(key, solution); err != nil {
+			t.Fatalf("Put(%q) error: %v", key, err)
+		}
+		if got, ok := cache.Get(key); !ok || CanonicalString(got) != easySolution {
+			t.Errorf("Get(%q) = %s, %v, want %s", key, CanonicalString(got), ok, easySolution)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape")); err == nil {
+		t.Error("Put(\"../escape\") wrote outside the cache directory")
+	}
+	if _, ok := cache.Get(CacheKey(Classic, VariantX, solution)); ok {
+		t.Error("Get() found the classic solution under the key of another variant")
+	}
+}
@@ -0,0 +1,59 @@
+/*
+This file exposes the candidates of a cell, the digits that the classic rules still allow there, for pencil marks
+in a UI and as a building block for logical techniques. A `CandidateSet` is a bitmask with one bit per digit, so
+sets are cheap to copy, compare, and combine with the usual bitwise operators; `Digits` lists them in order.
+
+Functions:
+- **`Candidates`**: Returns the candidates of a cell of a grid.
+- **`Has`**, **`Count`**, **`Digits`**: Test, count, and list the digits of a set.
+*/
+
+package sudokux
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// CandidateSet is a set of digits, as a bitmask where bit i stands for the digit at index i of the board's digits
+// (bit 0 for '1', bit 9 for 'A' on a 16x16 board).
+type CandidateSet uint32
+
+// Candidates returns the digits that the classic rules allow in the cell at pos: those not already used in its row,
+// column, or box. Filled cells and positions that aren't on the board have no candidates.
+func Candidates(grid map[string]rune, pos string) CandidateSet {
+	shape := ShapeOf(grid)
+	peers, ok := shape.tables().peers[pos]
+	if !ok || grid[pos] != '.' {
+		return 0
+	}
+	var used CandidateSet
+	for _, peer := range peers {
+		if i := shape.DigitIndex(grid[peer]); i >= 0 {
+			used |= 1 << i
+		}
+	}
+	return CandidateSet(1<<shape.Size-1) &^ used
+}
+
+// Has reports whether digit is in the set.
+func (set CandidateSet) Has(digit rune) bool {
+	i := strings.IndexRune(symbols, digit)
+	return i >= 0 && set&(1<<i) != 0
+}
+
+// Count returns the number of digits in the set.
+func (set CandidateSet) Count() int {
+	return bits.OnesCount32(uint32(set))
+}
+
+// Digits returns the digits of the set in ascending order.
+func (set CandidateSet) Digits() []rune {
+	digits := make([]rune, 0, set.Count())
+	for i, symbol := range symbols {
+		if set&(1<<i) != 0 {
+			digits = append(digits, symbol)
+		}
+	}
+	return digits
+}
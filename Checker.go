@@ -0,0 +1,108 @@
+/*
+This file verifies completed grids, for features such as "check my answer" where the player submits a filled grid.
+
+- `CheckSolved`: Ensures a grid is completely filled with digits and that every row, column, and subgrid
+  contains each digit exactly once.
+- `CheckAgainst`: Performs the same check on an attempt and additionally confirms that it keeps every clue
+  of the original puzzle.
+
+Both functions return nil when the grid is correct, or an error describing the first problem found.
+
+- `CheckProgress`: Checks an attempt that may not be finished yet against the unique solution of the puzzle,
+  listing the cells filled incorrectly and counting the cells left to fill.
+*/
+
+package sudokux
+
+import "fmt"
+
+// CheckSolved validates a fully filled grid against all Sudoku rules.
+func CheckSolved(grid map[string]rune) error {
+	shape := ShapeOf(grid) // Work out the board size from the grid
+
+	// Make sure every cell of the grid is filled with a digit
+	for _, pos := range shape.Cells() { // Loop through all cells in row-major order
+		val, ok := grid[pos] // Get the value at this position
+		if !ok {             // If the cell is missing from the grid
+			return fmt.Errorf("cell %s is missing", pos)
+		}
+		if val == '.' { // If the cell is still empty
+			return fmt.Errorf("cell %s is empty", pos)
+		}
+		if !shape.IsDigit(val) { // If the cell contains something other than a digit
+			return fmt.Errorf("cell %s contains invalid character %q", pos, val)
+		}
+	}
+
+	// Make sure no digit is repeated within a row, column, or subgrid
+	for _, unit := range shape.tables().units { // Loop through all units
+		seen := make(map[rune]string) // Map each digit to the first position it was seen at
+		for _, pos := range unit {
+			val := grid[pos]
+			if first, ok := seen[val]; ok { // If the digit already appeared in this unit
+				return fmt.Errorf("digit %c appears at both %s and %s", val, first, pos)
+			}
+			seen[val] = pos
+		}
+	}
+
+	return nil // The grid is a valid solution
+}
+
+// CheckAgainst validates an attempt at solving puzzle: the attempt must be a valid solved grid
+// and must keep every clue of the original puzzle.
+func CheckAgainst(puzzle, attempt map[string]rune) error {
+	if err := CheckSolved(attempt); err != nil { // The attempt must first be a valid solution on its own
+		return err
+	}
+	for _, pos := range ShapeOf(attempt).Cells() { // Loop through all cells in row-major order
+		clue := puzzle[pos]
+		if clue != '.' && clue != 0 && attempt[pos] != clue { // If a clue was changed in the attempt
+			return fmt.Errorf("cell %s should keep the clue %c, found %c", pos, clue, attempt[pos])
+		}
+	}
+	return nil // The attempt solves the puzzle
+}
+
+// ProgressReport is the outcome of CheckProgress.
+type ProgressReport struct {
+	Incorrect []string // Cells holding a digit other than the one of the solution, in row-major order
+	Correct   int      // Cells filled by the player with the digit of the solution (the clues don't count)
+	Remaining int      // Empty cells
+}
+
+// Solved reports whether the attempt is the solution.
+func (report ProgressReport) Solved() bool {
+	return len(report.Incorrect) == 0 && report.Remaining == 0
+}
+
+// CheckProgress compares an attempt at solving puzzle, finished or not, with the solution. The attempt must keep
+// every clue of the puzzle, and the puzzle must have a unique solution; an error says which is not the case.
+func CheckProgress(puzzle, attempt map[string]rune) (ProgressReport, error) {
+	shape := ShapeOf(puzzle)
+	if ShapeOf(attempt) != shape {
+		return ProgressReport{}, fmt.Errorf("the attempt is a %dx%d grid, the puzzle a %dx%d one", ShapeOf(attempt).Size, ShapeOf(attempt).Size, shape.Size, shape.Size)
+	}
+	for _, pos := range shape.Cells() {
+		if clue := puzzle[pos]; clue != '.' && attempt[pos] != clue { // If a clue was changed in the attempt
+			return ProgressReport{}, fmt.Errorf("cell %s should keep the clue %c, found %c", pos, clue, attempt[pos])
+		}
+	}
+	solution, ok := SolveSudoku(puzzle)
+	if !ok {
+		return ProgressReport{}, fmt.Errorf("the puzzle doesn't have a unique solution")
+	}
+
+	var report ProgressReport
+	for _, pos := range shape.Cells() {
+		switch {
+		case attempt[pos] == '.':
+			report.Remaining++
+		case attempt[pos] != solution[pos]:
+			report.Incorrect = append(report.Incorrect, pos)
+		case puzzle[pos] == '.':
+			report.Correct++
+		}
+	}
+	return report, nil
+}
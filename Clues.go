@@ -0,0 +1,252 @@
+/*
+This file reads clue files: the extra clues of variant puzzles, such as the cages of Killer Sudoku or the arrows
+of Arrow Sudoku, which don't fit in the rows of the grid. A clue file has one clue per line, written as a head,
+a colon, and the cells of the clue separated by spaces or commas. Blank lines and lines starting with '#' are
+ignored:
+
+	# A Killer cage: the sum, then its cells
+	15: A1 A2 B1
+	# An arrow: the circle, then the cells along the arrow
+	arrow C3: C4, C5
+	# Kropki dots: the color, then the two cells (and "kropki: all" when every dot is given)
+	white: E5 E6
+	kropki: all
+	# Greater-than signs: two side-by-side cells with '<' or '>' between them
+	F1 < F2
+	# A Little Killer clue: the sum, then the first cell of the diagonal and its direction
+	little 23: A3 SE
+	# A line: its kind, then the cells along the line in order
+	palindrome: A1 B2 C2 D3
+	whisper: E1 E2 F3
+	renban: G1 G2 H2
+	# A thermometer: the cells from the bulb to the tip
+	thermo: I1 I2 I3
+	# A quadruple: the top-left cell of the four around the circle, then the digits in the circle
+	quad B2: 1 3 7
+
+Functions:
+- **`ParseClues`**: Reads a clue file and checks every clue against the board.
+- **`Clues.Constraints`**: Returns the constraints enforcing the clues.
+*/
+
+package sudokux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Clues holds the clues read from a clue file.
+type Clues struct {
+	Cages   []Cage  // Killer cages (see Killer.go)
+	Arrows  []Arrow // Arrows (see Arrow.go)
+	Dots    []Dot   // Kropki dots (see Kropki.go)
+	AllDots bool    // Whether every Kropki dot is given (the negative constraint)
+
+	Inequalities []Inequality // Greater-than signs (see Inequality.go)
+	Diagonals    []Diagonal   // Little Killer diagonals (see LittleKiller.go)
+	Palindromes  [][]string   // Palindrome lines (see Palindrome.go)
+	Whispers     [][]string   // German Whispers lines (see Whispers.go)
+	Renbans      [][]string   // Renban lines (see Renban.go)
+	Thermos      [][]string   // Thermometers, each from its bulb (see Thermo.go)
+	Quadruples   []Quadruple  // Quadruple circles (see Quadruple.go)
+}
+
+// ParseClues reads a clue file from r and checks every clue against the board of the given shape.
+func ParseClues(r io.Reader, shape Shape) (*Clues, error) {
+	clues := &Clues{}
+	owner := make(map[string]int)     // Line number of the cage each cell belongs to
+	dotted := make(map[[2]string]int) // Line number of the dot between each pair of cells
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") { // Skip blank lines and comments
+			continue
+		}
+		if inequality, isSign, err := parseInequality(text, shape); isSign { // A sign has no colon
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Inequalities = append(clues.Inequalities, inequality)
+			continue
+		}
+		head, cellsText, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"sum: cells\" or another clue, got %q", line, text)
+		}
+		if strings.TrimSpace(head) == "kropki" && strings.TrimSpace(cellsText) == "all" { // The negative constraint
+			clues.AllDots = true
+			continue
+		}
+		if words := strings.Fields(head); len(words) == 2 && words[0] == "little" { // A diagonal, not a list of cells
+			diagonal, err := parseDiagonal(words[1], cellsText, shape)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Diagonals = append(clues.Diagonals, diagonal)
+			continue
+		}
+		if words := strings.Fields(head); len(words) == 2 && words[0] == "quad" { // Digits, not a list of cells
+			quad, err := parseQuadruple(words[1], cellsText, shape)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Quadruples = append(clues.Quadruples, quad)
+			continue
+		}
+		cells, err := parseCells(cellsText, shape)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		words := strings.Fields(head)
+		switch {
+		case len(words) == 1 && isNumber(words[0]): // A Killer cage
+			sum, _ := strconv.Atoi(words[0])
+			for _, pos := range cells {
+				if other, taken := owner[pos]; taken {
+					return nil, fmt.Errorf("line %d: cell %s is already in the cage on line %d", line, pos, other)
+				}
+				owner[pos] = line
+			}
+			if err := checkCage(Cage{Sum: sum, Cells: cells}, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Cages = append(clues.Cages, Cage{Sum: sum, Cells: cells})
+		case len(words) == 2 && words[0] == "arrow": // An arrow, from its circle
+			arrow := Arrow{Circle: words[1], Cells: cells}
+			if err := checkArrow(arrow, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Arrows = append(clues.Arrows, arrow)
+		case len(words) == 1 && (words[0] == DotWhite || words[0] == DotBlack): // A Kropki dot
+			if len(cells) != 2 {
+				return nil, fmt.Errorf("line %d: a dot goes between 2 cells, got %d", line, len(cells))
+			}
+			dot := Dot{Color: words[0], A: cells[0], B: cells[1]}
+			if err := checkDot(dot, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if other, taken := dotted[[2]string{dot.A, dot.B}]; taken {
+				return nil, fmt.Errorf("line %d: there is already a dot between %s and %s on line %d", line, dot.A, dot.B, other)
+			}
+			dotted[[2]string{dot.A, dot.B}], dotted[[2]string{dot.B, dot.A}] = line, line
+			clues.Dots = append(clues.Dots, dot)
+		case len(words) == 1 && words[0] == "palindrome": // A palindrome line
+			if err := checkLine(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Palindromes = append(clues.Palindromes, cells)
+		case len(words) == 1 && words[0] == "whisper": // A German Whispers line
+			if err := checkLine(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Whispers = append(clues.Whispers, cells)
+		case len(words) == 1 && words[0] == "renban": // A Renban line
+			if err := checkLine(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if err := checkRenban(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Renbans = append(clues.Renbans, cells)
+		case len(words) == 1 && words[0] == "thermo": // A thermometer, from its bulb
+			if err := checkLine(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			if err := checkThermo(cells, shape); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			clues.Thermos = append(clues.Thermos, cells)
+		default:
+			return nil, fmt.Errorf("line %d: unknown clue %q", line, strings.TrimSpace(head))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return clues, nil
+}
+
+// Constraints returns the constraints enforcing the clues on a board of the given shape, to be added to the
+// rules of the puzzle.
+func (clues *Clues) Constraints(shape Shape) []Constraint {
+	var constraints []Constraint
+	if len(clues.Cages) > 0 {
+		constraints = append(constraints, NewKillerConstraint(shape, clues.Cages))
+	}
+	if len(clues.Arrows) > 0 {
+		constraints = append(constraints, NewArrowConstraint(shape, clues.Arrows))
+	}
+	if len(clues.Dots) > 0 || clues.AllDots {
+		constraints = append(constraints, NewKropkiConstraint(shape, clues.Dots, clues.AllDots))
+	}
+	if len(clues.Inequalities) > 0 {
+		constraints = append(constraints, NewInequalityConstraint(shape, clues.Inequalities))
+	}
+	if len(clues.Diagonals) > 0 {
+		constraints = append(constraints, NewLittleKillerConstraint(shape, clues.Diagonals))
+	}
+	if len(clues.Palindromes) > 0 {
+		constraints = append(constraints, NewPalindromeConstraint(shape, clues.Palindromes))
+	}
+	if len(clues.Whispers) > 0 {
+		constraints = append(constraints, NewWhispersConstraint(shape, clues.Whispers))
+	}
+	if len(clues.Renbans) > 0 {
+		constraints = append(constraints, NewRenbanConstraint(shape, clues.Renbans))
+	}
+	if len(clues.Quadruples) > 0 {
+		constraints = append(constraints, NewQuadrupleConstraint(shape, clues.Quadruples))
+	}
+	if len(clues.Thermos) > 0 {
+		constraints = append(constraints, NewThermoConstraint(shape, clues.Thermos))
+	}
+	return constraints
+}
+
+// parseCells reads the cells of a clue, separated by spaces or commas, and checks that they are on the board and
+// appear only once.
+func parseCells(text string, shape Shape) ([]string, error) {
+	var cells []string
+	seen := make(map[string]bool)
+	for _, pos := range strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' }) {
+		if _, _, ok := shape.ParsePos(pos); !ok {
+			return nil, fmt.Errorf("%s is not a cell of the board", pos)
+		}
+		if seen[pos] {
+			return nil, fmt.Errorf("cell %s is listed twice", pos)
+		}
+		seen[pos] = true
+		cells = append(cells, pos)
+	}
+	if len(cells) == 0 {
+		return nil, fmt.Errorf("the clue has no cells")
+	}
+	return cells, nil
+}
+
+// checkLine checks that the cells of a line follow each other, each touching the next one orthogonally or
+// diagonally, and that the line has at least 2 cells.
+func checkLine(cells []string, shape Shape) error {
+	if len(cells) < 2 {
+		return fmt.Errorf("a line needs at least 2 cells, got %d", len(cells))
+	}
+	for i := 1; i < len(cells); i++ {
+		row, col, _ := shape.ParsePos(cells[i-1])
+		nextRow, nextCol, _ := shape.ParsePos(cells[i])
+		if abs(row-nextRow) > 1 || abs(col-nextCol) > 1 {
+			return fmt.Errorf("the cells %s and %s of the line must touch", cells[i-1], cells[i])
+		}
+	}
+	return nil
+}
+
+// isNumber reports whether s is made of decimal digits only.
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil && !strings.ContainsAny(s, "+-")
+}
@@ -0,0 +1,120 @@
+/*
+This file defines the `Constraint` interface, which the solver consults instead of hardcoding the row, column,
+and subgrid rules. Every rule of a puzzle is a constraint: the classic rules are three `RegionConstraint`s
+(rows, columns, and subgrids), and variants such as diagonals, killer cages, or anti-knight add their own.
+
+A constraint answers three questions:
+1. **`Allows`**: Can a digit be placed in a cell, given the rest of the grid?
+2. **`Peers`**: Which cells can a placement in a given cell affect? The solver only re-checks these cells
+   after each placement, which keeps candidate updates incremental.
+3. **`Eliminate`**: Are there candidates the constraint can rule out by looking at the grid as a whole?
+   This hook lets constraints prune more than single placement checks can (for example sums or adjacency rules).
+
+Functions:
+- **`ClassicConstraints`**: Returns the standard row, column, and subgrid rules of a 9x9 grid.
+- **`ClassicConstraintsFor`**: Returns the same rules for a board of any supported shape.
+- **`NewRegionConstraint`**: Builds a constraint where no digit may repeat within each of the given regions.
+*/
+
+package sudokux
+
+import "slices"
+
+// Constraint is a rule that placements must respect. A constraint must not change after it is created,
+// so that the same value can be shared by searches running in different goroutines.
+type Constraint interface {
+	// Name returns a short, human-readable name for the constraint (e.g. "rows").
+	Name() string
+	// Allows reports whether digit may be placed at pos, given the other cells of grid.
+	Allows(grid map[string]rune, pos string, digit rune) bool
+	// Peers returns the cells whose candidates may change when the content of pos changes.
+	Peers(pos string) []string
+	// Eliminate looks at the whole grid and returns candidates that can be removed. It returns false
+	// if the constraint can no longer be satisfied. The eliminations must not depend on map iteration order,
+	// so that searches stay deterministic.
+	Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool)
+}
+
+// Elimination is a candidate digit that can be removed from a cell.
+type Elimination struct {
+	Pos   string // Position of the cell (e.g. "A1")
+	Digit rune   // Digit that can no longer be placed there
+}
+
+// RegionConstraint requires every digit to appear at most once within each of its regions.
+type RegionConstraint struct {
+	name    string              // Name of the constraint
+	regions [][]string          // The cells of each region
+	byCell  map[string][]int    // Indexes of the regions containing each cell
+	peers   map[string][]string // Cells sharing at least one region with each cell
+}
+
+// NewRegionConstraint creates a constraint where no digit may repeat within each of the given regions.
+func NewRegionConstraint(name string, regions [][]string) *RegionConstraint {
+	c := &RegionConstraint{
+		name:    name,
+		regions: regions,
+		byCell:  make(map[string][]int),
+		peers:   make(map[string][]string),
+	}
+	for index, region := range regions {
+		for _, pos := range region {
+			c.byCell[pos] = append(c.byCell[pos], index) // Remember which regions each cell belongs to
+		}
+	}
+	for pos, indexes := range c.byCell {
+		seen := map[string]bool{pos: true} // A cell is not its own peer
+		for _, index := range indexes {
+			for _, other := range regions[index] {
+				if !seen[other] {
+					seen[other] = true
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *RegionConstraint) Name() string {
+	return c.name
+}
+
+// Regions returns the cells of each region.
+func (c *RegionConstraint) Regions() [][]string {
+	return c.regions
+}
+
+// Allows reports whether digit is absent from every region containing pos.
+func (c *RegionConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	for _, index := range c.byCell[pos] { // Only the regions containing the cell matter
+		for _, other := range c.regions[index] {
+			if other != pos && grid[other] == digit { // The digit is already used in this region
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Peers returns the cells sharing a region with pos, in the order of the regions and of their cells.
+func (c *RegionConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate has nothing to add for plain regions: Allows already rules out every repeated digit.
+func (c *RegionConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	return nil, true
+}
+
+// ClassicConstraints returns the standard Sudoku rules: no repeated digit in any row, column, or 3x3 subgrid.
+func ClassicConstraints() []Constraint {
+	return ClassicConstraintsFor(Classic)
+}
+
+// ClassicConstraintsFor returns the standard rules for a board of the given shape: no repeated digit in any
+// row, column, or box.
+func ClassicConstraintsFor(shape Shape) []Constraint {
+	return slices.Clone(shape.tables().classic) // Constraints never change, so every search can share them
+}
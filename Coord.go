@@ -0,0 +1,69 @@
+/*
+This file defines `Coord`, the row and column of a cell, so that callers can move between the ways a cell is named
+without building position strings by hand:
+- **Position strings** such as "A1" (row letter, column number), the keys of a `Grid`: `ParseCoord` and `String`.
+- **Row and column indexes** counted from 0: `CoordAt`, and the fields of `Coord`.
+- **Linear indexes** in row-major order, from 0 to 80 on a classic grid: `CoordOfIndex` and `Index`.
+
+The boxes and peers of a cell depend on the board, so `Box` and `Peers` take its shape, like the rest of Shape.go.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Coord is the row and column of a cell, both counted from 0.
+type Coord struct {
+	Row, Col int
+}
+
+// CoordAt returns the coordinates of the cell at the given row and column.
+func CoordAt(row, col int) Coord {
+	return Coord{Row: row, Col: col}
+}
+
+// ParseCoord returns the coordinates of a position string such as "A1", or "P16" on a 16x16 board.
+func ParseCoord(pos string) (Coord, error) {
+	row, col, ok := Shape{Size: len(symbols)}.ParsePos(pos) // Checked against the largest board
+	if !ok {
+		return Coord{}, fmt.Errorf("invalid position %q (expected a row letter and a column number, such as A1)", pos)
+	}
+	return Coord{Row: row, Col: col}, nil
+}
+
+// CoordOfIndex returns the coordinates of the cell at the given index in row-major order on a board of the given
+// shape (0 to 80 on a classic grid).
+func CoordOfIndex(index int, shape Shape) Coord {
+	return Coord{Row: index / shape.Size, Col: index % shape.Size}
+}
+
+// String returns the position string of the cell, such as "A1", which is its key in a Grid.
+func (c Coord) String() string {
+	return Shape{}.Pos(c.Row, c.Col)
+}
+
+// Index returns the index of the cell in row-major order on a board of the given shape.
+func (c Coord) Index(shape Shape) int {
+	return c.Row*shape.Size + c.Col
+}
+
+// In reports whether the cell is on a board of the given shape.
+func (c Coord) In(shape Shape) bool {
+	return c.Row >= 0 && c.Row < shape.Size && c.Col >= 0 && c.Col < shape.Size
+}
+
+// Box returns the index of the box of the cell on a board of the given shape, numbering boxes in row-major order.
+func (c Coord) Box(shape Shape) int {
+	return shape.BoxOf(c.Row, c.Col)
+}
+
+// Peers returns the cells sharing a row, column, or box with the cell on a board of the given shape, in row-major
+// order.
+func (c Coord) Peers(shape Shape) []Coord {
+	var peers []Coord
+	for _, peer := range shape.tables().peers[c.String()] {
+		row, col, _ := shape.ParsePos(peer)
+		peers = append(peers, Coord{Row: row, Col: col})
+	}
+	return peers
+}
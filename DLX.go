@@ -0,0 +1,237 @@
+/*
+This file implements a second, independent solving engine: Knuth's Algorithm X with Dancing Links (DLX). Sudoku is
+an exact cover problem: every candidate placement ("digit d in cell r,c") is a row of a 0/1 matrix, and every rule
+is a column that exactly one chosen row must cover. On a board of size n there are 4·n² columns:
+1. **Cell**: every cell holds exactly one digit.
+2. **Row**: every digit appears exactly once in every row.
+3. **Column**: every digit appears exactly once in every column.
+4. **Box**: every digit appears exactly once in every box.
+
+The matrix is stored as circular doubly linked lists in flat slices, so that covering a column (removing it and
+every row that conflicts with it) and uncovering it again are a handful of pointer updates. The search always
+branches on the column with the fewest rows left, which is the MRV heuristic of Search.go in another guise.
+
+Because it shares no code with the backtracking search, comparing the two (see Bench.go) is a check on both.
+It only knows the classic rules, for any board shape.
+
+- `DLXEngine`: The engine, named "dlx".
+*/
+
+package sudokux
+
+// DLXEngine solves puzzles under the classic rules with Dancing Links.
+type DLXEngine struct{}
+
+// Name returns "dlx".
+func (DLXEngine) Name() string {
+	return "dlx"
+}
+
+// Solve searches for up to two solutions of grid.
+func (e DLXEngine) Solve(grid map[string]rune) (map[string]rune, int, int) {
+	solution, count, nodes, _ := e.SolveUntil(grid, nil)
+	return solution, count, nodes
+}
+
+// SolveUntil searches for up to two solutions of grid, giving up once stop returns true (nil never stops).
+func (DLXEngine) SolveUntil(grid map[string]rune, stop func() bool) (map[string]rune, int, int, bool) {
+	shape := ShapeOf(grid)
+	n := shape.Size
+	d := sudokuMatrix(grid, shape)
+	if d == nil {
+		return nil, 0, 0, true
+	}
+	d.stop = stop
+
+	var first []int // Rows of the first solution found
+	count := 0
+	d.search(func(rows []int) bool {
+		if count == 0 {
+			first = append([]int(nil), rows...)
+		}
+		count++
+		return count < 2 // Two solutions are enough to tell unique puzzles apart
+	})
+	if count == 0 {
+		return nil, 0, d.nodes, !d.stopped
+	}
+	solution := make(map[string]rune)
+	copyGrid(grid, solution)
+	digits := shape.Digits()
+	for _, row := range first {
+		cell, digit := row/n, row%n
+		solution[shape.Pos(cell/n, cell%n)] = digits[digit]
+	}
+	return solution, count, d.nodes, !d.stopped
+}
+
+// Count returns the number of solutions of grid, stopping at limit (0 means no limit).
+func (DLXEngine) Count(grid map[string]rune, limit int) int {
+	d := sudokuMatrix(grid, ShapeOf(grid))
+	if d == nil {
+		return 0
+	}
+	count := 0
+	d.search(func([]int) bool {
+		count++
+		return limit <= 0 || count < limit
+	})
+	return count
+}
+
+// sudokuMatrix returns the exact cover matrix of grid under the classic rules, with its givens already chosen, or
+// nil if two givens conflict.
+func sudokuMatrix(grid map[string]rune, shape Shape) *dancingLinks {
+	n := shape.Size
+	d := newDancingLinks(4*n*n, n*n*n)
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			for digit := 0; digit < n; digit++ {
+				d.addRow([]int{
+					r*n + c,                             // Cell
+					n*n + r*n + digit,                   // Digit in row
+					2*n*n + c*n + digit,                 // Digit in column
+					3*n*n + shape.BoxOf(r, c)*n + digit, // Digit in box
+				})
+			}
+		}
+	}
+	for r := 0; r < n; r++ { // The givens are chosen before the search starts
+		for c := 0; c < n; c++ {
+			digit := shape.DigitIndex(grid[shape.Pos(r, c)])
+			if digit < 0 {
+				continue
+			}
+			if !d.choose((r*n+c)*n + digit) { // The given conflicts with another one
+				return nil
+			}
+		}
+	}
+	return d
+}
+
+// dancingLinks is a sparse 0/1 matrix for Algorithm X. Node 0 is the root, nodes 1 to the number of columns are
+// the column headers, and the nodes of the rows follow.
+type dancingLinks struct {
+	left, right, up, down []int // Neighbors of every node in its row and column lists
+	column                []int // Header of the column of every node
+	row                   []int // Row of every node (-1 for the root and the headers)
+	size                  []int // Number of rows left in every column, by header
+	first                 []int // First node of every row
+	covered               []bool
+	chosen                []int       // Rows chosen so far, givens first
+	nodes                 int         // Number of rows tried by the search
+	stop                  func() bool // Called at the first node and every stopCheck nodes after, to give up when it returns true
+	stopped               bool        // Whether stop made the search give up
+}
+
+// newDancingLinks returns a matrix with the given number of columns and room for the given number of rows.
+func newDancingLinks(columns, rows int) *dancingLinks {
+	d := &dancingLinks{size: make([]int, columns+1), covered: make([]bool, columns+1)}
+	for i := 0; i <= columns; i++ { // The root and the headers, linked in a circle
+		d.left = append(d.left, (i+columns)%(columns+1))
+		d.right = append(d.right, (i+1)%(columns+1))
+		d.up = append(d.up, i)
+		d.down = append(d.down, i)
+		d.column = append(d.column, i)
+		d.row = append(d.row, -1)
+	}
+	d.first = make([]int, 0, rows)
+	return d
+}
+
+// addRow appends a row with a 1 in each of the given columns, counted from 0.
+func (d *dancingLinks) addRow(columns []int) {
+	start := len(d.left)
+	for i, col := range columns {
+		node, header := start+i, col+1
+		d.left = append(d.left, start+(i+len(columns)-1)%len(columns))
+		d.right = append(d.right, start+(i+1)%len(columns))
+		d.up = append(d.up, d.up[header])
+		d.down = append(d.down, header)
+		d.down[d.up[header]] = node
+		d.up[header] = node
+		d.column = append(d.column, header)
+		d.row = append(d.row, len(d.first))
+		d.size[header]++
+	}
+	d.first = append(d.first, start)
+}
+
+// cover removes the column from the header list, and every row with a 1 in it from the other columns.
+func (d *dancingLinks) cover(col int) {
+	d.covered[col] = true
+	d.right[d.left[col]], d.left[d.right[col]] = d.right[col], d.left[col]
+	for i := d.down[col]; i != col; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]], d.up[d.down[j]] = d.down[j], d.up[j]
+			d.size[d.column[j]]--
+		}
+	}
+}
+
+// uncover undoes cover, in the reverse order.
+func (d *dancingLinks) uncover(col int) {
+	for i := d.up[col]; i != col; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.column[j]]++
+			d.down[d.up[j]], d.up[d.down[j]] = j, j
+		}
+	}
+	d.right[d.left[col]], d.left[d.right[col]] = col, col
+	d.covered[col] = false
+}
+
+// choose chooses a row for good, covering its columns. It returns false if one of them is already covered.
+func (d *dancingLinks) choose(row int) bool {
+	node := d.first[row]
+	for j := node; ; {
+		if d.covered[d.column[j]] {
+			return false
+		}
+		if j = d.right[j]; j == node {
+			break
+		}
+	}
+	for j := node; ; {
+		d.cover(d.column[j])
+		if j = d.right[j]; j == node {
+			break
+		}
+	}
+	d.chosen = append(d.chosen, row)
+	return true
+}
+
+// search looks for every way to cover the columns left, calling found with the rows of each solution until it
+// returns false, or until d.stop does. It reports whether the search should go on.
+func (d *dancingLinks) search(found func(rows []int) bool) bool {
+	if d.right[0] == 0 { // Every column is covered
+		return found(d.chosen)
+	}
+	best := d.right[0] // The column with the fewest rows left
+	for col := d.right[best]; col != 0; col = d.right[col] {
+		if d.size[col] < d.size[best] {
+			best = col
+		}
+	}
+	more := true
+	d.cover(best)
+	for i := d.down[best]; i != best && more; i = d.down[i] {
+		if d.nodes++; d.stop != nil && d.nodes%stopCheck == 1 && d.stop() {
+			d.stopped, more = true, false
+			break
+		}
+		d.chosen = append(d.chosen, d.row[i])
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.cover(d.column[j])
+		}
+		more = d.search(found)
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.uncover(d.column[j])
+		}
+		d.chosen = d.chosen[:len(d.chosen)-1]
+	}
+	d.uncover(best)
+	return more
+}
@@ -0,0 +1,105 @@
+/*
+This file explains why a puzzle has no solution, so that bad input can be diagnosed instead of silently failing.
+
+A contradiction is reported at the first of these levels where it shows up:
+1. **Conflicting clues**: two clues with the same digit share a row, column, or subgrid.
+2. **Logical dead end**: after the deductions of Logic.go, an empty cell has no candidate left, or a unit has
+   no place left for a digit it is missing.
+3. **Search dead end**: logic gets stuck without a visible contradiction, but every guess fails.
+
+Functions:
+- **`Diagnose`**: Returns the contradiction explaining why a grid has no solution, or nil if it has one.
+- **`findClueConflict`**: Finds the first pair of conflicting clues, in a fixed order.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Contradiction explains why a grid has no solution.
+type Contradiction struct {
+	Cells  []string // Cells involved in the contradiction
+	Unit   string   // Unit involved (e.g. "row A"), if any
+	Digit  rune     // Digit involved, if any
+	Reason string   // Human-readable explanation
+}
+
+// Error returns the explanation, so a contradiction can be used as an error.
+func (c *Contradiction) Error() string {
+	return c.Reason
+}
+
+// Diagnose explains why grid has no solution. It returns nil if the grid has at least one solution.
+func Diagnose(grid map[string]rune) *Contradiction {
+	// 1. Two clues that conflict with each other
+	if contradiction := findClueConflict(grid, ShapeOf(grid)); contradiction != nil {
+		return contradiction
+	}
+
+	// 2. A dead end reached by logic alone
+	work, deductions, solved := SolveLogically(grid)
+	if solved { // Logic completed the grid, so it has a solution
+		return nil
+	}
+	shape := ShapeOf(grid)
+	candidates := computeCandidates(work)
+	for _, pos := range shape.Cells() { // Look for an empty cell without candidates, in row-major order
+		if options, empty := candidates[pos]; empty && len(options) == 0 {
+			return &Contradiction{
+				Cells: []string{pos},
+				Reason: fmt.Sprintf("after %d logical deduction(s), cell %s has no candidates left: every digit is already used in its row, column, or subgrid",
+					len(deductions), pos),
+			}
+		}
+	}
+	for u, unit := range shape.tables().units { // Look for a unit with no room left for one of its digits
+		for _, num := range shape.Digits() {
+			possible := false
+			for _, pos := range unit {
+				if work[pos] == num || containsRune(candidates[pos], num) {
+					possible = true
+					break
+				}
+			}
+			if !possible {
+				return &Contradiction{
+					Cells: unit,
+					Unit:  shape.UnitName(u),
+					Digit: num,
+					Reason: fmt.Sprintf("after %d logical deduction(s), %c cannot be placed anywhere in %s",
+						len(deductions), num, shape.UnitName(u)),
+				}
+			}
+		}
+	}
+
+	// 3. No visible contradiction: only the search can tell
+	if IsSolvable(grid) {
+		return nil
+	}
+	state := NewSearchState(work, 1) // The cell the search would guess first, from where logic got stuck
+	pos := state.nextCell()
+	return &Contradiction{
+		Cells:  []string{pos},
+		Reason: fmt.Sprintf("no solution: logic gets stuck after %d deduction(s), and every digit tried at %s eventually leads to a dead end", len(deductions), pos),
+	}
+}
+
+// findClueConflict returns the first pair of clues sharing a digit within a unit of shape, scanning units in the
+// order of Shape.Units() (rows, then columns, then subgrids) so the reported pair is always the same.
+func findClueConflict(grid map[string]rune, shape Shape) *Contradiction {
+	if conflicts := clueConflicts(grid, shape); len(conflicts) > 0 {
+		return &conflicts[0]
+	}
+	return nil
+}
+
+// containsRune reports whether num is among digits.
+func containsRune(digits []rune, num rune) bool {
+	for _, d := range digits {
+		if d == num {
+			return true
+		}
+	}
+	return false
+}
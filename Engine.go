@@ -0,0 +1,160 @@
+/*
+This file defines the `Engine` interface, a common shape for solving backends so they can be swapped and
+compared against each other (see Bench.go). An engine searches for up to two solutions, which is enough to
+tell whether a puzzle has no solution, exactly one, or several, and reports how much work it did; it can also
+count the solutions of a puzzle up to any limit. Engines that can give up partway implement `StoppableEngine` too,
+so that `Solve` stops them at its timeout rather than leaving them running in the background.
+
+Engines are kept in a registry, by name, so that library callers and the `--engine` flag of the program pick
+them the same way. The engines of this package are registered from the start, and other packages can add theirs
+with `RegisterEngine`, as database/sql drivers do.
+
+- `StoppableEngine`: An engine whose search can be stopped, which every engine of this package is.
+- `BacktrackingEngine`: The backtracking search of Search.go, with a configurable cell-selection heuristic.
+- `DLXEngine`: Dancing Links (see DLX.go).
+- `SATEngine`: A boolean satisfiability solver (see SAT.go).
+- `RegisterEngine`: Adds an engine to the registry.
+- `Engines`: The registered engines, in the order they were registered.
+- `DefaultEngines`: The engines available in this package, in a fixed order.
+- `EngineByName`: The registered engine with a given name.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Engine is a solving backend.
+type Engine interface {
+	// Name returns a short name identifying the engine (e.g. "backtrack").
+	Name() string
+	// Solve searches for up to two solutions of grid. It returns the first solution found, the number of
+	// solutions found (0, 1 or 2), and the number of search nodes visited. The grid is not modified.
+	Solve(grid map[string]rune) (solution map[string]rune, count int, nodes int)
+	// Count returns the number of solutions of grid, stopping at limit (0 means no limit). The grid is not
+	// modified.
+	Count(grid map[string]rune, limit int) int
+}
+
+// StoppableEngine is an engine whose search can be stopped partway.
+type StoppableEngine interface {
+	Engine
+	// SolveUntil searches as Solve does, calling stop at its first node and every stopCheck nodes or so after,
+	// and giving up as soon as it returns true. finished is false if it gave up, in which case count only tells
+	// the solutions found so far.
+	SolveUntil(grid map[string]rune, stop func() bool) (solution map[string]rune, count int, nodes int, finished bool)
+}
+
+// stopCheck is about how many nodes the engines visit between two calls of the stop function of SolveUntil.
+const stopCheck = 1000
+
+// BacktrackingEngine solves puzzles with the backtracking search, choosing cells with the given heuristic.
+type BacktrackingEngine struct {
+	Heuristic string // HeuristicMRV (the default when empty) or HeuristicFirst
+}
+
+// Name returns "backtrack" for the default MRV heuristic, or "backtrack-<heuristic>" otherwise.
+func (e BacktrackingEngine) Name() string {
+	if e.Heuristic == "" || e.Heuristic == HeuristicMRV {
+		return "backtrack"
+	}
+	return "backtrack-" + e.Heuristic
+}
+
+// Solve searches for up to two solutions of grid.
+func (e BacktrackingEngine) Solve(grid map[string]rune) (map[string]rune, int, int) {
+	solution, count, nodes, _ := e.SolveUntil(grid, nil)
+	return solution, count, nodes
+}
+
+// SolveUntil searches for up to two solutions of grid, giving up once stop returns true (nil never stops).
+func (e BacktrackingEngine) SolveUntil(grid map[string]rune, stop func() bool) (map[string]rune, int, int, bool) {
+	state := NewSearchState(grid, 2) // Two solutions are enough to tell unique puzzles apart
+	state.Heuristic = e.Heuristic
+	finished := true
+	if stop == nil {
+		state.Run(0)
+	} else {
+		for {
+			if stop() {
+				finished = false
+				break
+			}
+			if state.Run(stopCheck) {
+				break
+			}
+		}
+	}
+	if len(state.Solutions) == 0 {
+		return nil, 0, state.Nodes, finished
+	}
+	return state.Solutions[0], len(state.Solutions), state.Nodes, finished
+}
+
+// Count returns the number of solutions of grid, stopping at limit (0 means no limit).
+func (e BacktrackingEngine) Count(grid map[string]rune, limit int) int {
+	state := NewSearchState(grid, limit)
+	state.Heuristic = e.Heuristic
+	state.Run(0)
+	return len(state.Solutions)
+}
+
+// engines is the registry of RegisterEngine, in registration order.
+var engines struct {
+	sync.RWMutex
+	list []Engine
+}
+
+func init() {
+	for _, engine := range DefaultEngines() {
+		RegisterEngine(engine)
+	}
+}
+
+// RegisterEngine makes engine available by its name to EngineByName and the programs built on this package. It
+// panics if the name is empty or taken by another engine, which is a programming error.
+func RegisterEngine(engine Engine) {
+	engines.Lock()
+	defer engines.Unlock()
+	if engine.Name() == "" {
+		panic("sudokux: RegisterEngine with an empty name")
+	}
+	for _, other := range engines.list {
+		if other.Name() == engine.Name() {
+			panic("sudokux: RegisterEngine called twice for engine " + engine.Name())
+		}
+	}
+	engines.list = append(engines.list, engine)
+}
+
+// Engines returns the registered engines, starting with those of DefaultEngines.
+func Engines() []Engine {
+	engines.RLock()
+	defer engines.RUnlock()
+	return append([]Engine(nil), engines.list...)
+}
+
+// DefaultEngines returns one instance of every engine in this package.
+func DefaultEngines() []Engine {
+	return []Engine{
+		BacktrackingEngine{Heuristic: HeuristicMRV},
+		BacktrackingEngine{Heuristic: HeuristicFirst},
+		DLXEngine{},
+		SATEngine{},
+	}
+}
+
+// EngineByName returns the registered engine with the given name (such as "dlx").
+func EngineByName(name string) (Engine, error) {
+	var names []string
+	for _, engine := range Engines() {
+		if engine.Name() == name {
+			return engine, nil
+		}
+		names = append(names, engine.Name())
+	}
+	return nil, fmt.Errorf("unknown engine %q (expected %s)", name, strings.Join(names, ", "))
+}
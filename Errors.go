@@ -0,0 +1,17 @@
+/*
+This file defines the errors returned for the possible outcomes of solving a puzzle, so that programs can tell them
+apart with `errors.Is` rather than by matching messages. Errors about a particular puzzle wrap one of them with the
+details, such as the clues that conflict or how long the search ran.
+*/
+
+package sudokux
+
+import "errors"
+
+// Outcomes of solving a puzzle other than a unique solution.
+var (
+	ErrNoSolution        = errors.New("no solution")                           // The clues can't be completed
+	ErrMultipleSolutions = errors.New("the puzzle has more than one solution") // The clues can be completed in several ways
+	ErrInvalidGrid       = errors.New("invalid grid")                          // The grid breaks a rule before solving even starts
+	ErrTimeout           = errors.New("the search timed out")                  // The search gave up before finding an answer
+)
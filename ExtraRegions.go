@@ -0,0 +1,66 @@
+/*
+This file lets users add their own no-repeat regions to the rules, so that variants without dedicated support can
+still be solved. The regions are read from a JSON file listing the cells of each region, along with an optional
+name used in error messages:
+
+	{
+	  "name": "argyle",
+	  "regions": [
+	    ["A2", "B3", "C4", "D5", "E6", "F7", "G8", "H9"],
+	    ["A5", "B4", "C3", "D2", "E1"]
+	  ]
+	}
+
+A region may have any number of cells up to the number of digits, and need not be connected. Each region must hold
+distinct digits, but it doesn't have to contain every digit.
+
+Functions:
+- **`ParseExtraRegions`**: Reads a region file and returns the constraint for its regions.
+*/
+
+package sudokux
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// extraRegionsFile is the layout of a region file.
+type extraRegionsFile struct {
+	Name    string     `json:"name"`    // Name of the regions (e.g. "argyle"), or "" for "extra regions"
+	Regions [][]string `json:"regions"` // The cells of each region
+}
+
+// ParseExtraRegions reads a region file from r and checks that every region fits a board of the given shape. It
+// returns a constraint requiring the digits of each region to be distinct.
+func ParseExtraRegions(r io.Reader, shape Shape) (*RegionConstraint, error) {
+	var file extraRegionsFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid region file: %v", err)
+	}
+	if len(file.Regions) == 0 {
+		return nil, fmt.Errorf("the region file has no regions")
+	}
+	if file.Name == "" {
+		file.Name = "extra regions"
+	}
+	for i, region := range file.Regions {
+		if len(region) > shape.Size {
+			return nil, fmt.Errorf("region %d has %d cells, more than the %d digits", i+1, len(region), shape.Size)
+		}
+		seen := make(map[string]bool)
+		for _, pos := range region {
+			if _, _, ok := shape.ParsePos(pos); !ok {
+				return nil, fmt.Errorf("region %d: %s is not a cell of the board", i+1, pos)
+			}
+			if seen[pos] {
+				return nil, fmt.Errorf("region %d: cell %s is listed twice", i+1, pos)
+			}
+			seen[pos] = true
+		}
+	}
+	return NewRegionConstraint(file.Name, file.Regions), nil
+}
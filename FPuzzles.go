@@ -0,0 +1,631 @@
+/*
+This file reads and writes the JSON format of f-puzzles, which SudokuPad opens too, so that puzzles move between
+the solver and the tools setters and solvers use. A puzzle holds the size of the board, its cells, its global rules,
+and lists of clues, with cells written "R1C1" (row 1, column 1):
+
+	{
+	  "size": 9,
+	  "title": "Thermo Killer",
+	  "grid": [[{"value": 5, "given": true}, {}, ...], ...],
+	  "antiknight": true,
+	  "killercage": [{"cells": ["R1C1", "R1C2"], "value": "10"}],
+	  "thermometer": [{"lines": [["R2C1", "R2C2", "R2C3"]]}]
+	}
+
+The links of f-puzzles ("?load=...") and SudokuPad ("/fpuzzles...") carry the same JSON, compressed with lz-string
+(see LZString.go); ParseFPuzzles reads them as well.
+
+What the solver knows of the format:
+- **Global rules**: "diagonal+" and "diagonal-" (one diagonal alone becomes an extra region), "antiknight",
+  "antiking", "disjointgroups", and "nonconsecutive" (see Variant.go).
+- **Regions**: The "region" of the cells, for Jigsaw puzzles (see Jigsaw.go), and "extraregion".
+- **Clues**: "killercage", "arrow", "thermometer", "palindrome", "renban", "whispers", "littlekillersum",
+  "quadruple", "difference" and "ratio" (white and black Kropki dots, with "negative" for the negative
+  constraint).
+
+Other constraints, such as sandwich sums or XV, are listed in FPuzzle.Unsupported and left out of the rules, so a
+puzzle that needs them may have several solutions here. Cosmetic markings (text, lines, shapes) are dropped.
+
+Functions:
+- **`ParseFPuzzles`**: Reads a puzzle in the format of f-puzzles, as JSON or as a link.
+- **`FPuzzle.Constraints`**: Returns the rules of a puzzle read by ParseFPuzzles.
+- **`MarshalFPuzzles`**: Writes a puzzle in the format of f-puzzles.
+- **`FPuzzlesLink`**: Returns the SudokuPad link of a puzzle.
+*/
+
+package sudokux
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FPuzzle is a puzzle as f-puzzles describes it: the givens, and every rule the solver knows.
+type FPuzzle struct {
+	Shape        Shape
+	Grid         map[string]rune // The givens, with '.' for empty cells
+	Solution     map[string]rune // The solution, for SudokuPad to check the answers of players, or nil
+	Variant      string          // Global rules, as the names of VariantConstraints separated by commas, or "" for classic
+	Regions      [][]string      // Regions replacing the boxes, for Jigsaw puzzles, or nil for the boxes
+	ExtraRegions [][]string      // Extra regions whose digits must differ (see ExtraRegions.go), or nil
+	Clues        Clues           // Cages, arrows, thermometers, lines, and the other clues
+
+	Title, Author, Rules string
+
+	Unsupported []string // Constraints of the file the solver doesn't know, which ParseFPuzzles left out
+}
+
+// fpuzzlesFile is the JSON of an f-puzzles puzzle, with the fields the solver knows.
+type fpuzzlesFile struct {
+	Size     int              `json:"size"`
+	Title    string           `json:"title,omitempty"`
+	Author   string           `json:"author,omitempty"`
+	Ruleset  string           `json:"ruleset,omitempty"`
+	Grid     [][]fpuzzlesCell `json:"grid"`
+	Solution []int            `json:"solution,omitempty"` // Digits of the solution in row-major order
+
+	DiagonalPos    bool     `json:"diagonal+,omitempty"` // From the bottom-left cell to the top-right one
+	DiagonalNeg    bool     `json:"diagonal-,omitempty"` // From the top-left cell to the bottom-right one
+	Antiknight     bool     `json:"antiknight,omitempty"`
+	Antiking       bool     `json:"antiking,omitempty"`
+	DisjointGroups bool     `json:"disjointgroups,omitempty"`
+	Nonconsecutive bool     `json:"nonconsecutive,omitempty"`
+	Negative       []string `json:"negative,omitempty"` // Constraints whose absence means something, such as "ratio"
+
+	KillerCage      []fpuzzlesCells            `json:"killercage,omitempty"`
+	Arrow           []fpuzzlesArrow            `json:"arrow,omitempty"`
+	Thermometer     []fpuzzlesLines            `json:"thermometer,omitempty"`
+	Palindrome      []fpuzzlesLines            `json:"palindrome,omitempty"`
+	Renban          []fpuzzlesLines            `json:"renban,omitempty"`
+	Whispers        []fpuzzlesLines            `json:"whispers,omitempty"`
+	LittleKillerSum []fpuzzlesLittle           `json:"littlekillersum,omitempty"`
+	Quadruple       []fpuzzlesQuadruple        `json:"quadruple,omitempty"`
+	Difference      []fpuzzlesCells            `json:"difference,omitempty"`
+	Ratio           []fpuzzlesCells            `json:"ratio,omitempty"`
+	ExtraRegion     []fpuzzlesCells            `json:"extraregion,omitempty"`
+	Others          map[string]json.RawMessage `json:"-"` // Every other field, to report the unsupported ones
+}
+
+// fpuzzlesCell is a cell of the grid of an f-puzzles puzzle.
+type fpuzzlesCell struct {
+	Value  int  `json:"value,omitempty"`  // Digit of the cell, or 0
+	Given  bool `json:"given,omitempty"`  // Whether the digit is a given, rather than entered by the player
+	Region *int `json:"region,omitempty"` // Index of the region of the cell, if not its box
+}
+
+// fpuzzlesCells is a clue on a group of cells, such as a cage or a dot.
+type fpuzzlesCells struct {
+	Cells []string `json:"cells"`
+	Value string   `json:"value,omitempty"` // Sum of a cage, difference or ratio of a dot
+}
+
+// fpuzzlesLines is a clue along lines, such as a thermometer, each line listed in order.
+type fpuzzlesLines struct {
+	Lines [][]string `json:"lines"`
+}
+
+// fpuzzlesArrow is an arrow: its circle, and lines that start from the circle.
+type fpuzzlesArrow struct {
+	Cells []string   `json:"cells"`
+	Lines [][]string `json:"lines"`
+}
+
+// fpuzzlesLittle is a Little Killer clue: the cell outside the grid holding the arrow, and where the arrow points.
+type fpuzzlesLittle struct {
+	Cell      string `json:"cell"`
+	Direction string `json:"direction"` // UL, UR, DL, or DR
+	Value     string `json:"value"`
+}
+
+// fpuzzlesQuadruple is a quadruple: its four cells and the digits in the circle.
+type fpuzzlesQuadruple struct {
+	Cells  []string `json:"cells"`
+	Values []int    `json:"values"`
+}
+
+// fpuzzlesKnown lists the fields of fpuzzlesFile, and fpuzzlesCosmetic the drawings that don't change the rules.
+var (
+	fpuzzlesKnown = map[string]bool{"size": true, "title": true, "author": true, "ruleset": true, "grid": true, "solution": true,
+		"diagonal+": true, "diagonal-": true, "antiknight": true, "antiking": true, "disjointgroups": true, "nonconsecutive": true,
+		"negative": true, "killercage": true, "arrow": true, "thermometer": true, "palindrome": true, "renban": true,
+		"whispers": true, "littlekillersum": true, "quadruple": true, "difference": true, "ratio": true, "extraregion": true}
+	fpuzzlesCosmetic = map[string]bool{"text": true, "line": true, "rectangle": true, "circle": true, "cage": true,
+		"highlightConflicts": true, "disabledlogic": true, "truecandidatesoptions": true}
+)
+
+// littleSteps maps the directions of f-puzzles to those of Little Killer clues (see LittleKiller.go).
+var littleSteps = map[string]string{"UL": "NW", "UR": "NE", "DL": "SW", "DR": "SE"}
+
+// ParseFPuzzles reads a puzzle in the format of f-puzzles: its JSON, or a link of f-puzzles or SudokuPad (or only
+// the compressed data of the link). Every clue is checked against the board, as ParseClues does.
+func ParseFPuzzles(data []byte) (*FPuzzle, error) {
+	text := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(text, "{") {
+		decoded, err := lzDecompress(fpuzzlesData(text))
+		if err != nil {
+			return nil, fmt.Errorf("neither f-puzzles JSON nor a link to a puzzle: %v", err)
+		}
+		text = decoded
+	}
+	var file fpuzzlesFile
+	if err := json.Unmarshal([]byte(text), &file); err != nil {
+		return nil, fmt.Errorf("invalid f-puzzles JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(text), &file.Others); err != nil {
+		return nil, fmt.Errorf("invalid f-puzzles JSON: %v", err)
+	}
+	shape, err := ShapeForSize(file.Size)
+	if err != nil {
+		return nil, err
+	}
+	p := &FPuzzle{Shape: shape, Grid: make(map[string]rune), Title: file.Title, Author: file.Author, Rules: file.Ruleset}
+	if err := p.readGrid(file); err != nil {
+		return nil, err
+	}
+	p.readRules(file)
+	if err := p.readClues(file); err != nil {
+		return nil, err
+	}
+	for name, value := range file.Others {
+		if !fpuzzlesKnown[name] && !fpuzzlesCosmetic[name] && !isFalsy(value) {
+			p.Unsupported = append(p.Unsupported, name)
+		}
+	}
+	sort.Strings(p.Unsupported)
+	return p, nil
+}
+
+// fpuzzlesData returns the compressed puzzle of a link of f-puzzles ("?load=") or SudokuPad ("/fpuzzles"), or text
+// itself if it isn't a link.
+func fpuzzlesData(text string) string {
+	if u, err := url.Parse(text); err == nil && u.Host != "" {
+		if load := u.Query().Get("load"); load != "" {
+			return load
+		}
+		text = strings.TrimPrefix(u.Path, "/")
+		if i := strings.Index(text, "fpuzzles"); i >= 0 { // Past the name of the page, as the data holds slashes too
+			text = text[i:]
+		}
+	}
+	return strings.TrimPrefix(text, "fpuzzles")
+}
+
+// isFalsy reports whether a field of the JSON says nothing: false, null, or empty.
+func isFalsy(value json.RawMessage) bool {
+	switch strings.TrimSpace(string(value)) {
+	case "false", "null", "[]", "{}", `""`:
+		return true
+	}
+	return false
+}
+
+// readGrid reads the givens, the solution, and the regions of file.
+func (p *FPuzzle) readGrid(file fpuzzlesFile) error {
+	size, digits := p.Shape.Size, p.Shape.Digits()
+	if len(file.Grid) != size {
+		return fmt.Errorf("the grid has %d rows instead of %d", len(file.Grid), size)
+	}
+	regions := make(map[int][]string)
+	custom := false // Whether a cell is out of its box
+	for row, cells := range file.Grid {
+		if len(cells) != size {
+			return fmt.Errorf("row %d of the grid has %d cells instead of %d", row+1, len(cells), size)
+		}
+		for col, cell := range cells {
+			pos := p.Shape.Pos(row, col)
+			p.Grid[pos] = '.'
+			if cell.Given && cell.Value != 0 { // Digits entered by a player aren't part of the puzzle
+				if cell.Value < 1 || cell.Value > size {
+					return fmt.Errorf("R%dC%d holds %d, which isn't a digit of the board", row+1, col+1, cell.Value)
+				}
+				p.Grid[pos] = digits[cell.Value-1]
+			}
+			region := p.Shape.BoxOf(row, col)
+			if cell.Region != nil {
+				custom = custom || *cell.Region != region
+				region = *cell.Region
+			}
+			regions[region] = append(regions[region], pos)
+		}
+	}
+	if custom {
+		for region := 0; region < size; region++ {
+			if len(regions[region]) != size {
+				return fmt.Errorf("region %d has %d cells instead of %d", region+1, len(regions[region]), size)
+			}
+			p.Regions = append(p.Regions, regions[region])
+		}
+	}
+	if len(file.Solution) == size*size {
+		p.Solution = make(map[string]rune)
+		for i, value := range file.Solution {
+			if value < 1 || value > size {
+				p.Solution = nil // A partial solution, which checks nothing
+				break
+			}
+			p.Solution[p.Shape.Pos(i/size, i%size)] = digits[value-1]
+		}
+	}
+	return nil
+}
+
+// readRules reads the global rules of file as variants, or extra regions for a lone diagonal.
+func (p *FPuzzle) readRules(file fpuzzlesFile) {
+	var variants []string
+	diagonals := NewDiagonalConstraint(p.Shape).Regions() // The main diagonal, then the anti-diagonal
+	switch {
+	case file.DiagonalPos && file.DiagonalNeg:
+		variants = append(variants, VariantX)
+	case file.DiagonalNeg:
+		p.ExtraRegions = append(p.ExtraRegions, diagonals[0])
+	case file.DiagonalPos:
+		p.ExtraRegions = append(p.ExtraRegions, diagonals[1])
+	}
+	for _, rule := range []struct {
+		on      bool
+		variant string
+	}{{file.Antiknight, VariantAntiKnight}, {file.Antiking, VariantAntiKing}, {file.DisjointGroups, VariantDisjoint}, {file.Nonconsecutive, VariantNonConsec}} {
+		if rule.on {
+			variants = append(variants, rule.variant)
+		}
+	}
+	p.Variant = strings.Join(variants, ",")
+}
+
+// readClues reads and checks the clues of file.
+func (p *FPuzzle) readClues(file fpuzzlesFile) error {
+	shape, clues := p.Shape, &p.Clues
+	owner := make(map[string]int) // Index of the cage each cell belongs to
+	for i, cage := range file.KillerCage {
+		cells, err := p.cells(cage.Cells)
+		if err != nil {
+			return fmt.Errorf("killer cage %d: %v", i+1, err)
+		}
+		if cage.Value == "" { // A cage without a sum only forbids repeats
+			p.ExtraRegions = append(p.ExtraRegions, cells)
+			continue
+		}
+		sum, err := strconv.Atoi(cage.Value)
+		if err != nil {
+			return fmt.Errorf("killer cage %d: invalid sum %q", i+1, cage.Value)
+		}
+		for _, pos := range cells {
+			if other, taken := owner[pos]; taken {
+				return fmt.Errorf("killer cage %d: cell %s is already in cage %d", i+1, pos, other)
+			}
+			owner[pos] = i + 1
+		}
+		if err := checkCage(Cage{Sum: sum, Cells: cells}, shape); err != nil {
+			return fmt.Errorf("killer cage %d: %v", i+1, err)
+		}
+		clues.Cages = append(clues.Cages, Cage{Sum: sum, Cells: cells})
+	}
+
+	for i, arrow := range file.Arrow {
+		circle, err := p.cells(arrow.Cells)
+		if err != nil || len(circle) != 1 {
+			p.unsupported("arrow with a circle of several cells")
+			continue
+		}
+		a := Arrow{Circle: circle[0]}
+		for _, line := range arrow.Lines {
+			cells, err := p.cells(line)
+			if err != nil {
+				return fmt.Errorf("arrow %d: %v", i+1, err)
+			}
+			if len(cells) > 0 && cells[0] == a.Circle { // Lines start in the circle
+				cells = cells[1:]
+			}
+			a.Cells = append(a.Cells, cells...)
+		}
+		if err := checkArrow(a, shape); err != nil {
+			return fmt.Errorf("arrow %d: %v", i+1, err)
+		}
+		clues.Arrows = append(clues.Arrows, a)
+	}
+
+	for _, kind := range []struct {
+		name  string
+		lines []fpuzzlesLines
+		to    *[][]string
+		check func([]string, Shape) error
+	}{
+		{"thermometer", file.Thermometer, &clues.Thermos, checkThermo},
+		{"palindrome", file.Palindrome, &clues.Palindromes, nil},
+		{"renban", file.Renban, &clues.Renbans, checkRenban},
+		{"whispers", file.Whispers, &clues.Whispers, nil},
+	} {
+		for i, clue := range kind.lines {
+			for _, line := range clue.Lines {
+				cells, err := p.cells(line)
+				if err == nil {
+					err = checkLine(cells, shape)
+				}
+				if err == nil && kind.check != nil {
+					err = kind.check(cells, shape)
+				}
+				if err != nil {
+					return fmt.Errorf("%s %d: %v", kind.name, i+1, err)
+				}
+				*kind.to = append(*kind.to, cells)
+			}
+		}
+	}
+
+	for i, little := range file.LittleKillerSum {
+		row, col, ok := parseRC(little.Cell) // Outside the grid, so not a cell of the board
+		step, known := littleSteps[little.Direction]
+		if !ok || !known {
+			return fmt.Errorf("little killer %d: invalid arrow %s %s", i+1, little.Cell, little.Direction)
+		}
+		if little.Value == "" {
+			continue // An arrow without a sum says nothing
+		}
+		move := diagonalSteps[step]
+		diagonal, err := parseDiagonal(little.Value, shape.Pos(row+move[0], col+move[1])+" "+step, shape)
+		if err != nil {
+			return fmt.Errorf("little killer %d: %v", i+1, err)
+		}
+		clues.Diagonals = append(clues.Diagonals, diagonal)
+	}
+
+	for i, quad := range file.Quadruple {
+		cells, err := p.cells(quad.Cells)
+		if err != nil || len(cells) != 4 {
+			return fmt.Errorf("quadruple %d: expected 4 cells", i+1)
+		}
+		corner := cells[0]
+		for _, pos := range cells[1:] { // The top-left cell of the four
+			row, col, _ := shape.ParsePos(pos)
+			if cornerRow, cornerCol, _ := shape.ParsePos(corner); row < cornerRow || (row == cornerRow && col < cornerCol) {
+				corner = pos
+			}
+		}
+		var digits []string
+		for _, value := range quad.Values {
+			if value < 1 || value > shape.Size {
+				return fmt.Errorf("quadruple %d: %d isn't a digit of the board", i+1, value)
+			}
+			digits = append(digits, string(shape.Digits()[value-1]))
+		}
+		q, err := parseQuadruple(corner, strings.Join(digits, " "), shape)
+		if err != nil {
+			return fmt.Errorf("quadruple %d: %v", i+1, err)
+		}
+		clues.Quadruples = append(clues.Quadruples, q)
+	}
+
+	for _, kind := range []struct {
+		name, color, value string // The value of the dots the solver knows, such as a difference of 1
+		dots               []fpuzzlesCells
+	}{{"difference", DotWhite, "1", file.Difference}, {"ratio", DotBlack, "2", file.Ratio}} {
+		for i, dot := range kind.dots {
+			cells, err := p.cells(dot.Cells)
+			if err != nil || len(cells) != 2 {
+				return fmt.Errorf("%s %d: expected 2 cells", kind.name, i+1)
+			}
+			if dot.Value != "" && dot.Value != kind.value {
+				p.unsupported(kind.name + " of " + dot.Value)
+				continue
+			}
+			d := Dot{Color: kind.color, A: cells[0], B: cells[1]}
+			if err := checkDot(d, shape); err != nil {
+				return fmt.Errorf("%s %d: %v", kind.name, i+1, err)
+			}
+			clues.Dots = append(clues.Dots, d)
+		}
+	}
+	negative := make(map[string]bool)
+	for _, name := range file.Negative {
+		negative[name] = true
+	}
+	if negative["difference"] && negative["ratio"] {
+		clues.AllDots = true
+	} else if negative["difference"] || negative["ratio"] {
+		p.unsupported("negative constraint of only one color of dots")
+	}
+
+	for i, region := range file.ExtraRegion {
+		cells, err := p.cells(region.Cells)
+		if err != nil {
+			return fmt.Errorf("extra region %d: %v", i+1, err)
+		}
+		p.ExtraRegions = append(p.ExtraRegions, cells)
+	}
+	for i, region := range p.ExtraRegions {
+		if len(region) > shape.Size {
+			return fmt.Errorf("extra region %d has %d cells, more than the %d digits", i+1, len(region), shape.Size)
+		}
+	}
+	return nil
+}
+
+// cells converts cells written "R1C1" to positions of the board such as "A1".
+func (p *FPuzzle) cells(cells []string) ([]string, error) {
+	var positions []string
+	for _, cell := range cells {
+		row, col, ok := parseRC(cell)
+		if !ok || !inside(p.Shape, row, col) {
+			return nil, fmt.Errorf("%s is not a cell of the board", cell)
+		}
+		positions = append(positions, p.Shape.Pos(row, col))
+	}
+	return positions, nil
+}
+
+// unsupported records a constraint left out of the rules, once.
+func (p *FPuzzle) unsupported(name string) {
+	for _, known := range p.Unsupported {
+		if known == name {
+			return
+		}
+	}
+	p.Unsupported = append(p.Unsupported, name)
+}
+
+// parseRC reads a cell written "R1C1" as its row and column from 0, which may be outside the board.
+func parseRC(cell string) (int, int, bool) {
+	rowText, colText, ok := strings.Cut(strings.TrimPrefix(strings.ToUpper(cell), "R"), "C")
+	row, errRow := strconv.Atoi(rowText)
+	col, errCol := strconv.Atoi(colText)
+	return row - 1, col - 1, ok && strings.HasPrefix(strings.ToUpper(cell), "R") && errRow == nil && errCol == nil
+}
+
+// rc writes pos as f-puzzles does, such as "R1C1" for "A1".
+func rc(shape Shape, pos string) string {
+	row, col, _ := shape.ParsePos(pos)
+	return fmt.Sprintf("R%dC%d", row+1, col+1)
+}
+
+// Constraints returns every rule of the puzzle: the classic rules with the regions of the puzzle, its variants, its
+// extra regions, and its clues.
+func (p *FPuzzle) Constraints() ([]Constraint, error) {
+	constraints, err := VariantConstraints(p.Variant, p.Shape)
+	if err != nil {
+		return nil, err
+	}
+	if p.Regions != nil {
+		constraints = WithRegions(constraints, p.Regions)
+	}
+	if len(p.ExtraRegions) > 0 {
+		constraints = append(constraints, NewRegionConstraint("extra regions", p.ExtraRegions))
+	}
+	return append(constraints, p.Clues.Constraints(p.Shape)...), nil
+}
+
+// MarshalFPuzzles writes p in the format of f-puzzles. Windoku, Asterisk, and Center-dot puzzles become extra
+// regions, which f-puzzles draws the same way. Greater-than signs have no equivalent in f-puzzles, so puzzles with
+// such signs are refused.
+func MarshalFPuzzles(p *FPuzzle) ([]byte, error) {
+	if len(p.Clues.Inequalities) > 0 {
+		return nil, fmt.Errorf("f-puzzles has no greater-than signs")
+	}
+	shape := p.Shape
+	file := fpuzzlesFile{Size: shape.Size, Title: p.Title, Author: p.Author, Ruleset: p.Rules, Grid: make([][]fpuzzlesCell, shape.Size)}
+	region := make(map[string]int)
+	if p.Regions != nil {
+		for index, cells := range p.Regions {
+			for _, pos := range cells {
+				region[pos] = index
+			}
+		}
+	}
+	for row := range file.Grid {
+		file.Grid[row] = make([]fpuzzlesCell, shape.Size)
+		for col := range file.Grid[row] {
+			pos, cell := shape.Pos(row, col), &file.Grid[row][col]
+			if digit := p.Grid[pos]; shape.IsDigit(digit) {
+				cell.Value, cell.Given = digitValue(shape, digit), true
+			}
+			if index, ok := region[pos]; ok {
+				cell.Region = &index
+			}
+		}
+	}
+	if p.Solution != nil {
+		for _, pos := range shape.Cells() {
+			file.Solution = append(file.Solution, digitValue(shape, p.Solution[pos]))
+		}
+	}
+
+	cellsOf := func(cells []string) []string {
+		var out []string
+		for _, pos := range cells {
+			out = append(out, rc(shape, pos))
+		}
+		return out
+	}
+	extra := append([][]string(nil), p.ExtraRegions...)
+	for _, name := range strings.Split(p.Variant, ",") {
+		switch strings.TrimSpace(name) {
+		case VariantClassic, "":
+		case VariantX:
+			file.DiagonalPos, file.DiagonalNeg = true, true
+		case VariantAntiKnight:
+			file.Antiknight = true
+		case VariantAntiKing:
+			file.Antiking = true
+		case VariantDisjoint:
+			file.DisjointGroups = true
+		case VariantNonConsec:
+			file.Nonconsecutive = true
+		default: // Variants made of extra regions
+			constraints, err := VariantConstraints(name, shape)
+			if err != nil {
+				return nil, err
+			}
+			extra = append(extra, constraints[len(constraints)-1].(*RegionConstraint).Regions()...)
+		}
+	}
+	for _, region := range extra {
+		file.ExtraRegion = append(file.ExtraRegion, fpuzzlesCells{Cells: cellsOf(region)})
+	}
+
+	clues := p.Clues
+	for _, cage := range clues.Cages {
+		file.KillerCage = append(file.KillerCage, fpuzzlesCells{Cells: cellsOf(cage.Cells), Value: strconv.Itoa(cage.Sum)})
+	}
+	for _, arrow := range clues.Arrows {
+		line := cellsOf(append([]string{arrow.Circle}, arrow.Cells...))
+		file.Arrow = append(file.Arrow, fpuzzlesArrow{Cells: line[:1], Lines: [][]string{line}})
+	}
+	for _, kind := range []struct {
+		lines [][]string
+		to    *[]fpuzzlesLines
+	}{{clues.Thermos, &file.Thermometer}, {clues.Palindromes, &file.Palindrome}, {clues.Renbans, &file.Renban}, {clues.Whispers, &file.Whispers}} {
+		for _, line := range kind.lines {
+			*kind.to = append(*kind.to, fpuzzlesLines{Lines: [][]string{cellsOf(line)}})
+		}
+	}
+	for _, diagonal := range clues.Diagonals {
+		cell, direction := littleArrow(shape, diagonal.Cells)
+		file.LittleKillerSum = append(file.LittleKillerSum, fpuzzlesLittle{Cell: cell, Direction: direction, Value: strconv.Itoa(diagonal.Sum)})
+	}
+	for _, quad := range clues.Quadruples {
+		var values []int
+		for _, digit := range quad.Digits {
+			values = append(values, digitValue(shape, digit))
+		}
+		file.Quadruple = append(file.Quadruple, fpuzzlesQuadruple{Cells: cellsOf(quad.Cells), Values: values})
+	}
+	for _, dot := range clues.Dots {
+		if dot.Color == DotWhite {
+			file.Difference = append(file.Difference, fpuzzlesCells{Cells: cellsOf([]string{dot.A, dot.B})})
+		} else {
+			file.Ratio = append(file.Ratio, fpuzzlesCells{Cells: cellsOf([]string{dot.A, dot.B})})
+		}
+	}
+	if clues.AllDots {
+		file.Negative = []string{"ratio", "difference"}
+	}
+	return json.Marshal(file)
+}
+
+// littleArrow returns the cell outside the grid, written "R0C3", and the direction of the arrow of a Little Killer
+// diagonal.
+func littleArrow(shape Shape, cells []string) (string, string) {
+	row, col, _ := shape.ParsePos(cells[0])
+	for _, direction := range []string{"DR", "DL", "UR", "UL"} {
+		move := diagonalSteps[littleSteps[direction]]
+		if inside(shape, row-move[0], col-move[1]) {
+			continue // The arrow sits outside the grid
+		}
+		if len(cells) > 1 {
+			nextRow, nextCol, _ := shape.ParsePos(cells[1])
+			if nextRow != row+move[0] || nextCol != col+move[1] {
+				continue
+			}
+		}
+		return fmt.Sprintf("R%dC%d", row-move[0]+1, col-move[1]+1), direction
+	}
+	return rc(shape, cells[0]), "DR" // Not reached for the diagonals of parseDiagonal
+}
+
+// FPuzzlesLink returns the link opening the puzzle of data, as written by MarshalFPuzzles, in SudokuPad.
+func FPuzzlesLink(data []byte) string {
+	return "https://sudokupad.app/fpuzzles" + lzCompress(string(data))
+}
@@ -0,0 +1,61 @@
+package sudokux
+
+import "testing"
+
+func TestLZString(t *testing.T) {
+	tests := []struct {
+		text, compressed string // compressed is "" when only the round trip is checked
+	}{
+		{"hello", "BYUwNmD2Q==="}, // As lz-string's compressToBase64 writes it
+		{"abcabcabcabcabcabc", ""},
+		{"ééé 日本 😀", ""},
+		{`{"size":9,"grid":[[{"value":5,"given":true},{},{}]]}`, ""},
+	}
+	for _, tt := range tests {
+		compressed := lzCompress(tt.text)
+		if tt.compressed != "" && compressed != tt.compressed {
+			t.Errorf("lzCompress(%q) = %s, want %s", tt.text, compressed, tt.compressed)
+		}
+		if text, err := lzDecompress(compressed); err != nil || text != tt.text {
+			t.Errorf("lzDecompress(%s) = %q, %v, want %q", compressed, text, err, tt.text)
+		}
+	}
+	for _, bad := range []string{"", "!!!!", "A"} {
+		if text, err := lzDecompress(bad); err == nil {
+			t.Errorf("lzDecompress(%q) = %q, want an error", bad, text)
+		}
+	}
+}
+
+// TestFPuzzlesRoundTrip checks that a puzzle written by MarshalFPuzzles, as JSON and as a link, reads back the same.
+func TestFPuzzlesRoundTrip(t *testing.T) {
+	grid, err := GridFromString(easyPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := GridFromString(easySolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	puzzle := &FPuzzle{Shape: Classic, Grid: grid, Solution: solution, Title: "Readme", Variant: VariantX}
+	data, err := MarshalFPuzzles(puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range []string{string(data), FPuzzlesLink(data)} {
+		got, err := ParseFPuzzles([]byte(input))
+		if err != nil {
+			t.Fatalf("ParseFPuzzles() error: %v", err)
+		}
+		if CanonicalString(got.Grid) != easyPuzzle || got.Title != puzzle.Title || got.Variant != VariantX {
+			t.Errorf("ParseFPuzzles() = %s %q %q, want %s %q %q", CanonicalString(got.Grid), got.Title, got.Variant,
+				easyPuzzle, puzzle.Title, VariantX)
+		}
+		if CanonicalString(got.Solution) != easySolution {
+			t.Errorf("ParseFPuzzles() solution = %s, want %s", CanonicalString(got.Solution), easySolution)
+		}
+		if len(got.Unsupported) > 0 {
+			t.Errorf("ParseFPuzzles() left out %v", got.Unsupported)
+		}
+	}
+}
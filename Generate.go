@@ -0,0 +1,339 @@
+/*
+This file generates new puzzles, graded as in Rating.go.
+
+A puzzle is generated by filling an empty grid at random (or starting from a solved grid of the setter's choice),
+then digging out its clues one by one in random order, putting a clue back whenever removing it makes the solution
+ambiguous or the puzzle harder than the target. When the dug puzzle ends up easier than the target, the generator
+starts over from a new grid (or digs the setter's grid again in another order). Digging can also stop at a target
+number of givens, or go on for a time budget, keeping the puzzle with the fewest givens found. On boards larger than
+9x9, proving every dug puzzle unique can take very long, so digging stops after a time limit and keeps the clues
+left, which only makes the puzzle easier. For players who
+refuse puzzles needing trial and error, digging can also be kept from going past what logic solves alone. For a
+symmetric layout of the clues, as newspapers print them, the clues are dug out together with their mirror images.
+
+Puzzles are classic 9x9 ones unless the options ask for another board, such as 6x6 or 16x16; every step above works
+on any board, with the default boxes for its size, since the grading of Rating.go assumes them.
+
+Variant puzzles (see Variant.go) are generated the same way, with the constraints of the variant both when filling
+the grid and when checking that the solution is unique. The grade only counts the classic techniques, though, so
+a variant puzzle may be easier than its grade says when its own rules give extra deductions.
+
+Functions:
+- **`Generate`**: Generates a puzzle with a unique solution, optionally of a given grade.
+- **`Daily`**: Generates the puzzle of the day, the same for everyone on a given date.
+- **`CountGivens`**: Counts the givens of a puzzle.
+- **`RandomSolvedGrid`**: Returns a random solved classic grid, for simulations and testing.
+*/
+
+package sudokux
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// Symmetry is a symmetry of the layout of the clues.
+type Symmetry string
+
+// Symmetries of the layout of the clues.
+const (
+	SymmetryNone       Symmetry = ""           // Any layout
+	SymmetryRotational Symmetry = "rotational" // The layout looks the same after a half-turn around the center
+	SymmetryMirror     Symmetry = "mirror"     // The left half of the layout mirrors the right half
+	SymmetryDiagonal   Symmetry = "diagonal"   // The layout mirrors itself across the main diagonal (A1 to I9)
+)
+
+// ParseSymmetry returns the symmetry with the given name (such as "rotational"), or SymmetryNone for "none".
+func ParseSymmetry(name string) (Symmetry, error) {
+	switch symmetry := Symmetry(name); symmetry {
+	case SymmetryRotational, SymmetryMirror, SymmetryDiagonal:
+		return symmetry, nil
+	case "none":
+		return SymmetryNone, nil
+	}
+	return "", fmt.Errorf("unknown symmetry %q (expected none, rotational, mirror, or diagonal)", name)
+}
+
+// images returns the cells of a board of the given shape that must be dug out together with pos (pos included) to
+// keep the symmetry.
+func (s Symmetry) images(shape Shape, pos string) []string {
+	row, col, _ := shape.ParsePos(pos)
+	last := shape.Size - 1
+	var image string
+	switch s {
+	case SymmetryRotational:
+		image = shape.Pos(last-row, last-col)
+	case SymmetryMirror:
+		image = shape.Pos(row, last-col)
+	case SymmetryDiagonal:
+		image = shape.Pos(col, row)
+	default:
+		return []string{pos}
+	}
+	if image == pos { // The center cell, or a cell on the axis of the symmetry
+		return []string{pos}
+	}
+	return []string{pos, image}
+}
+
+// GenerateOptions describes the puzzle to generate.
+type GenerateOptions struct {
+	Shape       Shape           // Board of the puzzle, with the default boxes for its size, or the zero Shape for a 9x9 one
+	Seed        int64           // Non-zero to generate the same puzzle every time, or 0 for a random one
+	Difficulty  Difficulty      // Grade of the puzzle, or "" for any grade
+	Symmetry    Symmetry        // Symmetry of the layout of the clues, or SymmetryNone
+	Variant     string          // Variant of the puzzle (see VariantConstraints), or "" for a classic one
+	Solution    map[string]rune // Solved grid to dig the puzzle out of, or nil for a random one
+	Givens      int             // Exact number of givens of the puzzle, or 0 for as few as digging gets to
+	TimeLimit   time.Duration   // Non-zero to keep generating until then, returning the puzzle with the fewest givens
+	NoGuessing  bool            // Whether the puzzle must be solvable by the techniques of Logic.go alone
+	MaxAttempts int             // Number of grids to try before giving up on the grade (100 when 0)
+	DigTime     time.Duration   // Longest time spent digging one grid: 0 for DefaultDigTime above 9x9 and no limit otherwise, negative for no limit
+}
+
+// DefaultDigTime is the longest time Generate spends digging one grid of a board larger than 9x9, unless the options
+// say otherwise.
+const DefaultDigTime = 30 * time.Second
+
+// Generate returns a puzzle with a unique solution, along with the solution. With a target difficulty or
+// number of givens, it tries new grids (or new digging orders of opts.Solution) until the dug puzzle has exactly
+// that grade and number of givens, and returns an error if none does within opts.MaxAttempts attempts. With a time
+// limit, it keeps trying until the time is up instead, and returns the matching puzzle with the fewest givens.
+func Generate(opts GenerateOptions) (map[string]rune, map[string]rune, error) {
+	constraints, err := opts.check()
+	if err != nil {
+		return nil, nil, err
+	}
+	shape := opts.shape()
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	attempts := opts.MaxAttempts
+	if attempts == 0 {
+		attempts = 100
+	}
+	deadline := time.Now().Add(opts.TimeLimit)
+	var gridUntil time.Time // When filling a grid gives up, never without a time limit
+	if opts.TimeLimit > 0 {
+		gridUntil = deadline
+	}
+	target := opts.Difficulty // Grade digging must not go past
+	if opts.NoGuessing && target == "" {
+		target = DifficultyMedium // The hardest grade logic solves alone
+	}
+
+	var best, bestSolution map[string]rune // The matching puzzle with the fewest givens so far
+	for attempt := 0; ; attempt++ {
+		if opts.TimeLimit > 0 && time.Now().After(deadline) || opts.TimeLimit == 0 && attempt == attempts {
+			break
+		}
+		solution := opts.Solution
+		if solution == nil {
+			var inTime bool
+			if solution, inTime = randomSolvedGrid(shape, constraints, gridUntil, rng); !inTime {
+				break
+			} else if solution == nil {
+				return nil, nil, fmt.Errorf("no grid satisfies the rules of the %s variant", opts.Variant)
+			}
+		}
+		digUntil := opts.digDeadline()
+		if opts.TimeLimit > 0 && (digUntil.IsZero() || deadline.Before(digUntil)) {
+			digUntil = deadline // Digging doesn't outlast the time limit either
+		}
+		puzzle := digPuzzle(solution, constraints, target, opts.Symmetry, opts.Givens, digUntil, rng)
+		if opts.Difficulty != "" && Rate(puzzle) != opts.Difficulty || opts.Givens > 0 && CountGivens(puzzle) != opts.Givens {
+			continue
+		}
+		if opts.TimeLimit == 0 {
+			return puzzle, solution, nil
+		}
+		if best == nil || CountGivens(puzzle) < CountGivens(best) {
+			best, bestSolution = puzzle, solution
+		}
+	}
+	if best != nil {
+		return best, bestSolution, nil
+	}
+	if opts.TimeLimit > 0 {
+		return nil, nil, fmt.Errorf("%w in %v", errNoMatch, opts.TimeLimit)
+	}
+	return nil, nil, fmt.Errorf("%w in %d attempts", errNoMatch, attempts)
+}
+
+// Daily returns the puzzle of the given grade for the day of date, along with its solution. The puzzle only depends
+// on the calendar date (in the location of date) and the grade, so everyone generating it on the same day gets the
+// same puzzle, laid out with rotational symmetry as newspapers print them.
+func Daily(date time.Time, difficulty Difficulty) (map[string]rune, map[string]rune, error) {
+	year, month, day := date.Date()
+	seed := int64(year*10000 + int(month)*100 + day) // Such as 20240601, never 0
+	return Generate(GenerateOptions{Seed: seed, Difficulty: difficulty, Symmetry: SymmetryRotational})
+}
+
+// errNoMatch is returned by Generate when none of the puzzles it dug matched the options.
+var errNoMatch = errors.New("no matching puzzle found")
+
+// shape returns the board of the puzzle to generate.
+func (opts GenerateOptions) shape() Shape {
+	if opts.Shape == (Shape{}) {
+		return Classic
+	}
+	return opts.Shape
+}
+
+// digDeadline returns when digging a grid started now must stop, or the zero time for never.
+func (opts GenerateOptions) digDeadline() time.Time {
+	limit := opts.DigTime
+	if limit == 0 && opts.shape().Size > Classic.Size {
+		limit = DefaultDigTime
+	}
+	if limit <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(limit)
+}
+
+// check validates the options and returns the constraints of their variant.
+func (opts GenerateOptions) check() ([]Constraint, error) {
+	shape := opts.shape()
+	if defaultShape, err := ShapeForSize(shape.Size); err != nil || shape != defaultShape {
+		return nil, fmt.Errorf("puzzles can only be generated with the default boxes for their size, which grading assumes")
+	}
+	if opts.Difficulty != "" && opts.Difficulty.rank() < 0 {
+		return nil, fmt.Errorf("unknown difficulty %q", opts.Difficulty)
+	}
+	if opts.NoGuessing && opts.Difficulty == DifficultyHard {
+		return nil, fmt.Errorf("hard puzzles need guessing by definition")
+	}
+	switch opts.Symmetry {
+	case SymmetryNone, SymmetryRotational, SymmetryMirror, SymmetryDiagonal:
+	default:
+		return nil, fmt.Errorf("unknown symmetry %q", opts.Symmetry)
+	}
+	constraints, err := VariantConstraints(opts.Variant, shape)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Solution != nil {
+		if len(opts.Solution) != shape.Size*shape.Size {
+			return nil, fmt.Errorf("invalid solution to dig from: expected a %dx%d grid", shape.Size, shape.Size)
+		}
+		if err := CheckSolved(opts.Solution); err != nil {
+			return nil, fmt.Errorf("invalid solution to dig from: %v", err)
+		}
+		if err := ValidateClues(opts.Solution, constraints); err != nil {
+			return nil, fmt.Errorf("invalid solution to dig from: %v", err)
+		}
+	}
+	if opts.Givens < 0 || opts.Givens > shape.Size*shape.Size {
+		return nil, fmt.Errorf("invalid number of givens %d", opts.Givens)
+	}
+	return constraints, nil
+}
+
+// CountGivens returns the number of givens (filled cells) of a puzzle.
+func CountGivens(grid map[string]rune) int {
+	count := 0
+	for _, val := range grid {
+		if val != '.' && val != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// RandomSolvedGrid returns a random solved classic grid; a non-zero seed gives the same grid every time. A shuffled
+// search alone favors some grids over others, depending on the order in which it fills the cells, so the grid it
+// finds is then put through a random transformation (see Shuffle): every grid equivalent to it under relabeling,
+// reordering of rows and columns, and reflection is then equally likely, which brings the sampling close to uniform.
+func RandomSolvedGrid(seed int64) map[string]rune {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	grid, _ := randomSolvedGrid(Classic, nil, time.Time{}, rng) // The classic rules always have a solution
+	return Shuffle(grid, Classic, rng.Int63()|1)
+}
+
+// randomSolvedGrid fills an empty grid of the given shape under the constraints, by a search that tries digits in a
+// shuffled order. Some orders lead the search into long dead ends under variant rules, so it restarts with a new
+// order whenever a node budget runs out, doubling the budget each time. It returns nil if no grid satisfies the
+// constraints. Unless deadline is the zero time, it gives up once the deadline has passed, returning nil and false.
+func randomSolvedGrid(shape Shape, constraints []Constraint, deadline time.Time, rng *rand.Rand) (map[string]rune, bool) {
+	grid := NewGrid(shape)
+	for budget := 1000; ; budget *= 2 {
+		state := NewConstrainedSearchState(grid, 1, constraints)
+		state.Seed = rng.Int63() | 1 // Any non-zero seed shuffles the digits
+		// 100 nodes at a time between checks of the deadline, which take milliseconds on a 25x25 board
+		for nodes := 0; nodes < budget; nodes += 100 {
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return nil, false
+			}
+			done := state.Run(100)
+			if len(state.Solutions) > 0 {
+				return state.Solutions[0], true
+			}
+			if done { // The whole search space was explored
+				return nil, true
+			}
+		}
+	}
+}
+
+// digPuzzle removes the clues of solution in random order, along with their images under symmetry, keeping them
+// whenever removing them would allow a second solution under the constraints, make the puzzle harder than target
+// (any grade when target is ""), or leave fewer than givens clues. Unless deadline is the zero time, it stops
+// digging once the deadline has passed, keeping the clues left.
+func digPuzzle(solution map[string]rune, constraints []Constraint, target Difficulty, symmetry Symmetry, givens int, deadline time.Time, rng *rand.Rand) map[string]rune {
+	puzzle := make(map[string]rune)
+	copyGrid(solution, puzzle)
+	count := len(puzzle) // Number of clues left
+	shape := ShapeOf(solution)
+	classic := slices.Equal(constraints, shape.tables().classic) // The array search of SolveInto.go only knows the classic rules
+	cells := shape.Cells()
+	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	for _, pos := range cells {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+		if puzzle[pos] == '.' { // Already dug out as the image of another cell
+			continue
+		}
+		images := symmetry.images(shape, pos)
+		if count-len(images) < givens {
+			continue
+		}
+		for _, image := range images {
+			puzzle[image] = '.'
+		}
+		if !isUnique(puzzle, constraints, classic, deadline) || (target != "" && Rate(puzzle).rank() > target.rank()) {
+			for _, image := range images {
+				puzzle[image] = solution[image]
+			}
+			continue
+		}
+		count -= len(images)
+	}
+	return puzzle
+}
+
+// isUnique reports whether puzzle has exactly one solution under the constraints, which are the classic rules alone
+// if classic is true. It reports false if the deadline passes before the search can tell, unless the deadline is
+// the zero time.
+func isUnique(puzzle map[string]rune, constraints []Constraint, classic bool, deadline time.Time) bool {
+	if classic {
+		solutions, finished := countSolutions(puzzle, deadline)
+		return finished && solutions == 1
+	}
+	state := NewConstrainedSearchState(puzzle, 2, constraints)
+	for !state.Run(10000) { // Look at the clock every 10000 nodes
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return false
+		}
+	}
+	return len(state.Solutions) == 1
+}
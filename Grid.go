@@ -0,0 +1,157 @@
+/*
+This file defines `Grid`, the board of a puzzle: a map from every position (such as "A1") to its digit, or '.' for
+an empty cell. Grid is a named map type, so a map[string]rune can be used wherever a Grid is expected and the other
+way around; the functions of this package that take or return a map[string]rune work with grids unchanged.
+
+The methods cover what callers would otherwise write again around the bare map:
+- **`NewGrid`**: Returns an empty grid of the given shape.
+- **`Get`** / **`Set`**: Read and write the cell at a (row, column) index, counted from 0.
+- **`Clone`** / **`Equal`**: Copy a grid, and compare two of them cell by cell.
+- **`EmptyCells`**: Lists the empty cells in row-major order.
+- **`Rows`**, **`Cols`**, **`Boxes`**, **`Peers`**: Iterate over the cells of every row, column, and box, and over
+  the peers of a cell, as coordinates (see Coord.go); **`Values`** reads the digits of a list of cells.
+- **`String`**: Writes every cell on one line, in row-major order.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"strings"
+)
+
+// Grid is the board of a puzzle, mapping every position (such as "A1") to its digit, or '.' for an empty cell.
+type Grid map[string]rune
+
+// NewGrid returns a grid of the given shape with every cell empty.
+func NewGrid(shape Shape) Grid {
+	grid := make(Grid, shape.Size*shape.Size)
+	for _, pos := range shape.Cells() {
+		grid[pos] = '.'
+	}
+	return grid
+}
+
+// Shape returns the shape of the grid, worked out from its number of cells (see ShapeOf).
+func (g Grid) Shape() Shape {
+	return ShapeOf(g)
+}
+
+// Get returns the digit at the given row and column (counted from 0), '.' if the cell is empty, or 0 if it is
+// outside the board.
+func (g Grid) Get(row, col int) rune {
+	shape := g.Shape()
+	if row < 0 || row >= shape.Size || col < 0 || col >= shape.Size {
+		return 0
+	}
+	return g[shape.Pos(row, col)]
+}
+
+// Set puts digit (or '.' to empty the cell) at the given row and column (counted from 0). It panics if the cell is
+// outside the board, which would add a cell to the grid and change its shape.
+func (g Grid) Set(row, col int, digit rune) {
+	shape := g.Shape()
+	if row < 0 || row >= shape.Size || col < 0 || col >= shape.Size {
+		panic(fmt.Sprintf("sudokux: cell (%d, %d) is outside the %dx%d grid", row, col, shape.Size, shape.Size))
+	}
+	g[shape.Pos(row, col)] = digit
+}
+
+// Clone returns a copy of the grid, which can be changed without changing the original.
+func (g Grid) Clone() Grid {
+	return maps.Clone(g)
+}
+
+// Equal reports whether both grids have the same cells with the same contents.
+func (g Grid) Equal(other Grid) bool {
+	return maps.Equal(g, other)
+}
+
+// EmptyCells returns the positions of the empty cells in row-major order.
+func (g Grid) EmptyCells() []string {
+	var empty []string
+	for _, pos := range g.Shape().Cells() {
+		if g[pos] == '.' {
+			empty = append(empty, pos)
+		}
+	}
+	return empty
+}
+
+// Rows returns an iterator over the rows of the grid, from top to bottom, each as its cells from left to right.
+func (g Grid) Rows() iter.Seq[[]Coord] {
+	shape := g.Shape()
+	return func(yield func([]Coord) bool) {
+		for row := 0; row < shape.Size; row++ {
+			cells := make([]Coord, shape.Size)
+			for col := range cells {
+				cells[col] = Coord{Row: row, Col: col}
+			}
+			if !yield(cells) {
+				return
+			}
+		}
+	}
+}
+
+// Cols returns an iterator over the columns of the grid, from left to right, each as its cells from top to bottom.
+func (g Grid) Cols() iter.Seq[[]Coord] {
+	shape := g.Shape()
+	return func(yield func([]Coord) bool) {
+		for col := 0; col < shape.Size; col++ {
+			cells := make([]Coord, shape.Size)
+			for row := range cells {
+				cells[row] = Coord{Row: row, Col: col}
+			}
+			if !yield(cells) {
+				return
+			}
+		}
+	}
+}
+
+// Boxes returns an iterator over the boxes of the grid (with the default boxes for its size), in row-major order,
+// each as its cells in row-major order.
+func (g Grid) Boxes() iter.Seq[[]Coord] {
+	shape := g.Shape()
+	return func(yield func([]Coord) bool) {
+		for box := 0; box < shape.Size; box++ {
+			if !yield(shape.boxCells(box)) {
+				return
+			}
+		}
+	}
+}
+
+// Peers returns an iterator over the cells sharing a row, column, or box with c, in row-major order.
+func (g Grid) Peers(c Coord) iter.Seq[Coord] {
+	peers := c.Peers(g.Shape())
+	return func(yield func(Coord) bool) {
+		for _, peer := range peers {
+			if !yield(peer) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns the digits of the given cells, in the same order ('.' for empty cells, 0 for cells outside the board).
+func (g Grid) Values(cells []Coord) []rune {
+	values := make([]rune, len(cells))
+	for i, c := range cells {
+		values[i] = g.Get(c.Row, c.Col)
+	}
+	return values
+}
+
+// String returns every cell of the grid on one line in row-major order, such as "53..7....6..195...", the format
+// read by ParseLine.
+func (g Grid) String() string {
+	var line strings.Builder
+	for _, pos := range g.Shape().Cells() {
+		line.WriteRune(g[pos])
+	}
+	return line.String()
+}
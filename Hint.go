@@ -0,0 +1,79 @@
+/*
+This file suggests the next move to a player who is partway through a puzzle, the way a friend looking over their
+shoulder would: it points out one step and why it works, without giving the rest of the solution away.
+
+The hint is, in order of priority:
+1. **A mistake**: a digit the player placed that isn't in the solution. Going on from a wrong grid only leads to
+   contradictions, so the player is told to clear that cell first (without being told the right digit).
+2. **A deduction**: the next step found by the techniques of Logic.go, simplest first, with its explanation.
+3. **A guess**: when logic is stuck, the empty cell with the fewest candidates and its digit in the solution,
+   since no reasoning short of trial and error finds it.
+
+- `NextHint`: Returns the hint for a puzzle and the player's progress on it.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Kinds of hints.
+const (
+	HintMistake   = "mistake"   // A placed digit is wrong and should be cleared
+	HintDeduction = "deduction" // A digit follows from a logical technique
+	HintGuess     = "guess"     // Logic is stuck, so a digit of the solution is given
+)
+
+// Hint is the next move suggested to a player.
+type Hint struct {
+	Kind      string // HintMistake, HintDeduction, or HintGuess
+	Pos       string // Cell of the move
+	Digit     rune   // Digit to place (0 for a mistake, which is only cleared)
+	Technique string // Technique that finds the digit, for a deduction
+	Reason    string // Human-readable explanation of the move
+}
+
+// NextHint returns the next move for a player who has filled progress starting from puzzle. Progress must keep
+// every clue of the puzzle, and the puzzle must have a unique solution. It returns an error if either is not the
+// case, or if progress is already the solution.
+func NextHint(puzzle, progress map[string]rune) (Hint, error) {
+	shape := ShapeOf(puzzle)
+	if ShapeOf(progress) != shape {
+		return Hint{}, fmt.Errorf("the progress is a %dx%d grid, the puzzle a %dx%d one", ShapeOf(progress).Size, ShapeOf(progress).Size, shape.Size, shape.Size)
+	}
+	for _, pos := range shape.Cells() {
+		if clue := puzzle[pos]; clue != '.' && progress[pos] != clue {
+			return Hint{}, fmt.Errorf("cell %s should keep the clue %c", pos, clue)
+		}
+	}
+	solution, ok := SolveSudoku(puzzle)
+	if !ok {
+		return Hint{}, fmt.Errorf("the puzzle doesn't have a unique solution")
+	}
+
+	empty := 0
+	for _, pos := range shape.Cells() { // Mistakes first, in row-major order
+		switch digit := progress[pos]; {
+		case digit == '.':
+			empty++
+		case digit != solution[pos]:
+			return Hint{Kind: HintMistake, Pos: pos, Reason: fmt.Sprintf("the %c in %s is wrong; clear it before going on", digit, pos)}, nil
+		}
+	}
+	if empty == 0 {
+		return Hint{}, fmt.Errorf("the puzzle is already solved")
+	}
+
+	candidates := computeCandidates(progress)
+	for _, t := range techniques { // Always suggest the simplest technique that works
+		if deduction, ok := t.find(progress, candidates); ok {
+			return Hint{Kind: HintDeduction, Pos: deduction.Pos, Digit: deduction.Digit, Technique: deduction.Technique, Reason: deduction.Reason}, nil
+		}
+	}
+	pos, fewest := "", shape.Size+1
+	for _, cell := range shape.Cells() {
+		if options, ok := candidates[cell]; ok && len(options) < fewest {
+			pos, fewest = cell, len(options)
+		}
+	}
+	return Hint{Kind: HintGuess, Pos: pos, Digit: solution[pos], Reason: fmt.Sprintf("no technique applies; %s has the fewest candidates (%d), and the solution has %c there", pos, fewest, solution[pos])}, nil
+}
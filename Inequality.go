@@ -0,0 +1,125 @@
+/*
+This file adds greater-than (inequality) Sudoku: signs between two side-by-side cells tell which of the two
+digits is larger. With enough signs, a grid needs no givens at all, as in Futoshiki.
+
+Signs are read from a clue file (see Clues.go) as two cells with '<' or '>' between them:
+
+	A1 < A2
+	B4 > C4
+
+Functions:
+- **`NewInequalityConstraint`**: Builds the constraint enforcing every sign.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Inequality says that the digit of Less is smaller than the digit of Greater.
+type Inequality struct {
+	Less    string // The cell with the smaller digit
+	Greater string // The cell with the larger digit
+}
+
+// parseInequality reads a sign between two cells, such as "A1 < A2" or "B4 > C4". It returns false if text
+// isn't a sign at all.
+func parseInequality(text string, shape Shape) (Inequality, bool, error) {
+	sign := strings.IndexAny(text, "<>")
+	if sign < 0 {
+		return Inequality{}, false, nil
+	}
+	left, right := strings.TrimSpace(text[:sign]), strings.TrimSpace(text[sign+1:])
+	for _, pos := range []string{left, right} {
+		if _, _, ok := shape.ParsePos(pos); !ok {
+			return Inequality{}, true, fmt.Errorf("%q is not a cell of the board", pos)
+		}
+	}
+	if err := checkDot(Dot{A: left, B: right}, shape); err != nil { // Signs sit between side-by-side cells, like dots
+		return Inequality{}, true, fmt.Errorf("the cells %s and %s of a sign must be side by side", left, right)
+	}
+	if text[sign] == '>' {
+		left, right = right, left
+	}
+	return Inequality{Less: left, Greater: right}, true, nil
+}
+
+// InequalityConstraint requires the digits on either side of every sign to compare as the sign says.
+type InequalityConstraint struct {
+	shape        Shape
+	inequalities []Inequality
+	peers        map[string][]string // The cells each cell is compared with
+}
+
+// NewInequalityConstraint creates the constraint for the given signs on a board of the given shape.
+func NewInequalityConstraint(shape Shape, inequalities []Inequality) *InequalityConstraint {
+	c := &InequalityConstraint{
+		shape:        shape,
+		inequalities: inequalities,
+		peers:        make(map[string][]string),
+	}
+	for _, inequality := range inequalities {
+		c.peers[inequality.Less] = append(c.peers[inequality.Less], inequality.Greater)
+		c.peers[inequality.Greater] = append(c.peers[inequality.Greater], inequality.Less)
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *InequalityConstraint) Name() string {
+	return "inequalities"
+}
+
+// Allows reports whether digit at pos compares correctly with every filled cell it is compared with.
+func (c *InequalityConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	value := digitValue(c.shape, digit)
+	for _, inequality := range c.inequalities {
+		switch pos {
+		case inequality.Less:
+			if val := grid[inequality.Greater]; val != '.' && val != 0 && digitValue(c.shape, val) <= value {
+				return false
+			}
+		case inequality.Greater:
+			if val := grid[inequality.Less]; val != '.' && val != 0 && digitValue(c.shape, val) >= value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Peers returns the cells pos is compared with.
+func (c *InequalityConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate narrows candidates by bounds: the smaller cell of a sign keeps only digits below the largest
+// possible digit of the larger cell, and the larger cell only digits above the smallest possible digit of the
+// smaller one. Chains of signs tighten over the repeated calls of the search.
+func (c *InequalityConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, inequality := range c.inequalities { // Signs are visited in order, so eliminations are deterministic
+		lessLow, _, okLess := valueBounds(c.shape, grid, candidates, inequality.Less)
+		_, greaterHigh, okGreater := valueBounds(c.shape, grid, candidates, inequality.Greater)
+		if !okLess || !okGreater || lessLow >= greaterHigh { // No pair of digits can satisfy the sign
+			return nil, false
+		}
+		if grid[inequality.Less] == '.' {
+			for _, digit := range candidates[inequality.Less] {
+				if digitValue(c.shape, digit) >= greaterHigh {
+					eliminations = append(eliminations, Elimination{Pos: inequality.Less, Digit: digit})
+				}
+			}
+		}
+		if grid[inequality.Greater] == '.' {
+			for _, digit := range candidates[inequality.Greater] {
+				if digitValue(c.shape, digit) <= lessLow {
+					eliminations = append(eliminations, Elimination{Pos: inequality.Greater, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
@@ -0,0 +1,93 @@
+/*
+This file adds Jigsaw Sudoku (also called irregular Sudoku), where the boxes are replaced by regions of any
+connected shape. Every region has as many cells as the board has digits, and must contain each digit exactly once.
+
+Regions are described by a region map: one label per cell, read row by row like the grid itself (81 labels for a
+9x9 board). Cells with the same label belong to the same region, and any character other than a space can be a
+label. For example, the classic 3x3 boxes would be:
+
+	111222333111222333111222333444555666444555666444555666777888999777888999777888999
+
+Functions:
+- **`ParseRegions`**: Reads a region map and checks that its regions fit the board and are connected.
+- **`WithRegions`**: Replaces the boxes in a list of constraints with the regions of a region map.
+*/
+
+package sudokux
+
+import "fmt"
+
+// ParseRegions reads a region map (one label per cell, row by row) and returns the cells of each region, ordered
+// by the first cell of each region in row-major order. There must be exactly shape.Size regions of shape.Size
+// cells each, and the cells of every region must be connected orthogonally.
+func ParseRegions(regionMap string, shape Shape) ([][]string, error) {
+	labels := []rune(regionMap)
+	if len(labels) != shape.Size*shape.Size {
+		return nil, fmt.Errorf("expected %d region labels, got %d", shape.Size*shape.Size, len(labels))
+	}
+
+	var regions [][]string
+	index := make(map[rune]int) // Index in regions of each label
+	for i, label := range labels {
+		if label == ' ' {
+			return nil, fmt.Errorf("region labels can't be spaces (found one at %s)", shape.Pos(i/shape.Size, i%shape.Size))
+		}
+		if _, ok := index[label]; !ok {
+			index[label] = len(regions)
+			regions = append(regions, nil)
+		}
+		regions[index[label]] = append(regions[index[label]], shape.Pos(i/shape.Size, i%shape.Size))
+	}
+	if len(regions) != shape.Size {
+		return nil, fmt.Errorf("expected %d regions, got %d", shape.Size, len(regions))
+	}
+
+	for _, region := range regions {
+		if len(region) != shape.Size {
+			return nil, fmt.Errorf("the region containing %s has %d cells instead of %d", region[0], len(region), shape.Size)
+		}
+		if !connected(region, shape) {
+			return nil, fmt.Errorf("the region containing %s is not connected", region[0])
+		}
+	}
+	return regions, nil
+}
+
+// connected reports whether every cell of region can be reached from its first cell through orthogonally adjacent
+// cells of the region.
+func connected(region []string, shape Shape) bool {
+	inRegion := make(map[string]bool)
+	for _, pos := range region {
+		inRegion[pos] = true
+	}
+	reached := map[string]bool{region[0]: true}
+	queue := []string{region[0]}
+	for len(queue) > 0 {
+		row, col, _ := shape.ParsePos(queue[0])
+		queue = queue[1:]
+		for _, step := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			r, c := row+step[0], col+step[1]
+			if r < 0 || r >= shape.Size || c < 0 || c >= shape.Size {
+				continue // Off the board
+			}
+			if next := shape.Pos(r, c); inRegion[next] && !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return len(reached) == len(region)
+}
+
+// WithRegions returns a copy of constraints where the boxes (the "subgrids" constraint of ClassicConstraintsFor)
+// are replaced by the given regions, so that Jigsaw puzzles combine with the rules of other variants.
+func WithRegions(constraints []Constraint, regions [][]string) []Constraint {
+	result := make([]Constraint, 0, len(constraints))
+	for _, constraint := range constraints {
+		if constraint.Name() == "subgrids" {
+			constraint = NewRegionConstraint("regions", regions)
+		}
+		result = append(result, constraint)
+	}
+	return result
+}
@@ -0,0 +1,96 @@
+/*
+This file provides undo and redo for a grid being played. A `Journal` wraps a grid together with the pencil marks of
+its cells, and records every change made through it: moves (see Moves.go) and pencil-mark changes alike. Undoing a
+change puts the cell back as it was, and redoing it makes the change again; making a new change after undoing
+discards the changes that could have been redone, like in a text editor.
+
+Functions:
+- **`NewJournal`**: Starts a journal on a copy of a grid, with no pencil marks.
+- **`Apply`** / **`SetMarks`**: Make a move or change the pencil marks of a cell, recording the change.
+- **`Undo`** / **`Redo`**: Revert the last change, or make the last reverted change again.
+*/
+
+package sudokux
+
+// Journal is a grid with pencil marks and a history of the changes made to them.
+type Journal struct {
+	Grid  Grid                    // Current contents of the grid (change it through the journal to record the changes)
+	Marks map[string]CandidateSet // Pencil marks of the cells that have some
+
+	undone []journalEntry // Changes that can be undone, the most recent last
+	redone []journalEntry // Changes that were undone and can be redone, the most recently undone last
+}
+
+// journalEntry records how one cell changed: its digit and its pencil marks before and after.
+type journalEntry struct {
+	pos                string
+	oldDigit, newDigit rune
+	oldMarks, newMarks CandidateSet
+}
+
+// NewJournal returns a journal for a copy of grid, with no pencil marks and no history.
+func NewJournal(grid Grid) *Journal {
+	return &Journal{Grid: grid.Clone(), Marks: make(map[string]CandidateSet)}
+}
+
+// Apply makes move on the grid (see Grid.Apply) and records it. An illegal move is refused with the error of
+// Grid.Check and isn't recorded.
+func (j *Journal) Apply(move Move) error {
+	old := j.Grid[move.Pos]
+	if err := j.Grid.Apply(move); err != nil {
+		return err
+	}
+	marks := j.Marks[move.Pos]
+	j.record(journalEntry{pos: move.Pos, oldDigit: old, newDigit: move.Digit, oldMarks: marks, newMarks: marks})
+	return nil
+}
+
+// SetMarks replaces the pencil marks of the cell at pos with marks (0 removes them) and records the change.
+func (j *Journal) SetMarks(pos string, marks CandidateSet) {
+	digit := j.Grid[pos]
+	j.record(journalEntry{pos: pos, oldDigit: digit, newDigit: digit, oldMarks: j.Marks[pos], newMarks: marks})
+	j.setMarks(pos, marks)
+}
+
+// Undo reverts the most recent change that hasn't been undone. It returns the move putting the cell's digit back
+// (which doesn't change the digit if only the pencil marks changed), or false if there is nothing to undo.
+func (j *Journal) Undo() (Move, bool) {
+	if len(j.undone) == 0 {
+		return Move{}, false
+	}
+	entry := j.undone[len(j.undone)-1]
+	j.undone = j.undone[:len(j.undone)-1]
+	j.redone = append(j.redone, entry)
+	j.Grid[entry.pos] = entry.oldDigit
+	j.setMarks(entry.pos, entry.oldMarks)
+	return Move{Pos: entry.pos, Digit: entry.oldDigit}, true
+}
+
+// Redo makes the most recently undone change again. It returns the move setting the cell's digit, or false if
+// there is nothing to redo.
+func (j *Journal) Redo() (Move, bool) {
+	if len(j.redone) == 0 {
+		return Move{}, false
+	}
+	entry := j.redone[len(j.redone)-1]
+	j.redone = j.redone[:len(j.redone)-1]
+	j.undone = append(j.undone, entry)
+	j.Grid[entry.pos] = entry.newDigit
+	j.setMarks(entry.pos, entry.newMarks)
+	return Move{Pos: entry.pos, Digit: entry.newDigit}, true
+}
+
+// record adds a new change to the history, which makes the undone changes impossible to redo.
+func (j *Journal) record(entry journalEntry) {
+	j.undone = append(j.undone, entry)
+	j.redone = nil
+}
+
+// setMarks sets the pencil marks of a cell, dropping cells left without any.
+func (j *Journal) setMarks(pos string, marks CandidateSet) {
+	if marks == 0 {
+		delete(j.Marks, pos)
+		return
+	}
+	j.Marks[pos] = marks
+}
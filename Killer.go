@@ -0,0 +1,264 @@
+/*
+This file adds Killer Sudoku: the grid is divided into cages, groups of cells whose digits must add up to the
+cage's sum without repeating. Killer puzzles usually come with few givens or none at all, so the cages carry
+most of the information.
+
+Cages are read from a clue file (see Clues.go) with one cage per line: the sum, a colon, and the cells of the cage
+separated by spaces or commas. Blank lines and lines starting with '#' are ignored:
+
+	# The top-left corner
+	15: A1 A2 B1
+	7: A3, A4
+
+On boards larger than 9x9, letters count as the digits after 9 (A is 10, B is 11, and so on).
+
+Functions:
+- **`ParseCages`**: Reads cages from a text file and checks that they fit the board.
+- **`NewKillerConstraint`**: Builds the constraint enforcing the sum of every cage and forbidding repeats within it.
+- **`KillerConstraint.CageOf`**: Returns the index of the cage containing a cell, which is useful to render cage boundaries.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"io"
+)
+
+// Cage is a group of cells whose digits must add up to Sum without repeating.
+type Cage struct {
+	Sum   int      // Target sum of the digits in the cage
+	Cells []string // Cells of the cage (e.g. "A1")
+}
+
+// ParseCages reads cages from r (one "sum: cells" line per cage, see Clues.go) and checks them against the board:
+// every cell must be on the board and in at most one cage, and every sum must be reachable with distinct digits.
+// Other clues in the file are ignored.
+func ParseCages(r io.Reader, shape Shape) ([]Cage, error) {
+	clues, err := ParseClues(r, shape)
+	if err != nil {
+		return nil, err
+	}
+	return clues.Cages, nil
+}
+
+// checkCage checks that the digits of cage can be distinct and add up to its sum.
+func checkCage(cage Cage, shape Shape) error {
+	if len(cage.Cells) > shape.Size {
+		return fmt.Errorf("a cage of %d cells can't avoid repeating a digit", len(cage.Cells))
+	}
+	if low, high := sumRange(len(cage.Cells), shape.Size); cage.Sum < low || cage.Sum > high {
+		return fmt.Errorf("%d distinct digits can't add up to %d (the sum must be between %d and %d)",
+			len(cage.Cells), cage.Sum, low, high)
+	}
+	return nil
+}
+
+// sumRange returns the smallest and largest sums of count distinct digits among 1 to size.
+func sumRange(count, size int) (int, int) {
+	low := count * (count + 1) / 2         // 1 + 2 + ... + count
+	high := count*size - count*(count-1)/2 // size + (size-1) + ... + (size-count+1)
+	return low, high
+}
+
+// KillerConstraint requires the digits of every cage to add up to the cage's sum without repeating.
+type KillerConstraint struct {
+	shape  Shape
+	cages  []Cage
+	byCell map[string]int      // Index of the cage containing each cell
+	peers  map[string][]string // The other cells of the cage containing each cell
+}
+
+// NewKillerConstraint creates the constraint for the given cages on a board of the given shape. Cages must not
+// overlap (ParseCages checks this).
+func NewKillerConstraint(shape Shape, cages []Cage) *KillerConstraint {
+	c := &KillerConstraint{
+		shape:  shape,
+		cages:  cages,
+		byCell: make(map[string]int),
+		peers:  make(map[string][]string),
+	}
+	for index, cage := range cages {
+		for _, pos := range cage.Cells {
+			c.byCell[pos] = index
+			for _, other := range cage.Cells {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *KillerConstraint) Name() string {
+	return "cages"
+}
+
+// Cages returns the cages of the constraint.
+func (c *KillerConstraint) Cages() []Cage {
+	return c.cages
+}
+
+// CageOf returns the index in Cages() of the cage containing pos, or -1 if pos is in no cage.
+func (c *KillerConstraint) CageOf(pos string) int {
+	if index, ok := c.byCell[pos]; ok {
+		return index
+	}
+	return -1
+}
+
+// Allows reports whether digit can go at pos without repeating within its cage, and whether the cage's sum can
+// still be reached afterwards with distinct digits in its remaining empty cells.
+func (c *KillerConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	index, ok := c.byCell[pos]
+	if !ok { // Cells outside every cage are free
+		return true
+	}
+	used := make([]bool, c.shape.Size+1) // Digit values already in the cage, including the new one
+	value := c.value(digit)
+	used[value] = true
+	total, empty := value, 0
+	for _, other := range c.cages[index].Cells {
+		if other == pos {
+			continue
+		}
+		val := grid[other]
+		if val == '.' || val == 0 {
+			empty++
+			continue
+		}
+		if val == digit { // The digit is already used in this cage
+			return false
+		}
+		used[c.value(val)] = true
+		total += c.value(val)
+	}
+	return c.reachable(c.cages[index].Sum-total, empty, used)
+}
+
+// Peers returns the other cells of the cage containing pos.
+func (c *KillerConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate keeps only the candidates that take part in at least one combination of distinct digits adding up
+// to what is left of each cage's sum. It returns false if a cage has no such combination left.
+func (c *KillerConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, cage := range c.cages { // Cages and their cells are visited in order, so eliminations are deterministic
+		var empty []string
+		used := make([]bool, c.shape.Size+1)
+		remaining := cage.Sum
+		for _, pos := range cage.Cells {
+			if val := grid[pos]; val != '.' && val != 0 {
+				used[c.value(val)] = true
+				remaining -= c.value(val)
+			} else {
+				empty = append(empty, pos)
+			}
+		}
+		if len(empty) == 0 {
+			if remaining != 0 { // A full cage with the wrong sum
+				return nil, false
+			}
+			continue
+		}
+
+		// Collect the digits of every combination that fits the sum and the candidates of the empty cells
+		possible := make([]bool, c.shape.Size+1)
+		found := false
+		combination := make([]int, 0, len(empty))
+		var search func(next, left int)
+		search = func(next, left int) {
+			if len(combination) == len(empty) {
+				if left == 0 && c.fits(combination, empty, candidates) {
+					found = true
+					for _, value := range combination {
+						possible[value] = true
+					}
+				}
+				return
+			}
+			for value := next; value <= c.shape.Size && value <= left; value++ {
+				if !used[value] {
+					combination = append(combination, value)
+					search(value+1, left-value)
+					combination = combination[:len(combination)-1]
+				}
+			}
+		}
+		search(1, remaining)
+		if !found {
+			return nil, false
+		}
+		for _, pos := range empty {
+			for _, digit := range candidates[pos] {
+				if !possible[c.value(digit)] {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
+
+// fits reports whether every digit of combination is a candidate of one of the empty cells, and every empty cell
+// has a candidate among the digits of combination.
+func (c *KillerConstraint) fits(combination []int, empty []string, candidates map[string][]rune) bool {
+	inCombination := make([]bool, c.shape.Size+1)
+	for _, value := range combination {
+		inCombination[value] = true
+	}
+	covered := make([]bool, c.shape.Size+1)
+	for _, pos := range empty {
+		hasCandidate := false
+		for _, digit := range candidates[pos] {
+			if value := c.value(digit); inCombination[value] {
+				covered[value] = true
+				hasCandidate = true
+			}
+		}
+		if !hasCandidate {
+			return false
+		}
+	}
+	for _, value := range combination {
+		if !covered[value] {
+			return false
+		}
+	}
+	return true
+}
+
+// reachable reports whether count distinct digits outside used can add up to sum.
+func (c *KillerConstraint) reachable(sum, count int, used []bool) bool {
+	low, high, free := 0, 0, 0
+	for value := 1; value <= c.shape.Size && free < count; value++ { // The smallest free digits
+		if !used[value] {
+			low += value
+			free++
+		}
+	}
+	if free < count { // Not enough digits left to fill the empty cells
+		return false
+	}
+	for value, taken := c.shape.Size, 0; value >= 1 && taken < count; value-- { // The largest free digits
+		if !used[value] {
+			high += value
+			taken++
+		}
+	}
+	return low <= sum && sum <= high
+}
+
+// value returns the numeric value of a digit (1 for '1', 10 for 'A').
+func (c *KillerConstraint) value(digit rune) int {
+	return digitValue(c.shape, digit)
+}
+
+// digitValue returns the numeric value of a digit of the board (1 for '1', 10 for 'A'), for constraints on sums.
+func digitValue(shape Shape, digit rune) int {
+	return shape.DigitIndex(digit) + 1
+}
@@ -0,0 +1,177 @@
+/*
+This file adds Kropki dots, placed between two orthogonally adjacent cells:
+- A **white dot** means the two digits are consecutive (e.g. 4 and 5).
+- A **black dot** means one digit is twice the other (e.g. 3 and 6).
+
+Between 1 and 2 either dot may appear. When a puzzle says that all dots are given (the negative constraint), two
+adjacent cells without a dot may be neither consecutive nor in a 1:2 ratio.
+
+Dots are read from a clue file (see Clues.go) as the color, a colon, and the two cells. The line "kropki: all"
+turns on the negative constraint:
+
+	white: A1 A2
+	black: B4, C4
+	kropki: all
+
+Functions:
+- **`NewKropkiConstraint`**: Builds the constraint enforcing the dots, and optionally the negative constraint.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Colors of Kropki dots.
+const (
+	DotWhite = "white" // The digits are consecutive
+	DotBlack = "black" // One digit is twice the other
+)
+
+// Dot is a Kropki dot between two orthogonally adjacent cells.
+type Dot struct {
+	Color string // DotWhite or DotBlack
+	A, B  string // The two cells on either side of the dot
+}
+
+// checkDot checks that the two cells of dot are orthogonally adjacent.
+func checkDot(dot Dot, shape Shape) error {
+	rowA, colA, _ := shape.ParsePos(dot.A)
+	rowB, colB, _ := shape.ParsePos(dot.B)
+	if distance := abs(rowA-rowB) + abs(colA-colB); distance != 1 {
+		return fmt.Errorf("the cells %s and %s of a dot must be side by side", dot.A, dot.B)
+	}
+	return nil
+}
+
+// kropkiRule is the relation a cell must keep with one of its neighbors.
+type kropkiRule struct {
+	other int    // Index of the neighbor in cells
+	color string // DotWhite, DotBlack, or "" for no dot (negative constraint)
+}
+
+// KropkiConstraint enforces Kropki dots between adjacent cells.
+type KropkiConstraint struct {
+	shape Shape
+	cells []string            // Every cell in row-major order, so eliminations are deterministic
+	index map[string]int      // Index of each cell in cells
+	rules [][]kropkiRule      // The relations each cell must keep, by index
+	peers map[string][]string // The neighbors each cell has a relation with
+}
+
+// NewKropkiConstraint creates the constraint for the given dots on a board of the given shape. With allGiven,
+// adjacent cells without a dot must be neither consecutive nor in a 1:2 ratio.
+func NewKropkiConstraint(shape Shape, dots []Dot, allGiven bool) *KropkiConstraint {
+	c := &KropkiConstraint{
+		shape: shape,
+		cells: shape.Cells(),
+		index: make(map[string]int),
+		rules: make([][]kropkiRule, shape.Size*shape.Size),
+		peers: make(map[string][]string),
+	}
+	for i, pos := range c.cells {
+		c.index[pos] = i
+	}
+	colors := make(map[[2]int]string) // Color of the dot between two cells, by index
+	for _, dot := range dots {
+		a, b := c.index[dot.A], c.index[dot.B]
+		colors[[2]int{a, b}], colors[[2]int{b, a}] = dot.Color, dot.Color
+	}
+	for row := 0; row < shape.Size; row++ {
+		for col := 0; col < shape.Size; col++ {
+			i := row*shape.Size + col
+			for _, step := range [][2]int{{-1, 0}, {0, -1}, {0, 1}, {1, 0}} {
+				r, col2 := row+step[0], col+step[1]
+				if r < 0 || r >= shape.Size || col2 < 0 || col2 >= shape.Size {
+					continue
+				}
+				j := r*shape.Size + col2
+				color, dotted := colors[[2]int{i, j}]
+				if dotted || allGiven {
+					c.rules[i] = append(c.rules[i], kropkiRule{other: j, color: color})
+					c.peers[c.cells[i]] = append(c.peers[c.cells[i]], c.cells[j])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *KropkiConstraint) Name() string {
+	return "kropki"
+}
+
+// Allows reports whether digit at pos keeps the relation with every filled neighbor.
+func (c *KropkiConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	value := digitValue(c.shape, digit)
+	for _, rule := range c.rules[c.index[pos]] {
+		if val := grid[c.cells[rule.other]]; val != '.' && val != 0 && !kropkiHolds(rule.color, value, digitValue(c.shape, val)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the neighbors of pos it has a relation with.
+func (c *KropkiConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate removes a candidate from a cell when an empty neighbor has no candidate that keeps their relation.
+func (c *KropkiConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	values := make([][]int, len(c.cells)) // Candidate values of each empty cell (nil if filled)
+	for i, pos := range c.cells {
+		if grid[pos] == '.' {
+			for _, digit := range candidates[pos] {
+				values[i] = append(values[i], digitValue(c.shape, digit))
+			}
+		}
+	}
+
+	var eliminations []Elimination
+	for i, pos := range c.cells {
+		if values[i] == nil {
+			continue
+		}
+		for k, value := range values[i] {
+			for _, rule := range c.rules[i] {
+				if values[rule.other] == nil {
+					continue // Filled neighbors are handled by Allows
+				}
+				compatible := false
+				for _, other := range values[rule.other] {
+					if other != value && kropkiHolds(rule.color, value, other) {
+						compatible = true
+						break
+					}
+				}
+				if !compatible {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: candidates[pos][k]})
+					break
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
+
+// kropkiHolds reports whether two digit values satisfy the relation of a dot of the given color ("" for no dot).
+func kropkiHolds(color string, a, b int) bool {
+	consecutive := a-b == 1 || b-a == 1
+	double := a == 2*b || b == 2*a
+	switch color {
+	case DotWhite:
+		return consecutive
+	case DotBlack:
+		return double
+	}
+	return !consecutive && !double
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
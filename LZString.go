@@ -0,0 +1,204 @@
+/*
+This file implements the base64 flavor of lz-string, the compression f-puzzles and SudokuPad use to fit a whole
+puzzle in a link (see FPuzzles.go). lz-string works on UTF-16 code units, as JavaScript strings do, and writes
+6 bits per character of its output.
+
+Functions:
+- **`lzCompress`**: Compresses a string to the characters of a link.
+- **`lzDecompress`**: Reverses lzCompress, accepting the base64 and the URI-safe alphabets of lz-string.
+*/
+
+package sudokux
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// lzAlphabet holds the 64 characters of the output of lzCompress, plus its padding.
+const lzAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// errLZData is returned by lzDecompress for data lz-string didn't write.
+var errLZData = errors.New("invalid compressed data")
+
+// lzWriter packs values into characters of lzAlphabet, 6 bits at a time.
+type lzWriter struct {
+	out      strings.Builder
+	val, pos int // Bits of the character being written, and how many there are
+}
+
+// write writes the numBits lowest bits of value, lowest first.
+func (w *lzWriter) write(value, numBits int) {
+	for i := 0; i < numBits; i++ {
+		w.val = w.val<<1 | value&1
+		value >>= 1
+		if w.pos++; w.pos == 6 {
+			w.out.WriteByte(lzAlphabet[w.val])
+			w.val, w.pos = 0, 0
+		}
+	}
+}
+
+// lzCompress compresses s as lz-string's compressToBase64 does.
+func lzCompress(s string) string {
+	units := utf16.Encode([]rune(s))
+	key := func(units []uint16) string { // Dictionary key of a sequence of code units
+		b := make([]byte, 0, 2*len(units))
+		for _, u := range units {
+			b = append(b, byte(u>>8), byte(u))
+		}
+		return string(b)
+	}
+	dictionary := make(map[string]int)
+	pending := make(map[string]bool) // Single units added to the dictionary but not yet written
+	dictSize, numBits, enlargeIn := 3, 2, 2
+	var w lzWriter
+	grow := func() {
+		if enlargeIn--; enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+	var word []uint16
+	emit := func() { // Writes the code of word, preceded by its unit if it is new
+		if k := key(word); pending[k] {
+			if word[0] < 256 {
+				w.write(0, numBits)
+				w.write(int(word[0]), 8)
+			} else {
+				w.write(1, numBits)
+				w.write(int(word[0]), 16)
+			}
+			grow()
+			delete(pending, k)
+		} else {
+			w.write(dictionary[k], numBits)
+		}
+		grow()
+	}
+	for _, unit := range units {
+		c := []uint16{unit}
+		if _, ok := dictionary[key(c)]; !ok {
+			dictionary[key(c)] = dictSize
+			dictSize++
+			pending[key(c)] = true
+		}
+		wc := append(append([]uint16(nil), word...), unit)
+		if _, ok := dictionary[key(wc)]; ok {
+			word = wc
+			continue
+		}
+		emit()
+		dictionary[key(wc)] = dictSize
+		dictSize++
+		word = c
+	}
+	if len(word) > 0 {
+		emit()
+	}
+	w.write(2, numBits) // End of the stream
+	w.write(0, 1)       // Flush the last character, which is a whole character of zeros if the stream ended on one
+	for w.pos != 0 {
+		w.write(0, 1)
+	}
+	out := w.out.String()
+	return out + strings.Repeat("=", (4-len(out)%4)%4)
+}
+
+// lzValue returns the 6 bits of the character c of a compressed string, in either alphabet of lz-string. Spaces
+// stand for the '+' that a link decoded as a form turns into spaces.
+func lzValue(c byte) (int, bool) {
+	switch c {
+	case '+', ' ':
+		return 62, true
+	case '/', '-':
+		return 63, true
+	case '=', '$':
+		return 64, true
+	}
+	i := strings.IndexByte(lzAlphabet[:62], c)
+	return i, i >= 0
+}
+
+// lzDecompress decompresses s as lz-string's decompressFromBase64 and decompressFromEncodedURIComponent do.
+func lzDecompress(s string) (string, error) {
+	if s == "" {
+		return "", errLZData
+	}
+	index, val, position := 0, 0, 0
+	var bad bool
+	next := func() { // Loads the next character, reading past the end as zeros
+		val, position = 0, 32
+		if index < len(s) {
+			v, ok := lzValue(s[index])
+			val, bad = v, bad || !ok
+		}
+		index++
+	}
+	next()
+	read := func(numBits int) int {
+		bits := 0
+		for power := 0; power < numBits; power++ {
+			if val&position != 0 {
+				bits |= 1 << power
+			}
+			if position >>= 1; position == 0 {
+				next()
+			}
+		}
+		return bits
+	}
+
+	dictionary := [][]uint16{nil, nil, nil}
+	enlargeIn, numBits := 4, 3
+	var c []uint16
+	switch read(2) {
+	case 0:
+		c = []uint16{uint16(read(8))}
+	case 1:
+		c = []uint16{uint16(read(16))}
+	default:
+		return "", errLZData
+	}
+	dictionary = append(dictionary, c)
+	word, result := c, append([]uint16(nil), c...)
+	for {
+		if index > len(s) || bad {
+			return "", errLZData
+		}
+		code := read(numBits)
+		switch code {
+		case 0, 1:
+			size := 8
+			if code == 1 {
+				size = 16
+			}
+			dictionary = append(dictionary, []uint16{uint16(read(size))})
+			code = len(dictionary) - 1
+			enlargeIn--
+		case 2:
+			return string(utf16.Decode(result)), nil
+		}
+		if enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+		var entry []uint16
+		switch {
+		case code < len(dictionary) && dictionary[code] != nil:
+			entry = dictionary[code]
+		case code == len(dictionary):
+			entry = append(append([]uint16(nil), word...), word[0])
+		default:
+			return "", errLZData
+		}
+		result = append(result, entry...)
+		dictionary = append(dictionary, append(append([]uint16(nil), word...), entry[0]))
+		word = entry
+		if enlargeIn--; enlargeIn == 0 {
+			enlargeIn = 1 << numBits
+			numBits++
+		}
+	}
+}
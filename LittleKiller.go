@@ -0,0 +1,133 @@
+/*
+This file adds Little Killer clues: an arrow outside the grid points along a diagonal, and the digits on that
+diagonal must add up to the clue's sum. Unlike Killer cages, digits may repeat along the diagonal.
+
+Little Killer clues are read from a clue file (see Clues.go) as "little" and the sum, a colon, the first cell of
+the diagonal (on the edge of the grid, next to the arrow), and the direction the arrow points in: SE (down and to
+the right), SW (down and to the left), NE, or NW:
+
+	little 23: A3 SE
+	little 9: I2 NE
+
+Functions:
+- **`NewLittleKillerConstraint`**: Builds the constraint enforcing the sum of every diagonal.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diagonal is a Little Killer clue: the digits of Cells must add up to Sum.
+type Diagonal struct {
+	Sum   int      // Target sum of the digits along the diagonal
+	Cells []string // The cells of the diagonal, from the edge next to the arrow
+}
+
+// diagonalSteps maps each direction of a Little Killer arrow to its step in rows and columns.
+var diagonalSteps = map[string][2]int{"SE": {1, 1}, "SW": {1, -1}, "NE": {-1, 1}, "NW": {-1, -1}}
+
+// parseDiagonal reads the sum of a Little Killer clue and its diagonal, given as the first cell and a direction
+// such as "A3 SE". The first cell must be on the edge the arrow points from.
+func parseDiagonal(sumText, text string, shape Shape) (Diagonal, error) {
+	sum, err := strconv.Atoi(sumText)
+	if err != nil {
+		return Diagonal{}, fmt.Errorf("invalid sum %q", sumText)
+	}
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return Diagonal{}, fmt.Errorf("expected the first cell and a direction (such as \"A3 SE\"), got %q", strings.TrimSpace(text))
+	}
+	row, col, ok := shape.ParsePos(fields[0])
+	if !ok {
+		return Diagonal{}, fmt.Errorf("%s is not a cell of the board", fields[0])
+	}
+	step, ok := diagonalSteps[strings.ToUpper(fields[1])]
+	if !ok {
+		return Diagonal{}, fmt.Errorf("unknown direction %q (expected SE, SW, NE, or NW)", fields[1])
+	}
+	if inside(shape, row-step[0], col-step[1]) { // The arrow sits just before the first cell, outside the grid
+		return Diagonal{}, fmt.Errorf("the diagonal from %s going %s doesn't start on the edge of the grid", fields[0], strings.ToUpper(fields[1]))
+	}
+
+	diagonal := Diagonal{Sum: sum}
+	for r, c := row, col; inside(shape, r, c); r, c = r+step[0], c+step[1] {
+		diagonal.Cells = append(diagonal.Cells, shape.Pos(r, c))
+	}
+	if low, high := len(diagonal.Cells), len(diagonal.Cells)*shape.Size; sum < low || sum > high {
+		return Diagonal{}, fmt.Errorf("%d digits can't add up to %d (the sum must be between %d and %d)", len(diagonal.Cells), sum, low, high)
+	}
+	return diagonal, nil
+}
+
+// inside reports whether the cell at row and col is on the board.
+func inside(shape Shape, row, col int) bool {
+	return row >= 0 && row < shape.Size && col >= 0 && col < shape.Size
+}
+
+// LittleKillerConstraint requires the digits along every Little Killer diagonal to add up to its sum.
+type LittleKillerConstraint struct {
+	shape     Shape
+	diagonals []Diagonal
+	byCell    map[string][]int    // Indexes of the diagonals each cell is on
+	peers     map[string][]string // The other cells of the diagonals containing each cell
+}
+
+// NewLittleKillerConstraint creates the constraint for the given diagonals on a board of the given shape.
+func NewLittleKillerConstraint(shape Shape, diagonals []Diagonal) *LittleKillerConstraint {
+	c := &LittleKillerConstraint{
+		shape:     shape,
+		diagonals: diagonals,
+		byCell:    make(map[string][]int),
+		peers:     make(map[string][]string),
+	}
+	for index, diagonal := range diagonals {
+		for _, pos := range diagonal.Cells {
+			c.byCell[pos] = append(c.byCell[pos], index)
+			for _, other := range diagonal.Cells {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *LittleKillerConstraint) Name() string {
+	return "little killer"
+}
+
+// Allows reports whether digit can go at pos while every diagonal through pos can still reach its sum.
+func (c *LittleKillerConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	for _, index := range c.byCell[pos] {
+		diagonal := c.diagonals[index]
+		if low, high := partialSum(c.shape, grid, diagonal.Cells, pos, digit); diagonal.Sum < low || diagonal.Sum > high {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the other cells of the diagonals through pos.
+func (c *LittleKillerConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate narrows the candidates along every diagonal by bounds, like the cells of an arrow (see Arrow.go).
+// It returns false if a diagonal can no longer reach its sum.
+func (c *LittleKillerConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, diagonal := range c.diagonals { // Diagonals are visited in order, so eliminations are deterministic
+		narrowed, _, _, ok := narrowSum(c.shape, grid, candidates, diagonal.Cells, diagonal.Sum, diagonal.Sum)
+		if !ok {
+			return nil, false
+		}
+		eliminations = append(eliminations, narrowed...)
+	}
+	return eliminations, true
+}
@@ -0,0 +1,134 @@
+/*
+This file implements solving by pure logic, the way a human would: instead of guessing, it repeatedly applies
+solving techniques that each prove one new fact about the grid. It is the basis for analyses that measure how
+hard a puzzle is for a person rather than for the backtracking search.
+
+The implemented techniques, tried in this order (simplest first):
+1. **Naked single**: an empty cell has only one candidate left, so that digit goes there.
+2. **Hidden single**: a digit has only one possible cell left in a row, column, or subgrid, so it goes there.
+
+Functions:
+- **`SolveLogically`**: Applies the techniques until the grid is solved or no technique makes progress.
+- **`computeCandidates`**: Computes the candidates of every empty cell from the grid.
+- **`findNakedSingle`** / **`findHiddenSingle`**: Look for one deduction of each kind.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Deduction is one step found by a logical technique.
+type Deduction struct {
+	Technique string // Name of the technique that found the step (e.g. "hidden single")
+	Pos       string // Cell where a digit is placed
+	Digit     rune   // Digit placed in the cell
+	Reason    string // Human-readable explanation of the step
+}
+
+// technique is a logical solving technique: it looks for one deduction given the grid and its candidates.
+type technique struct {
+	name string
+	find func(grid map[string]rune, candidates map[string][]rune) (Deduction, bool)
+}
+
+// techniques lists the implemented techniques from the simplest to the hardest.
+var techniques = []technique{
+	{name: "naked single", find: findNakedSingle},
+	{name: "hidden single", find: findHiddenSingle},
+}
+
+// SolveLogically applies the logical techniques until the grid is full or none of them finds anything.
+// It returns the (possibly partially) filled grid, the deductions made in order, and whether the grid was
+// completely solved. The caller's grid is not modified.
+func SolveLogically(grid map[string]rune) (map[string]rune, []Deduction, bool) {
+	shape := ShapeOf(grid)
+	work := make(map[string]rune)
+	copyGrid(grid, work)                  // Work on a copy so the caller's grid is left untouched
+	candidates := computeCandidates(work) // Candidates of every empty cell
+
+	var deductions []Deduction
+	for len(candidates) > 0 { // While there are empty cells
+		found := false
+		for _, t := range techniques { // Always try the simplest technique first
+			deduction, ok := t.find(work, candidates)
+			if !ok {
+				continue
+			}
+			work[deduction.Pos] = deduction.Digit // Apply the deduction
+			delete(candidates, deduction.Pos)
+			for _, peer := range shape.tables().peers[deduction.Pos] { // The digit is no longer possible among the peers
+				if _, empty := candidates[peer]; empty {
+					candidates[peer] = removeRune(candidates[peer], deduction.Digit)
+				}
+			}
+			deductions = append(deductions, deduction)
+			found = true
+			break
+		}
+		if !found { // No technique can make progress: logic alone is stuck
+			return work, deductions, false
+		}
+	}
+	return work, deductions, true
+}
+
+// computeCandidates returns the digits allowed by the classic rules in every empty cell of the grid.
+func computeCandidates(grid map[string]rune) map[string][]rune {
+	candidates := make(map[string][]rune)
+	for pos, val := range grid {
+		if val == '.' { // Only empty cells have candidates
+			candidates[pos] = Candidates(grid, pos).Digits()
+		}
+	}
+	return candidates
+}
+
+// findNakedSingle looks for an empty cell with a single candidate, scanning cells in row-major order.
+func findNakedSingle(grid map[string]rune, candidates map[string][]rune) (Deduction, bool) {
+	for _, pos := range ShapeOf(grid).Cells() { // Loop through all cells in row-major order
+		if options, empty := candidates[pos]; empty && len(options) == 1 {
+			return Deduction{
+				Technique: "naked single",
+				Pos:       pos,
+				Digit:     options[0],
+				Reason:    fmt.Sprintf("%c is the only candidate left in %s", options[0], pos),
+			}, true
+		}
+	}
+	return Deduction{}, false
+}
+
+// findHiddenSingle looks for a digit that has only one possible cell left in a unit.
+func findHiddenSingle(grid map[string]rune, candidates map[string][]rune) (Deduction, bool) {
+	shape := ShapeOf(grid)
+	unitNames := []string{"row", "column", "subgrid"} // Units lists the rows, then the columns, then the subgrids
+	for u, unit := range shape.tables().units {
+		for _, num := range shape.Digits() {
+			place := "" // The only cell of the unit where num fits, if there is one
+			count := 0  // Number of cells of the unit where num fits
+			placed := false
+			for _, pos := range unit {
+				if grid[pos] == num { // The digit is already placed in this unit
+					placed = true
+					break
+				}
+				for _, option := range candidates[pos] {
+					if option == num {
+						place = pos
+						count++
+						break
+					}
+				}
+			}
+			if !placed && count == 1 {
+				return Deduction{
+					Technique: "hidden single",
+					Pos:       place,
+					Digit:     num,
+					Reason:    fmt.Sprintf("%s is the only place for %c in its %s", place, num, unitNames[u/shape.Size]),
+				}, true
+			}
+		}
+	}
+	return Deduction{}, false
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"sudokux"
+)
+
+// pipe solves the classic puzzles read from the standard input, one per line as 81 cells, and prints one line for
+// each of them on the standard output: the 81 cells of its solution, or a token saying why there is none (invalid,
+// no-solution, multiple, or timeout when opts.timeout runs out). Every line is written as soon as its puzzle is
+// solved, so that a program can keep the process running and talk to it one puzzle at a time.
+func pipe(opts options) {
+	if opts.variant != sudokux.VariantClassic && opts.variant != "" || opts.clues != "" || opts.regions != "" || opts.extraRegions != "" || opts.box != "" {
+		fail(fmt.Errorf("--pipe only solves classic puzzles"))
+	}
+	in := bufio.NewScanner(os.Stdin)
+	out := bufio.NewWriter(os.Stdout)
+	for in.Scan() {
+		fmt.Fprintln(out, pipeLine(opts, strings.TrimSpace(in.Text())))
+		if err := out.Flush(); err != nil { // The reader went away
+			fail(err)
+		}
+	}
+	if err := in.Err(); err != nil {
+		fail(err)
+	}
+}
+
+// pipeLine returns the line printed by pipe for the puzzle written on line.
+func pipeLine(opts options, line string) string {
+	grid, err := sudokux.ParseLine(line)
+	if err != nil {
+		slog.Debug("invalid puzzle", "line", line, "error", err)
+		return "invalid"
+	}
+	state := newSearchState(opts, grid, nil)
+	if !runSearch(state, opts.timeout) {
+		return "timeout"
+	}
+	switch len(state.Solutions) {
+	case 0:
+		return "no-solution"
+	case 2:
+		return "multiple"
+	}
+	return sudokux.Grid(state.Solutions[0]).String()
+}
+
+// solveBatch solves the classic puzzles of the file opts.batch on a pool of opts.workers goroutines and writes one
+// CSV line per puzzle, in the order of the file, to opts.output or to the standard output: the line of the puzzle
+// in the file, the puzzle, its status (solved, no-solution, multiple, timeout when the search of the puzzle runs longer
+// than opts.timeout, or invalid with the reason, as with statusToken), and its solution. The puzzles are streamed
+// through the pool as they are read, so files of any size can be solved, and a summary is printed at the end (on the
+// standard error when the results go to the standard output).
+func solveBatch(opts options) {
+	if opts.variant != sudokux.VariantClassic && opts.variant != "" || opts.clues != "" || opts.regions != "" || opts.extraRegions != "" || opts.box != "" {
+		fail(fmt.Errorf("--batch only solves classic puzzles"))
+	}
+	in, err := os.Open(opts.batch)
+	if err != nil {
+		fail(err)
+	}
+	defer in.Close()
+	out, summary := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	if opts.output != "" {
+		file, err := os.Create(opts.output)
+		if err != nil {
+			fail(err)
+		}
+		defer file.Close()
+		out, summary = file, os.Stdout
+	}
+	var bar *progress
+	if opts.progress && (opts.output != "" || !isTerminal(os.Stdout)) { // Results printed on the terminal show the progress already
+		total, err := countPuzzles(opts.batch)
+		if err != nil {
+			fail(err)
+		}
+		bar = newProgress("Solving", total)
+	}
+
+	type row struct { // One line of the results
+		seq      int // Position of the puzzle among the puzzles of the file
+		line     int // Line of the puzzle in the file
+		puzzle   string
+		status   string
+		solution string
+	}
+	start := time.Now()
+	pool := sudokux.NewSolverPool(opts.workers, sudokux.WithTimeout(opts.timeout), sudokux.WithSeed(opts.seed))
+	if opts.remote != "" {
+		pool = sudokux.NewSolverPoolFunc(opts.workers, remoteSolveFunc(opts))
+	}
+	invalid := make(chan row) // Puzzles that don't parse, which skip the pool
+	var (
+		mu        sync.Mutex // Guards submitted
+		submitted []row      // The puzzles given to the pool, by pool index
+		readErr   error      // Error reading the file, if any
+	)
+	go func() { // Feed the pool while the results are written below
+		scanner := bufio.NewScanner(in)
+		seq := 0
+		for number := 1; scanner.Scan(); number++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			r := row{seq: seq, line: number, puzzle: line}
+			seq++
+			grid, err := sudokux.ParseLine(line)
+			if err != nil {
+				r.status = "invalid: " + err.Error()
+				invalid <- r
+				continue
+			}
+			mu.Lock()
+			submitted = append(submitted, r) // The pool numbers the puzzles in the order they are submitted
+			mu.Unlock()
+			pool.Submit(grid)
+		}
+		readErr = scanner.Err()
+		close(invalid)
+		pool.Close()
+	}()
+
+	csvOut := csv.NewWriter(out)
+	csvOut.Write([]string{"line", "puzzle", "status", "solution"})
+	pending := make(map[int]row) // Results waiting for the results of earlier puzzles
+	next := 0                    // Position of the puzzle whose result is written next
+	counts := make(map[string]int)
+	results, invalidRows := pool.Results(), invalid
+	for results != nil || invalidRows != nil {
+		var r row
+		select {
+		case result, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			mu.Lock()
+			r = submitted[result.Index]
+			mu.Unlock()
+			r.status = statusToken(result.Err)
+			switch r.status {
+			case "solved":
+				r.solution = sudokux.Grid(result.Solution).String()
+			case "invalid":
+				r.status += ": " + result.Err.Error()
+			}
+		case bad, ok := <-invalidRows:
+			if !ok {
+				invalidRows = nil
+				continue
+			}
+			r = bad
+		}
+		counts[strings.SplitN(r.status, ":", 2)[0]]++
+		slog.Debug("puzzle done", "line", r.line, "status", r.status)
+		bar.add()
+		pending[r.seq] = r
+		for { // Write the results in the order of the file, as soon as every earlier puzzle is done
+			done, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			csvOut.Write([]string{fmt.Sprint(done.line), done.puzzle, done.status, done.solution})
+		}
+	}
+	csvOut.Flush()
+	bar.finish()
+	if readErr != nil {
+		fail(readErr)
+	}
+	if err := csvOut.Error(); err != nil {
+		fail(err)
+	}
+	fmt.Fprintf(summary, "Solved %d puzzles in %v (%d without a solution, %d with several, %d timed out, %d invalid)\n", counts["solved"],
+		time.Since(start).Round(time.Millisecond), counts["no-solution"], counts["multiple"], counts["timeout"], counts["invalid"])
+}
+
+// countPuzzles returns the number of puzzles in the file at path, counting every line but blank ones and comments.
+func countPuzzles(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// progress draws a progress bar on one line of the standard error, with the rate of the work done so far and the
+// time left. A nil *progress draws nothing, so callers don't need to check whether it is shown.
+type progress struct {
+	label string    // What is being done, such as "Solving"
+	total int       // Number of items to do
+	done  int       // Number of items done so far
+	start time.Time // When the work started
+	drawn time.Time // When the bar was last drawn, or the zero time if it is hidden
+}
+
+// progressInterval is the shortest time between two drawings of a progress bar.
+const progressInterval = 100 * time.Millisecond
+
+// newProgress returns a progress bar for total items, labeled with what is being done.
+func newProgress(label string, total int) *progress {
+	return &progress{label: label, total: total, start: time.Now()}
+}
+
+// add counts one more item done, and draws the bar again unless it was drawn moments ago.
+func (p *progress) add() {
+	if p == nil {
+		return
+	}
+	p.done++
+	if time.Since(p.drawn) >= progressInterval || p.done == p.total {
+		p.draw()
+	}
+}
+
+// draw draws the bar over the current line of the standard error.
+func (p *progress) draw() {
+	const width = 30
+	filled, percent := width, 100.0
+	if p.total > 0 {
+		filled, percent = width*p.done/p.total, 100*float64(p.done)/float64(p.total)
+	}
+	elapsed := time.Since(p.start)
+	rate := float64(p.done) / elapsed.Seconds()
+	eta := "?"
+	if p.done > 0 {
+		eta = (elapsed * time.Duration(p.total-p.done) / time.Duration(p.done)).Round(time.Second).String()
+	}
+	fmt.Fprintf(os.Stderr, "\r%s [%s%s] %d/%d (%.1f%%), %.1f/s, ETA %s%s", p.label, strings.Repeat("#", filled), strings.Repeat(".", width-filled), p.done, p.total, percent, rate, eta, ansiClearLine)
+	p.drawn = time.Now()
+}
+
+// hide erases the bar, so that a line can be printed in its place; the next call to add draws it again below.
+func (p *progress) hide() {
+	if p == nil || p.drawn.IsZero() {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r"+ansiClearLine)
+	p.drawn = time.Time{}
+}
+
+// finish leaves the bar as it is and moves to the next line.
+func (p *progress) finish() {
+	if p == nil || p.drawn.IsZero() {
+		return
+	}
+	fmt.Fprintln(os.Stderr)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"sudokux"
+)
+
+// bench solves the puzzles of the file opts.input with the engines of opts.engines, or every engine but the slow
+// backtrack-first, and prints, for each puzzle, the time and number of search nodes each engine took, then the
+// minimum, median, maximum, and total of both for each engine, and finally the puzzles on which the engines disagree.
+func bench(opts options) {
+	engines := parseEngines(opts.engines)
+	if engines == nil {
+		for _, engine := range sudokux.Engines() {
+			if engine.Name() != "backtrack-first" {
+				engines = append(engines, engine)
+			}
+		}
+	}
+	grids := benchPuzzles(opts)
+	report := sudokux.Bench(grids, engines...)
+
+	if opts.perPuzzle {
+		fmt.Printf("%-8s", "puzzle")
+		for _, result := range report.Results {
+			fmt.Printf(" %28s", result.Engine)
+		}
+		fmt.Println()
+		for p := range grids {
+			fmt.Printf("%-8d", p+1)
+			for _, result := range report.Results {
+				fmt.Printf(" %14v %7d nodes", result.Times[p].Round(time.Microsecond), result.Nodes[p])
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d puzzles from %s\n", len(grids), opts.input)
+	for _, result := range report.Results {
+		stats := result.Stats()
+		fmt.Printf("%s:\n", result.Engine)
+		fmt.Printf("  time:  min %v, median %v, max %v, total %v\n", stats.MinTime.Round(time.Microsecond), stats.MedianTime.Round(time.Microsecond), stats.MaxTime.Round(time.Microsecond), stats.TotalTime.Round(time.Microsecond))
+		fmt.Printf("  nodes: min %d, median %d, max %d, total %d\n", stats.MinNodes, stats.MedianNodes, stats.MaxNodes, stats.TotalNodes)
+	}
+	for _, d := range report.Disagreements {
+		fmt.Printf("Disagreement on puzzle %d: %s %s\n", d.Puzzle+1, d.Engine, d.Detail)
+	}
+}
+
+// compare solves the puzzles of the file opts.input with the engines of opts.engines and prints how many puzzles
+// each engine found unique, unsolvable, or with several solutions, its total time and nodes relative to the
+// fastest engine, and every puzzle on which an engine disagrees with the first one about the number of solutions
+// or the solution itself. It exits with a non-zero status when there is a disagreement.
+func compare(opts options) {
+	grids := benchPuzzles(opts)
+	report := sudokux.Bench(grids, parseEngines(opts.engines)...)
+
+	fastest := report.Results[0].Total
+	for _, result := range report.Results {
+		fastest = min(fastest, result.Total)
+	}
+	fmt.Printf("%d puzzles from %s\n", len(grids), opts.input)
+	fmt.Printf("%-16s %8s %8s %8s %12s %12s %10s\n", "engine", "unique", "none", "several", "time", "nodes", "relative")
+	for _, result := range report.Results {
+		verdicts := make([]int, 3) // Puzzles with no solution, one, and several
+		for _, count := range result.Counts {
+			verdicts[min(count, 2)]++
+		}
+		relative := float64(result.Total) / float64(max(fastest, 1))
+		fmt.Printf("%-16s %8d %8d %8d %12v %12d %9.1fx\n", result.Engine, verdicts[1], verdicts[0], verdicts[2], result.Total.Round(time.Microsecond), result.TotalNodes, relative)
+	}
+	if len(report.Disagreements) == 0 {
+		fmt.Println("The engines agree on every puzzle.")
+		return
+	}
+	for _, d := range report.Disagreements {
+		fmt.Printf("Disagreement on puzzle %d: %s %s\n", d.Puzzle+1, d.Engine, d.Detail)
+	}
+	os.Exit(exitError)
+}
+
+// parseEngines returns the engines of names, separated by commas, for bench and compare, or nil when names is
+// empty. It exits on an unknown engine.
+func parseEngines(names string) []sudokux.Engine {
+	var engines []sudokux.Engine
+	if names != "" {
+		for _, name := range strings.Split(names, ",") {
+			engine, err := sudokux.EngineByName(strings.TrimSpace(name))
+			if err != nil {
+				fail(err)
+			}
+			engines = append(engines, engine)
+		}
+	}
+	return engines
+}
+
+// benchPuzzles returns the puzzles of the file opts.input for bench and compare, or exits if they can't be read.
+func benchPuzzles(opts options) []sudokux.Grid {
+	if opts.input == "" {
+		fmt.Fprintln(os.Stderr, "Error: give the file of puzzles with --file")
+		os.Exit(exitUsage)
+	}
+	file, err := os.Open(opts.input)
+	if err != nil {
+		fail(err)
+	}
+	grids, err := sudokux.ReadPuzzles(file)
+	file.Close()
+	if err != nil {
+		fail(fmt.Errorf("%s: %v", opts.input, err))
+	}
+	if len(grids) == 0 {
+		fail(fmt.Errorf("%s: no puzzles", opts.input))
+	}
+	return grids
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configPath returns the path of the configuration file: $SUDOKU_CONFIG if it is set, or else sudoku/config.toml
+// in the user's configuration directory (~/.config on Linux). It returns "" when there is no such directory.
+func configPath() string {
+	if path, ok := os.LookupEnv("SUDOKU_CONFIG"); ok {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sudoku", "config.toml")
+}
+
+// loadConfig sets the flags of the command named command to the values of the configuration file, which become
+// their defaults: the flags given on the command line are parsed afterwards and win. The file holds the simple
+// part of TOML: "key = value" lines where every key is the name of a flag, such as
+//
+//	format = "line"
+//	difficulty = "hard"
+//
+//	[bench]
+//	per-puzzle = false
+//
+// Keys before the first [section] apply to every command that has such a flag, and keys in the section named after
+// a command only apply to it. A missing file is not an error.
+func loadConfig(flags *flag.FlagSet, command string) error {
+	path := configPath()
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	section := "" // Section of the current line, "" before the first one
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name, ok := strings.CutSuffix(strings.TrimSpace(stripComment(line)), "]")
+			section = strings.TrimSpace(name[1:])
+			if !ok || findCommand(section) == nil {
+				return fmt.Errorf("%s:%d: expected the name of a command such as [solve], got %s", path, number, line)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%s:%d: expected key = value, got %s", path, number, line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) { // A string, maybe followed by a comment
+			quoted, err := strconv.QuotedPrefix(value)
+			if err == nil && strings.TrimSpace(stripComment(value[len(quoted):])) != "" {
+				err = fmt.Errorf("unexpected text after the string")
+			}
+			if err == nil {
+				value, err = strconv.Unquote(quoted)
+			}
+			if err != nil {
+				return fmt.Errorf("%s:%d: invalid string %s: %v", path, number, value, err)
+			}
+		} else {
+			value = strings.TrimSpace(stripComment(value))
+		}
+
+		if section != "" && section != command { // Settings of another command
+			continue
+		}
+		if flags.Lookup(key) == nil {
+			if section == "" { // Another command may have this flag
+				continue
+			}
+			return fmt.Errorf("%s:%d: the %s command has no flag %q", path, number, section, key)
+		}
+		if err := flags.Set(key, value); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, number, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// stripComment returns text without the comment that ends it, if any.
+func stripComment(text string) string {
+	before, _, _ := strings.Cut(text, "#")
+	return before
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"sudokux"
+)
+
+// readFPuzzles reads the puzzle of --fpuzzles: a file with its JSON or its link (- for the standard input), or a
+// link given as the value of the flag itself.
+func readFPuzzles(opts options) (*sudokux.FPuzzle, error) {
+	var data []byte
+	var err error
+	switch {
+	case opts.fpuzzles == "-":
+		data, err = io.ReadAll(os.Stdin)
+	case strings.Contains(opts.fpuzzles, "://"):
+		data = []byte(opts.fpuzzles)
+	default:
+		data, err = os.ReadFile(opts.fpuzzles)
+	}
+	if err != nil {
+		return nil, err
+	}
+	puzzle, err := sudokux.ParseFPuzzles(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range puzzle.Unsupported {
+		fmt.Fprintf(os.Stderr, "Warning: the %s constraint isn't supported and is left out of the rules\n", name)
+	}
+	return puzzle, nil
+}
+
+// parseFPuzzlesGrid returns the grid, the shape, and the rules of the puzzle of --fpuzzles, as parseGrid does for
+// the rows. The puzzle carries its own rules, so the flags giving them are refused.
+func parseFPuzzlesGrid(opts options, args []string) (map[string]rune, sudokux.Shape, []sudokux.Constraint, *sudokux.KillerConstraint, error) {
+	if len(args) > 0 || opts.input != "" || opts.box != "" || opts.clues != "" || opts.regions != "" || opts.extraRegions != "" ||
+		(opts.variant != sudokux.VariantClassic && opts.variant != "") {
+		return nil, sudokux.Shape{}, nil, nil, fmt.Errorf("--fpuzzles gives the whole puzzle, without rows, --input, --box, --variant, --clues, --regions, or --extra-regions")
+	}
+	puzzle, err := readFPuzzles(opts)
+	if err != nil {
+		return nil, sudokux.Shape{}, nil, nil, err
+	}
+	constraints, err := puzzle.Constraints()
+	if err != nil {
+		return nil, sudokux.Shape{}, nil, nil, err
+	}
+	var killer *sudokux.KillerConstraint
+	for _, constraint := range constraints {
+		if cages, ok := constraint.(*sudokux.KillerConstraint); ok { // Kept to draw the cages
+			killer = cages
+		}
+	}
+	var extra []sudokux.Constraint // Constraints the parser checks instead of the classic rules, as in parseGrid
+	if len(constraints) > len(sudokux.ClassicConstraintsFor(puzzle.Shape)) || puzzle.Regions != nil {
+		extra = constraints
+	}
+	var rows []string
+	for row := 0; row < puzzle.Shape.Size; row++ {
+		var sb strings.Builder
+		for col := 0; col < puzzle.Shape.Size; col++ {
+			sb.WriteRune(puzzle.Grid[puzzle.Shape.Pos(row, col)])
+		}
+		rows = append(rows, sb.String())
+	}
+	grid, err := sudokux.ParseRowsWithConstraints(rows, puzzle.Shape, extra)
+	if err == nil {
+		slog.Debug("f-puzzles", "title", puzzle.Title, "variant", puzzle.Variant, "givens", sudokux.CountGivens(grid), "constraints", len(constraints), "unsupported", puzzle.Unsupported)
+	}
+	return grid, puzzle.Shape, constraints, killer, err
+}
+
+// export writes the puzzle given by rows, with the rules of opts, in the format of f-puzzles, along with its
+// solution when it has a unique one, for SudokuPad to check the answers of players. With --link, it prints the
+// link opening the puzzle in SudokuPad instead.
+func export(opts options, rows []string) {
+	grid, shape, constraints, _, err := parseGrid(opts, rows)
+	if err != nil {
+		fail(err)
+	}
+	puzzle, err := fpuzzleOf(opts, grid, shape)
+	if err != nil {
+		fail(err)
+	}
+	if opts.title != "" {
+		puzzle.Title = opts.title
+	}
+	state := newSearchState(opts, grid, constraints)
+	if !runSearch(state, opts.timeout) {
+		fmt.Fprintln(os.Stderr, "Warning: the search ran out of time, so the solution is left out")
+	} else if len(state.Solutions) == 1 {
+		puzzle.Solution = state.Solutions[0]
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: the puzzle has %d solutions, so no solution is given\n", len(state.Solutions))
+	}
+	data, err := sudokux.MarshalFPuzzles(puzzle)
+	if err != nil {
+		fail(err)
+	}
+	if opts.link {
+		data = []byte(sudokux.FPuzzlesLink(data))
+	}
+	w := os.Stdout
+	if opts.output != "" {
+		file, err := os.Create(opts.output)
+		if err != nil {
+			fail(err)
+		}
+		defer file.Close()
+		w = file
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		fail(err)
+	}
+}
+
+// fpuzzleOf returns the puzzle of grid with the rules of opts, as f-puzzles describes them: read again from
+// --fpuzzles, or gathered from --variant, --box, --regions, --extra-regions, and --clues.
+func fpuzzleOf(opts options, grid map[string]rune, shape sudokux.Shape) (*sudokux.FPuzzle, error) {
+	if opts.fpuzzles != "" {
+		return readFPuzzles(opts)
+	}
+	puzzle := &sudokux.FPuzzle{Shape: shape, Grid: grid, Variant: opts.variant}
+	switch {
+	case opts.regions != "":
+		regions, err := sudokux.ParseRegions(opts.regions, shape)
+		if err != nil {
+			return nil, err
+		}
+		puzzle.Regions = regions
+	case shape != sudokux.ShapeOf(grid): // f-puzzles only knows the default boxes, so others are written as regions
+		units := shape.Units()
+		puzzle.Regions = units[2*shape.Size:] // The rows, the columns, then the boxes
+	}
+	if opts.extraRegions != "" {
+		file, err := os.Open(opts.extraRegions)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		regions, err := sudokux.ParseExtraRegions(file, shape)
+		if err != nil {
+			return nil, err
+		}
+		puzzle.ExtraRegions = regions.Regions()
+	}
+	if opts.clues != "" {
+		file, err := os.Open(opts.clues)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		clues, err := sudokux.ParseClues(file, shape)
+		if err != nil {
+			return nil, err
+		}
+		puzzle.Clues = *clues
+	}
+	return puzzle, nil
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sudokux"
+	"sudokux/book"
+)
+
+// generate generates a puzzle of the difficulty given by opts and prints it, followed by its rows as arguments for
+// solving it and by its solution. The puzzle is dug out of the solved grid given by rows, or of a random one when
+// there are no rows.
+func generate(opts options, rows []string) {
+	genOpts := sudokux.GenerateOptions{Seed: opts.seed, Variant: opts.variant, Givens: opts.givens, TimeLimit: opts.timeLimit, DigTime: opts.digTime, NoGuessing: opts.noGuessing}
+	if len(rows) > 0 {
+		solution, err := sudokux.ParseRows(rows)
+		if err != nil {
+			fail(err)
+		}
+		genOpts.Solution = solution
+		opts.size = sudokux.ShapeOf(solution).Size // The rows decide the board
+	}
+	shape, err := sudokux.ShapeForSize(opts.size)
+	if err != nil {
+		fail(err)
+	}
+	genOpts.Shape = shape
+	if opts.difficulty != "any" {
+		difficulty, err := sudokux.ParseDifficulty(opts.difficulty)
+		if err != nil {
+			fail(err)
+		}
+		genOpts.Difficulty = difficulty
+	}
+	symmetry, err := sudokux.ParseSymmetry(opts.symmetry)
+	if err != nil {
+		fail(err)
+	}
+	genOpts.Symmetry = symmetry
+	if opts.count > 1 {
+		generateMany(opts, genOpts)
+		return
+	}
+	puzzle, solution, err := sudokux.Generate(genOpts)
+	if err != nil {
+		fail(err)
+	}
+
+	if opts.variant != sudokux.VariantClassic && opts.variant != "" {
+		fmt.Printf("Generated a puzzle of the %s variant rated %s with %d givens:\n", opts.variant, sudokux.Rate(puzzle), sudokux.CountGivens(puzzle))
+	} else {
+		fmt.Printf("Generated a puzzle rated %s with %d givens:\n", sudokux.Rate(puzzle), sudokux.CountGivens(puzzle))
+	}
+	printSudoku(os.Stdout, puzzle, shape)
+	fmt.Println("Rows:", quoteRows(puzzle, shape))
+	fmt.Println("Solution:")
+	printSudoku(os.Stdout, solution, shape)
+}
+
+// generateMany generates opts.count puzzles on every core and prints each one on a line as soon as it is found:
+// its grade, its number of givens, the seed that generates it again, and its rows as arguments.
+func generateMany(opts options, genOpts sudokux.GenerateOptions) {
+	puzzles, err := sudokux.GenerateMany(genOpts, opts.count, opts.workers)
+	if err != nil {
+		fail(err)
+	}
+	var bar *progress
+	if opts.progress {
+		bar = newProgress("Generating", opts.count)
+	}
+	for generated := range puzzles {
+		bar.hide()
+		if generated.Err != nil {
+			fail(generated.Err)
+		}
+		fmt.Printf("%d. %s puzzle with %d givens (seed %d): %s\n", generated.Index+1, sudokux.Rate(generated.Puzzle), sudokux.CountGivens(generated.Puzzle), generated.Seed, quoteRows(generated.Puzzle, genOpts.Shape))
+		bar.add()
+	}
+	bar.finish()
+}
+
+// daily prints the puzzle of the day for the date and difficulty given by opts, followed by its rows as arguments
+// for solving it. The solution is left out, so as not to spoil the challenge.
+func daily(opts options) {
+	date := time.Now()
+	if opts.date != "" {
+		var err error
+		if date, err = time.Parse(time.DateOnly, opts.date); err != nil {
+			fail(fmt.Errorf("invalid --date %q, expected YYYY-MM-DD such as 2024-06-01", opts.date))
+		}
+	}
+	difficulty, err := sudokux.ParseDifficulty(opts.difficulty)
+	if err != nil {
+		fail(err)
+	}
+	puzzle, _, err := sudokux.Daily(date, difficulty)
+	if err != nil {
+		fail(err)
+	}
+
+	fmt.Printf("Daily %s puzzle for %s, with %d givens:\n", difficulty, date.Format(time.DateOnly), sudokux.CountGivens(puzzle))
+	printSudoku(os.Stdout, puzzle, sudokux.Classic)
+	fmt.Println("Rows:", quoteRows(puzzle, sudokux.Classic))
+}
+
+// printBook writes a PDF booklet of puzzles to opts.output, with their solutions at the back. The puzzles are read from
+// opts.input and must have unique solutions, or else opts.count puzzles of the difficulty of opts are generated on
+// every core.
+func printBook(opts options) {
+	puzzles, err := bookPuzzles(opts)
+	if err != nil {
+		fail(err)
+	}
+	file, err := os.Create(opts.output)
+	if err != nil {
+		fail(err)
+	}
+	if err := book.Write(file, opts.title, puzzles); err != nil {
+		file.Close()
+		fail(err)
+	}
+	if err := file.Close(); err != nil {
+		fail(err)
+	}
+	fmt.Printf("Wrote %d puzzles to %s\n", len(puzzles), opts.output)
+}
+
+// bookPuzzles returns the puzzles of a book, read from opts.input or generated, along with their solutions and
+// grades.
+func bookPuzzles(opts options) ([]book.Puzzle, error) {
+	if opts.input != "" {
+		file, err := os.Open(opts.input)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		grids, err := sudokux.ReadPuzzles(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", opts.input, err)
+		}
+		var puzzles []book.Puzzle
+		for i, grid := range grids {
+			state := sudokux.NewSearchState(grid, 2)
+			state.Run(0)
+			if len(state.Solutions) != 1 {
+				return nil, fmt.Errorf("%s: puzzle %d doesn't have a unique solution", opts.input, i+1)
+			}
+			puzzles = append(puzzles, book.Puzzle{Puzzle: grid, Solution: state.Solutions[0], Difficulty: sudokux.Rate(grid)})
+		}
+		return puzzles, nil
+	}
+
+	genOpts := sudokux.GenerateOptions{Seed: opts.seed, NoGuessing: opts.noGuessing}
+	if opts.difficulty != "any" {
+		difficulty, err := sudokux.ParseDifficulty(opts.difficulty)
+		if err != nil {
+			return nil, err
+		}
+		genOpts.Difficulty = difficulty
+	}
+	symmetry, err := sudokux.ParseSymmetry(opts.symmetry)
+	if err != nil {
+		return nil, err
+	}
+	genOpts.Symmetry = symmetry
+	generated, err := sudokux.GenerateMany(genOpts, opts.count, opts.workers)
+	if err != nil {
+		return nil, err
+	}
+	var bar *progress
+	if opts.progress {
+		bar = newProgress("Generating", opts.count)
+	}
+	defer bar.finish()
+	puzzles := make([]book.Puzzle, opts.count)
+	for g := range generated {
+		if g.Err != nil {
+			return nil, g.Err
+		}
+		puzzles[g.Index] = book.Puzzle{Puzzle: g.Puzzle, Solution: g.Solution, Difficulty: sudokux.Rate(g.Puzzle)}
+		bar.add()
+	}
+	return puzzles, nil
+}
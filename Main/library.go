@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"sudokux"
+	"sudokux/library"
+)
+
+// libraryPath returns the default path of the library: $SUDOKU_LIBRARY if it is set, or else sudoku/library.db in
+// the user's configuration directory, next to the configuration file.
+func libraryPath() string {
+	if path, ok := os.LookupEnv("SUDOKU_LIBRARY"); ok {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "library.db"
+	}
+	return filepath.Join(dir, "sudoku", "library.db")
+}
+
+// libraryFlags registers the flag naming the library file.
+func libraryFlags(flags *flag.FlagSet, opts *options) {
+	flags.StringVar(&opts.library, "library", libraryPath(), "SQLite file of the library of puzzles, created if missing")
+}
+
+// openLibrary opens the library of opts, creating its directory if needed.
+func openLibrary(opts options) *library.Library {
+	if dir := filepath.Dir(opts.library); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			fail(err)
+		}
+	}
+	lib, err := library.Open(opts.library)
+	if err != nil {
+		fail(err)
+	}
+	return lib
+}
+
+// save adds the puzzle given by rows to the library and prints its ID.
+func save(opts options, rows []string) {
+	grid, shape, _, _, err := parseGrid(opts, rows)
+	if err == nil && shape != sudokux.ShapeOf(grid) {
+		err = fmt.Errorf("puzzles can only be saved with the default boxes")
+	}
+	if err != nil {
+		fail(err)
+	}
+	lib, _ := savePuzzle(opts, grid)
+	lib.Close()
+}
+
+// savePuzzle adds grid to the library of opts, with its solution and grade, unless the library has it already, and
+// returns the open library and the ID of the puzzle. Puzzles without a unique solution are refused, as they can't
+// be played to the end.
+func savePuzzle(opts options, grid map[string]rune) (*library.Library, int64) {
+	result, err := sudokux.Solve(grid)
+	if err != nil {
+		fail(err)
+	}
+	lib := openLibrary(opts)
+	if id, found, err := lib.Find(grid); err != nil {
+		fail(err)
+	} else if found {
+		fmt.Printf("Puzzle %d of the library already has these givens.\n", id)
+		return lib, id
+	}
+	rating := sudokux.RatePuzzle(grid)
+	id, err := lib.Add(library.Entry{Name: opts.name, Puzzle: grid, Solution: result.Solution, Difficulty: rating.Difficulty, Score: rating.Score})
+	if err != nil {
+		fail(err)
+	}
+	fmt.Printf("Saved as puzzle %d (%s, %.1f).\n", id, rating.Difficulty, rating.Score)
+	return lib, id
+}
+
+// list prints the puzzles of the library as a table, the most recently played first.
+func list(opts options) {
+	lib := openLibrary(opts)
+	defer lib.Close()
+	entries, err := lib.List()
+	if err != nil {
+		fail(err)
+	}
+	if len(entries) == 0 {
+		fmt.Println(`The library is empty; add puzzles with "sudoku save" or "sudoku play --save".`)
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tDIFFICULTY\tSCORE\tFILLED\tADDED\tLAST PLAYED\tSOLVED")
+	for _, e := range entries {
+		solved := "-"
+		if !e.Solved.IsZero() {
+			solved = e.Solved.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%.1f\t%d/%d\t%s\t%s\t%s\n", e.ID, e.Name, e.Difficulty, e.Score, e.Filled(), len(e.Progress),
+			e.Created.Format("2006-01-02 15:04"), e.Updated.Format("2006-01-02 15:04"), solved)
+	}
+	w.Flush()
+}
+
+// resume lets the user carry on playing the puzzle of the library whose ID is args[0], from the grid as it was left,
+// and saves the progress after every move. Undo only goes back to the start of the session.
+func resume(opts options, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Error: expected the ID of a puzzle of the library (see list)")
+		os.Exit(exitUsage)
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid puzzle ID %q\n", args[0])
+		os.Exit(exitUsage)
+	}
+	lib := openLibrary(opts)
+	defer lib.Close()
+	entry, err := lib.Get(id)
+	if err != nil {
+		fail(err)
+	}
+	state := sudokux.NewMoveState(entry.Puzzle) // Only the givens of the puzzle are clues
+	for _, pos := range state.Shape.Cells() {
+		if digit := entry.Progress[pos]; digit != '.' && !state.Givens[pos] {
+			if _, err := sudokux.ApplyMove(state, pos, digit); err != nil {
+				fail(fmt.Errorf("puzzle %d is damaged: %w", id, err))
+			}
+		}
+	}
+	if !entry.Solved.IsZero() {
+		fmt.Printf("Solved on %s.\n", entry.Solved.Format("2006-01-02 15:04"))
+	}
+	playGrid(state, state.Shape, func(grid map[string]rune) error { return lib.SaveProgress(id, grid) })
+}
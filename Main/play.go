@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"sudokux"
+)
+
+// play lets the user solve the puzzle given by rows in the terminal (see playGrid). With --save, the puzzle is
+// added to the library first and the progress is saved after every move, for resume to carry on later.
+func play(opts options, rows []string) {
+	grid, shape, _, _, err := parseGrid(opts, rows)
+	if err == nil && shape != sudokux.ShapeOf(grid) {
+		err = fmt.Errorf("puzzles can only be played with the default boxes")
+	}
+	if err != nil {
+		fail(err)
+	}
+	var saved func(map[string]rune) error
+	if opts.save {
+		lib, id := savePuzzle(opts, grid)
+		defer lib.Close()
+		saved = func(progress map[string]rune) error { return lib.SaveProgress(id, progress) }
+	}
+	playGrid(sudokux.NewMoveState(grid), shape, saved)
+}
+
+// playGrid lets the user play state in the terminal, one move per line: a cell and a digit (such as "B3 7") to place
+// it, a cell and a dot to clear it, or "undo" and "redo". Illegal moves are refused, and the user is warned as soon
+// as the grid can no longer be completed. Moves are checked against the classic rules only. saved, if not nil, is
+// called with the grid after every move.
+func playGrid(state *sudokux.MoveState, shape sudokux.Shape, saved func(map[string]rune) error) {
+	journal := sudokux.NewJournal(state.Grid) // History of the moves, for undo and redo
+	printSudoku(os.Stdout, state.Grid, shape)
+	fmt.Println(`Enter a cell and a digit to place it (e.g. "B3 7"), a cell and a dot to clear it, "undo", "redo", or "quit".`)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for fmt.Print("> "); scanner.Scan(); fmt.Print("> ") {
+		fields := strings.Fields(strings.ToUpper(scanner.Text()))
+		var move sudokux.Move
+		switch {
+		case len(fields) == 1 && fields[0] == "QUIT":
+			return
+		case len(fields) == 1 && (fields[0] == "UNDO" || fields[0] == "REDO"):
+			var ok bool
+			if fields[0] == "UNDO" {
+				move, ok = journal.Undo()
+			} else {
+				move, ok = journal.Redo()
+			}
+			if !ok {
+				fmt.Printf("Nothing to %s.\n", strings.ToLower(fields[0]))
+				continue
+			}
+		case len(fields) == 2 && len([]rune(fields[1])) == 1:
+			move = sudokux.Move{Pos: fields[0], Digit: []rune(fields[1])[0]}
+		default:
+			fmt.Println(`Expected a cell and a digit, such as "B3 7", "undo", "redo", or "quit".`)
+			continue
+		}
+		solvable, err := sudokux.ApplyMove(state, move.Pos, move.Digit) // Undoing and redoing return to legal grids
+		if err != nil {
+			fmt.Println("Illegal move:", err)
+			continue
+		}
+		if len(fields) == 2 {
+			journal.Apply(move) // Already checked by ApplyMove
+		}
+		if saved != nil {
+			if err := saved(state.Grid); err != nil {
+				fmt.Fprintln(os.Stderr, "Warning: the progress wasn't saved:", err)
+			}
+		}
+		printSudoku(os.Stdout, state.Grid, shape)
+		if sudokux.CountGivens(state.Grid) == len(state.Grid) { // Every move was legal, so a full grid is solved
+			fmt.Println("Solved, well done!")
+			return
+		}
+		if !solvable {
+			fmt.Println("Warning: the grid can no longer be completed; clear some cells to go on.")
+		}
+	}
+}
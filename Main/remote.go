@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sudokux"
+	"sudokux/server"
+)
+
+// remoteClient sends puzzles to the POST /solve endpoint of a server started with the serve command, for --remote.
+type remoteClient struct {
+	url     string        // URL of the endpoint
+	engine  string        // Engine the server solves with, or "" for its default
+	timeout time.Duration // Time limit asked of the server, which may allow less, or 0 for its own limit
+	http    *http.Client
+}
+
+// remoteAttempts is how many times a request is sent while the server answers that it is busy (429 or 503).
+const remoteAttempts = 5
+
+// newRemoteClient returns the client of the server at opts.remote, such as "https://solver.example.com".
+func newRemoteClient(opts options) *remoteClient {
+	client := &http.Client{}
+	if opts.timeout > 0 { // Leave the server time to answer once its search gives up
+		client.Timeout = opts.timeout + 30*time.Second
+	}
+	return &remoteClient{url: strings.TrimSuffix(opts.remote, "/") + "/solve", engine: opts.engine, timeout: opts.timeout, http: client}
+}
+
+// solve sends grid, a board of the given shape, to the server, and returns the document it answers with. The
+// error is about the request: a puzzle without a unique solution is told by the status of the document, and
+// explained by message. While the server is busy, the request is sent again after the delay it asks for.
+func (c *remoteClient) solve(grid map[string]rune, shape sudokux.Shape) (doc sudokux.Document, message string, err error) {
+	body, err := json.Marshal(server.SolveRequest{
+		Puzzle:    sudokux.NewDocument(shape, grid, nil).Puzzle,
+		Engine:    c.engine,
+		TimeoutMs: c.timeout.Milliseconds(),
+	})
+	if err != nil {
+		return doc, "", err
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := c.http.Post(c.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return doc, "", err
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, sudokux.MaxInputBytes))
+		resp.Body.Close()
+		if err != nil {
+			return doc, "", err
+		}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			var answer server.SolveResponse
+			if err := json.Unmarshal(data, &answer); err != nil {
+				return doc, "", fmt.Errorf("unexpected answer of %s: %v", c.url, err)
+			}
+			if answer.Status == nil {
+				return doc, "", fmt.Errorf("unexpected answer of %s: no status", c.url)
+			}
+			return answer.Document, answer.Error, nil
+		case (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) && attempt < remoteAttempts:
+			wait, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+			if err != nil || wait < 1 {
+				wait = attempt // Back off when the server doesn't say how long to wait
+			}
+			time.Sleep(time.Duration(wait) * time.Second)
+			continue
+		}
+		var failure server.ErrorResponse
+		if json.Unmarshal(data, &failure) != nil || failure.Error == "" {
+			return doc, "", fmt.Errorf("%s answered %s", c.url, resp.Status)
+		}
+		return doc, "", fmt.Errorf("%s answered %s: %s", c.url, resp.Status, failure.Error)
+	}
+}
+
+// remoteError is an outcome of the server other than a unique solution: it reads as the message of the server, and
+// matches the sentinel error of its status (see sudokux.Status.Err), so that the exit status tells it apart.
+type remoteError struct {
+	status  error  // Sentinel error of the status of the document
+	message string // Error of the answer of the server
+}
+
+// Error returns the message of the server.
+func (e remoteError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the sentinel error of the status, for errors.Is.
+func (e remoteError) Unwrap() error {
+	return e.status
+}
+
+// outcome returns nil if doc holds a unique solution, or else the error of its status, explained by message.
+func outcome(doc sudokux.Document, message string) error {
+	err := doc.Status.Err()
+	if err == nil || message == "" {
+		return err
+	}
+	return remoteError{status: err, message: message}
+}
+
+// solveRemote solves grid on the server of opts.remote, and writes the solution as solveOnce does. parse is the
+// time parsing took, for --timing; the solving time includes the round trip to the server.
+func solveRemote(opts options, grid map[string]rune, shape sudokux.Shape, parse time.Duration) error {
+	start := time.Now()
+	doc, message, err := newRemoteClient(opts).solve(grid, shape)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+	_, solution, err := doc.Grids()
+	if err != nil {
+		return fmt.Errorf("unexpected answer of %s: %v", opts.remote, err)
+	}
+	nodes := 0
+	if doc.Stats != nil {
+		nodes = doc.Stats.Nodes
+	}
+	err = outcome(doc, message)
+	if opts.format == "tsv" {
+		if writeErr := writeTSV(opts, statusToken(err), solution, sudokux.CountGivens(grid), nodes, elapsed); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	var t *timing
+	if opts.timing {
+		t = &timing{Parse: parse, Solve: elapsed, Nodes: nodes}
+	}
+	return writeSolution(opts, grid, solution, shape, nil, t)
+}
+
+// remoteSolveFunc returns the function solving the puzzles of --batch on the server of opts.remote. The program
+// stops if the server can't be reached, rather than reporting every puzzle as not unique.
+func remoteSolveFunc(opts options) sudokux.SolveFunc {
+	client := newRemoteClient(opts)
+	return func(grid map[string]rune) (map[string]rune, error) {
+		doc, _, err := client.solve(grid, sudokux.ShapeOf(grid))
+		if err != nil {
+			fail(err)
+		}
+		if err := outcome(doc, ""); err != nil {
+			return nil, err
+		}
+		_, solution, err := doc.Grids()
+		if err != nil {
+			fail(fmt.Errorf("unexpected answer of %s: %v", opts.remote, err))
+		}
+		return solution, nil
+	}
+}
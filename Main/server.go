@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"sudokux/bot"
+	"sudokux/cache"
+	"sudokux/queue"
+	"sudokux/rpc"
+	"sudokux/server"
+)
+
+// serveGRPC serves the Sudoku service of the rpc package on the address of opts, until the program is interrupted
+// or terminated, when it stops taking calls and waits for the ones in progress to finish.
+func serveGRPC(opts options) {
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		fail(err)
+	}
+	server := grpc.NewServer()
+	rpc.RegisterSudokuServer(server, &rpc.Server{MaxTimeout: opts.timeout})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		server.GracefulStop()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving gRPC on %s\n", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		fail(err)
+	}
+}
+
+// serveHTTP serves the endpoints of the server package on the address of opts, until the program is interrupted or
+// terminated. It then stops taking requests, fails its readiness probe, and waits up to opts.shutdownTimeout for
+// the solves in progress, WebSockets included, before exiting.
+func serveHTTP(opts options) {
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		fail(err)
+	}
+	solutions, err := cache.Open(opts.cache, opts.cacheSize)
+	if err != nil {
+		fail(err)
+	}
+	if closer, ok := solutions.(io.Closer); ok {
+		defer closer.Close()
+	}
+	solver := &server.Server{
+		MaxTimeout:    opts.timeout,
+		Cache:         solutions,
+		MaxConcurrent: opts.maxConcurrent,
+		RatePerSecond: opts.ratePerSecond,
+		Burst:         opts.burst,
+		MaxBodyBytes:  opts.maxBodyBytes,
+		MaxNodes:      opts.maxNodes,
+	}
+	httpServer := &http.Server{
+		Handler:           solver.Handler(),
+		ReadHeaderTimeout: opts.readTimeout,
+		ReadTimeout:       opts.readTimeout,
+		WriteTimeout:      opts.writeTimeout,
+		IdleTimeout:       opts.idleTimeout,
+	}
+	stopped := make(chan error, 1)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		fmt.Fprintln(os.Stderr, "Shutting down, waiting for the solves in progress")
+		ctx, cancel := context.WithTimeout(context.Background(), opts.shutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(ctx) // Waits for the requests in progress, but not for the hijacked WebSockets
+		if err == nil {
+			err = solver.Shutdown(ctx)
+		}
+		stopped <- err
+	}()
+	fmt.Fprintf(os.Stderr, "Serving HTTP on %s\n", listener.Addr())
+	if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		fail(err)
+	}
+	if err := <-stopped; err != nil {
+		fail(fmt.Errorf("solves still in progress after %v: %w", opts.shutdownTimeout, err)) // Exiting without closing the cache is safe
+	}
+}
+
+// serveBot serves the webhooks of the bot package on the address of opts, for the platforms whose credentials are
+// set in the environment, until the program is interrupted or terminated.
+func serveBot(opts options) {
+	answerer := &bot.Bot{
+		MaxTimeout:         opts.timeout,
+		SlackSigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+		SlackToken:         os.Getenv("SLACK_BOT_TOKEN"),
+	}
+	if key := os.Getenv("DISCORD_PUBLIC_KEY"); key != "" {
+		decoded, err := hex.DecodeString(key)
+		if err != nil {
+			fail(fmt.Errorf("DISCORD_PUBLIC_KEY: %v", err))
+		}
+		answerer.DiscordPublicKey = decoded
+	}
+	if answerer.SlackSigningSecret != "" && answerer.SlackToken == "" {
+		fail(errors.New("SLACK_BOT_TOKEN is needed to answer on Slack"))
+	}
+	if answerer.SlackSigningSecret == "" && answerer.DiscordPublicKey == nil {
+		fail(errors.New("set SLACK_SIGNING_SECRET and SLACK_BOT_TOKEN for Slack, or DISCORD_PUBLIC_KEY for Discord"))
+	}
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		fail(err)
+	}
+	httpServer := &http.Server{Handler: answerer.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(ctx) // Slack answers still being searched are dropped, as Slack only waited for the acknowledgment
+	}()
+	fmt.Fprintf(os.Stderr, "Serving the bot on %s\n", listener.Addr())
+	if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		fail(err)
+	}
+}
+
+// work handles the jobs of the broker of opts (see the queue package) until the program is interrupted or
+// terminated, when it stops taking jobs and finishes the ones in progress.
+func work(opts options) {
+	workers := opts.workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	prefetch := opts.prefetch
+	if prefetch <= 0 { // Enough to keep every goroutine busy while the next jobs arrive
+		prefetch = 2 * workers
+	}
+	broker, err := queue.Open(opts.broker, opts.jobs, opts.results, prefetch)
+	if err != nil {
+		fail(err)
+	}
+	defer broker.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	worker := &queue.Worker{Broker: broker, Concurrency: workers, Retries: opts.retries, MaxTimeout: opts.timeout}
+	fmt.Fprintf(os.Stderr, "Taking the jobs of %s from %s\n", opts.jobs, opts.broker)
+	if err := worker.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		fail(err)
+	}
+}
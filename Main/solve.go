@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"sudokux"
+)
+
+// solve solves the puzzle given by rows under the rules of opts and writes the solution in the format of opts to
+// opts.output, or prints it. It explains why when the puzzle has no solution, and reports when it has more than
+// one or when the search runs out of time.
+func solve(opts options, rows []string) {
+	if opts.engine != "" && opts.remote == "" && (opts.batch != "" || opts.pipe) { // The server solves the puzzles of --batch with its engine
+		fail(fmt.Errorf("--engine only solves a single puzzle, not --batch or --pipe"))
+	}
+	if opts.remote != "" && opts.pipe {
+		fail(fmt.Errorf("--remote solves a single puzzle or the puzzles of --batch, not --pipe"))
+	}
+	if opts.batch != "" {
+		solveBatch(opts)
+		return
+	}
+	if opts.watchFile != "" {
+		watchFile(opts, rows)
+		return
+	}
+	if opts.pipe {
+		pipe(opts)
+		return
+	}
+	if err := solveOnce(opts, rows); err != nil {
+		// If there's an error (e.g., invalid input format, or no unique solution), print it and exit with its status.
+		fail(err)
+	}
+}
+
+// solveOnce solves the puzzle given by rows as solve does, and returns why it couldn't instead of exiting.
+func solveOnce(opts options, rows []string) error {
+	// Parse the command-line input to create the Sudoku grid, using the parse functions from the sudokux package.
+	start := time.Now()
+	grid, shape, constraints, killer, err := parseGrid(opts, rows)
+	parsed := time.Now()
+	if err == nil && opts.format != "grid" && opts.format != "line" && opts.format != "json" && opts.format != "tsv" {
+		err = fmt.Errorf("unknown --format %q (expected grid, line, json, or tsv)", opts.format)
+	}
+	if err == nil && opts.watch && opts.step {
+		err = fmt.Errorf("use either --watch or --step, not both")
+	}
+	if err == nil && opts.engine != "" && (opts.watch || opts.step) {
+		err = fmt.Errorf("--watch and --step show the backtracking search, so they can't be used with --engine")
+	}
+	if err == nil && opts.engine != "" && !isClassic(opts, grid, shape) {
+		err = fmt.Errorf("--engine only solves the classic rules with the default boxes")
+	}
+	if err == nil && opts.remote != "" && (opts.watch || opts.step) {
+		err = fmt.Errorf("--watch and --step show the search as it runs here, so they can't be used with --remote")
+	}
+	if err == nil && opts.remote != "" && !isClassic(opts, grid, shape) {
+		err = fmt.Errorf("--remote only solves the classic rules with the default boxes")
+	}
+	if err != nil {
+		if opts.format == "tsv" {
+			if writeErr := writeTSV(opts, "invalid", nil, 0, 0, 0); writeErr != nil {
+				return writeErr
+			}
+		}
+		return err
+	}
+
+	if opts.remote != "" {
+		return solveRemote(opts, grid, shape, parsed.Sub(start))
+	}
+	if opts.engine != "" {
+		return solveWithEngine(opts, grid, shape, parsed.Sub(start))
+	}
+
+	var steps *stepper
+	if opts.step {
+		steps = &stepper{in: bufio.NewScanner(os.Stdin)}
+		if isClassic(opts, grid, shape) { // The techniques only know the classic rules
+			grid = steps.logic(grid)
+		}
+	}
+	// Solve the Sudoku puzzle under the rules of its shape and variant (at most two solutions are needed to prove uniqueness).
+	state := newSearchState(opts, grid, constraints)
+	if opts.watch {
+		watch(state, grid, shape, opts.delay, colors(opts))
+	}
+	if steps != nil {
+		steps.search(state)
+	}
+	finished := runSearch(state, opts.timeout)
+	err = searchOutcome(opts, state, finished, grid, shape)
+	if opts.format == "tsv" { // A line for every outcome, which scripts tell apart by its status
+		var solution map[string]rune
+		if err == nil {
+			solution = state.Solutions[0]
+		}
+		if writeErr := writeTSV(opts, statusToken(err), solution, sudokux.CountGivens(grid), state.Nodes, time.Since(parsed)); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	// If the puzzle is successfully solved, write the solved grid in the requested format.
+	var t *timing
+	if opts.timing {
+		t = &timing{Parse: parsed.Sub(start), Solve: time.Since(parsed), Nodes: state.Nodes}
+	}
+	return writeSolution(opts, grid, state.Solutions[0], shape, killer, t)
+}
+
+// solveWithEngine solves grid with the engine named by opts.engine, and writes the solution as solveOnce does.
+// parse is the time parsing took, for --timing.
+func solveWithEngine(opts options, grid map[string]rune, shape sudokux.Shape, parse time.Duration) error {
+	engine, err := sudokux.EngineByName(opts.engine)
+	if err != nil {
+		return err
+	}
+	result, err := sudokux.Solve(grid, sudokux.WithEngine(engine), sudokux.WithTimeout(opts.timeout), sudokux.WithSeed(opts.seed))
+	if contradiction := sudokux.Diagnose(grid); errors.Is(err, sudokux.ErrNoSolution) && contradiction != nil {
+		err = fmt.Errorf("%w: %v", sudokux.ErrNoSolution, contradiction) // Explain where the contradiction lies, as searchOutcome does
+	}
+	if opts.format == "tsv" {
+		if writeErr := writeTSV(opts, statusToken(err), result.Solution, sudokux.CountGivens(grid), result.Stats.Nodes, result.Stats.Elapsed); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	var t *timing
+	if opts.timing {
+		t = &timing{Parse: parse, Solve: result.Stats.Elapsed, Nodes: result.Stats.Nodes}
+	}
+	return writeSolution(opts, grid, result.Solution, shape, nil, t)
+}
+
+// searchOutcome returns nil if the search finished with a unique solution, or else the error saying why not: it ran
+// out of time, the puzzle has no solution (with where the contradiction lies), or it has several.
+func searchOutcome(opts options, state *sudokux.SearchState, finished bool, grid map[string]rune, shape sudokux.Shape) error {
+	if !finished {
+		return fmt.Errorf("%w after %v", sudokux.ErrTimeout, opts.timeout)
+	}
+	switch len(state.Solutions) {
+	case 0:
+		// If the puzzle has no solution, explain where the contradiction lies (Diagnose only knows the classic rules with the default boxes).
+		if contradiction := sudokux.Diagnose(grid); isClassic(opts, grid, shape) && contradiction != nil {
+			return fmt.Errorf("%w: %v", sudokux.ErrNoSolution, contradiction)
+		}
+		return sudokux.ErrNoSolution
+	case 2:
+		// Otherwise the puzzle has solutions, but more than one.
+		return sudokux.ErrMultipleSolutions
+	}
+	return nil
+}
+
+// statusToken returns the one-word status of a puzzle for machine-readable output, for the error of solving it.
+func statusToken(err error) string {
+	switch {
+	case err == nil:
+		return "solved"
+	case errors.Is(err, sudokux.ErrNoSolution):
+		return "no-solution"
+	case errors.Is(err, sudokux.ErrMultipleSolutions):
+		return "multiple"
+	case errors.Is(err, sudokux.ErrTimeout):
+		return "timeout"
+	}
+	return "invalid"
+}
+
+// writeTSV writes the outcome of solving a puzzle to opts.output, or to the standard output when it is empty, as
+// one line of tab-separated fields: the status (see statusToken), the cells of the solution on one line (empty
+// without a solution), the number of givens, the number of search nodes, and the solving time in milliseconds.
+func writeTSV(opts options, status string, solution map[string]rune, givens, nodes int, elapsed time.Duration) error {
+	w, done, err := openOutput(opts)
+	if err != nil {
+		return err
+	}
+	cells := ""
+	if solution != nil {
+		cells = sudokux.Grid(solution).String()
+	}
+	fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.3f\n", status, cells, givens, nodes, milliseconds(elapsed))
+	return done()
+}
+
+// openOutput returns the file opts.output, created, or the standard output when it is empty, along with the
+// function to call once everything is written.
+func openOutput(opts options) (io.Writer, func() error, error) {
+	if opts.output == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	file, err := os.Create(opts.output)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, file.Close, nil
+}
+
+// timing holds how long a puzzle took to parse and to solve, for --timing.
+type timing struct {
+	Parse time.Duration
+	Solve time.Duration
+	Nodes int // Search nodes visited
+}
+
+// MarshalJSON writes the times in milliseconds, which JSON readers handle better than nanoseconds.
+func (t timing) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ParseMS float64 `json:"parse_ms"`
+		SolveMS float64 `json:"solve_ms"`
+		Nodes   int     `json:"nodes"`
+	}{milliseconds(t.Parse), milliseconds(t.Solve), t.Nodes})
+}
+
+// milliseconds returns d in milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// stepper walks through the solving of a puzzle one step at a time, printing every step and waiting for Enter
+// before the next one, so that users can follow how the program reasons. Entering "c" runs the remaining steps
+// without waiting.
+type stepper struct {
+	in    *bufio.Scanner // Answers of the user
+	count int            // Number of steps printed so far
+	auto  bool           // Whether to stop waiting
+}
+
+// logic applies the logical techniques to grid one deduction at a time, and returns the grid they leave for the
+// search to finish.
+func (s *stepper) logic(grid map[string]rune) map[string]rune {
+	filled, deductions, solved := sudokux.SolveLogically(grid)
+	for _, deduction := range deductions {
+		s.print(fmt.Sprintf("place %c in %s (%s): %s", deduction.Digit, deduction.Pos, deduction.Technique, deduction.Reason))
+	}
+	if !solved {
+		s.print("logic is stuck, so the search takes over and guesses")
+	}
+	return filled
+}
+
+// search makes the search print every placement, backtrack, and solution as a step.
+func (s *stepper) search(state *sudokux.SearchState) {
+	state.OnStep = func(event sudokux.StepEvent) {
+		switch event.Kind {
+		case sudokux.StepPlace:
+			s.print(fmt.Sprintf("guess %c in %s (depth %d)", event.Digit, event.Pos, event.Depth))
+		case sudokux.StepBacktrack:
+			s.print(fmt.Sprintf("take %c back from %s, which led to a contradiction", event.Digit, event.Pos))
+		default:
+			s.print("solution found; the search goes on to make sure it is the only one")
+		}
+	}
+}
+
+// print prints one step, then waits for the user unless they asked to run freely.
+func (s *stepper) print(step string) {
+	s.count++
+	fmt.Printf("Step %d: %s\n", s.count, step)
+	if s.auto {
+		return
+	}
+	fmt.Print("[Enter for the next step, c to finish] ")
+	if !s.in.Scan() || strings.TrimSpace(s.in.Text()) == "c" { // The end of the input runs freely too
+		s.auto = true
+	}
+}
+
+// newSearchState returns the search for up to two solutions of grid under constraints, which is enough to prove
+// whether the solution is unique, trying the digits in the order shuffled by opts.seed, and logging the starting
+// candidates and the search itself with --debug.
+func newSearchState(opts options, grid map[string]rune, constraints []sudokux.Constraint) *sudokux.SearchState {
+	state := sudokux.NewConstrainedSearchState(grid, 2, constraints)
+	state.Seed = opts.seed
+	if opts.debug {
+		state.Logger = slog.Default()
+		candidates := 0
+		for _, digits := range state.Candidates {
+			candidates += len(digits)
+		}
+		slog.Debug("search started", "empty", len(state.Candidates), "candidates", candidates, "constraints", len(constraints), "contradiction", state.Done)
+	}
+	return state
+}
+
+// runSearch runs the search to the end, or until timeout has passed when it is non-zero. It reports whether the
+// search finished.
+func runSearch(state *sudokux.SearchState, timeout time.Duration) bool {
+	start := time.Now()
+	defer func() {
+		slog.Debug("search stopped", "nodes", state.Nodes, "solutions", len(state.Solutions), "done", state.Done, "elapsed", time.Since(start))
+	}()
+	if timeout == 0 {
+		return state.Run(0)
+	}
+	deadline := time.Now().Add(timeout)
+	for !state.Run(10000) { // Check the clock every 10000 nodes
+		if time.Now().After(deadline) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSolution writes the solution to opts.output, or to the standard output when it is empty, in the format of
+// opts: "grid" prints the board (with the Killer cages drawn, if any), "line" prints every cell on one line, and
+// "json" prints the document of the puzzle and its solution (see sudokux.Document).
+func writeSolution(opts options, puzzle, solution map[string]rune, shape sudokux.Shape, killer *sudokux.KillerConstraint, t *timing) error {
+	w, done, err := openOutput(opts)
+	if err != nil {
+		return err
+	}
+	defer done()
+	var rows []string
+	for i := 0; i < shape.Size; i++ {
+		var row strings.Builder
+		for j := 0; j < shape.Size; j++ {
+			row.WriteRune(solution[shape.Pos(i, j)])
+		}
+		rows = append(rows, row.String())
+	}
+
+	switch opts.format {
+	case "line":
+		if _, err := fmt.Fprintln(w, strings.Join(rows, "")); err != nil || t == nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "parse_ms=%.3f solve_ms=%.3f nodes=%d\n", milliseconds(t.Parse), milliseconds(t.Solve), t.Nodes)
+		return err
+	case "json":
+		return json.NewEncoder(w).Encode(struct {
+			sudokux.Document
+			Timing *timing `json:"timing,omitempty"`
+		}{sudokux.NewDocument(shape, puzzle, &sudokux.Result{Solution: solution, SolutionCount: 1}), t})
+	}
+	if opts.output == "" {
+		fmt.Println("Sudoku solved successfully:")
+	}
+	// Call a helper function to print the solved Sudoku grid in a readable format.
+	if killer != nil {
+		printCages(w, solution, shape, killer)
+	} else {
+		printSudoku(w, solution, shape)
+	}
+	if t != nil {
+		fmt.Fprintf(w, "Parsed in %v, solved in %v (%d nodes)\n", t.Parse.Round(time.Microsecond), t.Solve.Round(time.Microsecond), t.Nodes)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sudokux"
+)
+
+// watchFile solves the puzzle of the file opts.watchFile (see --input), then solves it again every time the file
+// is saved, until the program is interrupted. Errors are printed without stopping, so that a puzzle can be fixed
+// while it is being edited. The file is polled rather than watched, which works on every system.
+func watchFile(opts options, rows []string) {
+	if len(rows) > 0 {
+		fmt.Fprintln(os.Stderr, "Error: give the rows either as arguments or with --watch-file, not both")
+		os.Exit(exitUsage)
+	}
+	opts.input = opts.watchFile
+	var modified time.Time // When the file was last saved, or the zero time if it was missing
+	missing := false       // Whether the missing file was already reported
+	for ; ; time.Sleep(watchInterval) {
+		info, err := os.Stat(opts.watchFile)
+		if err != nil { // Wait for the file to come back, such as while an editor replaces it
+			if !missing {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				missing = true
+			}
+			modified = time.Time{}
+			continue
+		}
+		missing = false
+		if info.ModTime().Equal(modified) {
+			continue
+		}
+		modified = info.ModTime()
+		fmt.Printf("--- %s changed at %s ---\n", opts.watchFile, modified.Format(time.TimeOnly))
+		if err := solveOnce(opts, nil); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
+}
+
+// watchInterval is how often --watch-file checks whether the file was saved.
+const watchInterval = 200 * time.Millisecond
+
+// ANSI escape sequences used to animate the search.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiGreen     = "\x1b[32m"
+	ansiRed       = "\x1b[31m"
+	ansiBlue      = "\x1b[34m"
+	ansiOrange    = "\x1b[38;5;208m"
+	ansiClearLine = "\x1b[K"
+)
+
+// palette holds the escape sequences that color the output, all empty when colors are off.
+type palette struct {
+	place     string // A digit just placed
+	backtrack string // A cell just cleared
+	clue      string // A given
+	reset     string // Back to the normal color
+}
+
+// palettes lists the palettes of --palette. Red and green look alike to many colorblind people, so the colorblind
+// palette uses blue and orange instead.
+var palettes = map[string]palette{
+	"default":    {place: ansiGreen + ansiBold, backtrack: ansiRed, clue: ansiBold, reset: ansiReset},
+	"colorblind": {place: ansiBlue + ansiBold, backtrack: ansiOrange, clue: ansiBold, reset: ansiReset},
+}
+
+// colors returns the palette of opts.palette, or no colors at all with --color=never, and with --color=auto when
+// the standard output isn't a terminal or the NO_COLOR environment variable is set (see no-color.org).
+func colors(opts options) palette {
+	switch opts.color {
+	case "always":
+		return palettes[opts.palette]
+	case "never":
+		return palette{}
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || !isTerminal(os.Stdout) {
+		return palette{}
+	}
+	return palettes[opts.palette]
+}
+
+// watch makes the search draw the grid in place on the terminal after every placement and backtrack, pausing delay
+// after each of them. The clues are drawn in bold, the digit just placed in green, and the cell just cleared in red,
+// under a line with the number of nodes tried so far and the depth of the search.
+func watch(state *sudokux.SearchState, clues map[string]rune, shape sudokux.Shape, delay time.Duration, colors palette) {
+	drawn := false
+	state.Delay = delay
+	state.OnStep = func(event sudokux.StepEvent) {
+		if drawn { // Move back up over the previous drawing to replace it
+			fmt.Printf("\x1b[%dA", shape.Size+1)
+		}
+		drawn = true
+		view := state.View()
+		for i := 0; i < shape.Size; i++ {
+			for j := 0; j < shape.Size; j++ {
+				pos := shape.Pos(i, j)
+				cell := string(view.Get(i, j))
+				switch {
+				case pos == event.Pos && event.Kind == sudokux.StepPlace:
+					cell = colors.place + cell + colors.reset
+				case pos == event.Pos && event.Kind == sudokux.StepBacktrack:
+					cell = colors.backtrack + "." + colors.reset
+				case clues[pos] != '.':
+					cell = colors.clue + cell + colors.reset
+				}
+				fmt.Print(cell, " ")
+			}
+			fmt.Println(ansiClearLine)
+		}
+		switch event.Kind {
+		case sudokux.StepPlace:
+			fmt.Printf("Nodes: %d, depth: %d, placed %c at %s%s\n", event.Nodes, event.Depth, event.Digit, event.Pos, ansiClearLine)
+		case sudokux.StepBacktrack:
+			fmt.Printf("Nodes: %d, depth: %d, took %c back from %s%s\n", event.Nodes, event.Depth, event.Digit, event.Pos, ansiClearLine)
+		default:
+			fmt.Printf("Nodes: %d, depth: %d, solution found%s\n", event.Nodes, event.Depth, ansiClearLine)
+		}
+	}
+}
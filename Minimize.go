@@ -0,0 +1,41 @@
+/*
+This file tightens hand-made puzzles: it removes every clue that the puzzle doesn't need, so that the solution
+stays unique but dropping any clue left would allow a second solution.
+
+Functions:
+- **`Minimize`**: Removes the redundant clues of a puzzle and reports which ones were dropped.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Minimize returns a copy of the puzzle without its redundant clues, along with the cells of the dropped clues in
+// row-major order. Clues are tried in row-major order, and each is dropped if the solution stays unique under the
+// constraints without it (nil means the classic rules). It returns an error if the puzzle doesn't have a unique
+// solution to begin with.
+func Minimize(grid map[string]rune, constraints []Constraint) (map[string]rune, []string, error) {
+	switch _, count := searchSolutions(grid, 2, constraints); count {
+	case 0:
+		return nil, nil, fmt.Errorf("the puzzle has no solution")
+	case 2:
+		return nil, nil, fmt.Errorf("the puzzle has more than one solution")
+	}
+
+	puzzle := make(map[string]rune)
+	copyGrid(grid, puzzle)
+	var dropped []string
+	for _, pos := range ShapeOf(grid).Cells() {
+		val := puzzle[pos]
+		if val == '.' {
+			continue
+		}
+		puzzle[pos] = '.'
+		if _, count := searchSolutions(puzzle, 2, constraints); count != 1 {
+			puzzle[pos] = val // The clue is needed
+			continue
+		}
+		dropped = append(dropped, pos)
+	}
+	return puzzle, dropped, nil
+}
@@ -0,0 +1,176 @@
+/*
+This file provides incremental move handling for interactive use, such as a UI where the player fills
+the grid one cell at a time. Instead of re-running the full solver after every keystroke, a `MoveState`
+keeps the candidate digits of every empty cell and only updates the cells affected by each move.
+
+- `Move`: A single change to a grid, checked by `Grid.Check` and applied by `Grid.Apply`. An illegal move is
+  refused with a `*MoveError` naming the cell it conflicts with, so that a frontend can highlight it.
+- `NewMoveState`: Builds a move state from a grid, computing the initial candidates of every empty cell.
+- `ApplyMove`: Places (or clears) a digit, checks its legality, updates the candidates of the peer cells
+  and reports whether the puzzle can still be completed as far as the candidates can tell.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Move is a single change to a grid: a digit placed in a cell, or the cell cleared when Digit is '.'.
+type Move struct {
+	Pos   string `json:"pos"`   // Position of the cell (e.g. "B3")
+	Digit rune   `json:"digit"` // Digit placed, or '.' to clear the cell
+}
+
+// MoveError explains why a move was refused.
+type MoveError struct {
+	Move     Move   // Move that was refused
+	Conflict string // Cell already holding the digit in the same unit, if that is why
+	Unit     string // Unit shared with that cell (e.g. "row B"), if any
+	Reason   string // Human-readable explanation
+}
+
+// Error returns the explanation.
+func (e *MoveError) Error() string {
+	return e.Reason
+}
+
+// Check reports whether move can be made on the grid under the classic rules, without making it. It returns a
+// *MoveError if the cell isn't on the board, the digit isn't one of the board's, or a peer of the cell already
+// holds the digit (the first one found in its row, then its column, then its box). Whatever the cell holds now is
+// ignored, so a digit can be replaced by another one.
+func (g Grid) Check(move Move) error {
+	shape := g.Shape()
+	row, col, ok := shape.ParsePos(move.Pos)
+	if _, exists := g[move.Pos]; !ok || !exists {
+		return &MoveError{Move: move, Reason: fmt.Sprintf("invalid position %q", move.Pos)}
+	}
+	if move.Digit == '.' { // Clearing a cell is always legal
+		return nil
+	}
+	if !shape.IsDigit(move.Digit) {
+		return &MoveError{Move: move, Reason: fmt.Sprintf("invalid digit %q", move.Digit)}
+	}
+	c := Coord{Row: row, Col: col}
+	rowCells, colCells := make([]Coord, shape.Size), make([]Coord, shape.Size)
+	for i := 0; i < shape.Size; i++ {
+		rowCells[i], colCells[i] = Coord{Row: row, Col: i}, Coord{Row: i, Col: col}
+	}
+	units := [][]Coord{rowCells, colCells, shape.boxCells(c.Box(shape))}
+	names := []string{shape.UnitName(row), shape.UnitName(shape.Size + col), shape.UnitName(2*shape.Size + c.Box(shape))}
+	for u, unit := range units {
+		for _, other := range unit {
+			if other != c && g.Get(other.Row, other.Col) == move.Digit {
+				return &MoveError{
+					Move:     move,
+					Conflict: other.String(),
+					Unit:     names[u],
+					Reason:   fmt.Sprintf("%c cannot be placed at %s: %s already holds %c in %s", move.Digit, move.Pos, other, move.Digit, names[u]),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Apply makes move on the grid if Check allows it, and returns the error of Check otherwise, leaving the grid
+// unchanged. Grids don't record which cells are clues, so Apply can change any cell (see MoveState for that).
+func (g Grid) Apply(move Move) error {
+	if err := g.Check(move); err != nil {
+		return err
+	}
+	g[move.Pos] = move.Digit
+	return nil
+}
+
+// MoveState holds a grid being played together with the candidates of its empty cells.
+type MoveState struct {
+	Shape      Shape                    // Dimensions of the board
+	Grid       map[string]rune          // Current contents of the grid, keyed by position (e.g. "A1")
+	Givens     map[string]bool          // Positions of the original clues, which cannot be changed
+	Candidates map[string]map[rune]bool // Digits that can still be placed in each empty cell
+}
+
+// NewMoveState creates a move state for the grid. The non-empty cells of the grid are treated as clues.
+func NewMoveState(grid map[string]rune) *MoveState {
+	state := &MoveState{
+		Shape:      ShapeOf(grid),
+		Grid:       make(map[string]rune),
+		Givens:     make(map[string]bool),
+		Candidates: make(map[string]map[rune]bool),
+	}
+	copyGrid(grid, state.Grid) // Work on a copy so the caller's grid is left untouched
+	for pos, val := range state.Grid {
+		if val != '.' { // Every filled cell of the starting grid is a clue
+			state.Givens[pos] = true
+		}
+	}
+	for pos := range state.Grid {
+		state.updateCandidates(pos) // Compute the starting candidates of every cell
+	}
+	return state
+}
+
+// ApplyMove places digit at pos, or clears the cell when digit is '.'.
+// It returns an error if the move is illegal, and otherwise reports whether the puzzle remains solvable,
+// that is, whether every empty cell still has a candidate and every unit still has room for each missing digit.
+func ApplyMove(state *MoveState, pos string, digit rune) (bool, error) {
+	if _, ok := state.Grid[pos]; !ok { // If the position is not part of the grid
+		return false, fmt.Errorf("invalid position %q", pos)
+	}
+	if !state.Shape.IsDigit(digit) && digit != '.' { // Only digits of the board and the empty marker are accepted
+		return false, fmt.Errorf("invalid digit %q", digit)
+	}
+	if state.Givens[pos] { // Clues from the original puzzle cannot be overwritten
+		return false, fmt.Errorf("cell %s is a clue and cannot be changed", pos)
+	}
+	if err := Grid(state.Grid).Check(Move{Pos: pos, Digit: digit}); err != nil { // Check the digit against the row, column, and subgrid
+		return false, err
+	}
+
+	state.Grid[pos] = digit     // Apply the move
+	state.updateCandidates(pos) // The changed cell gets new candidates
+	for _, peer := range state.Shape.tables().peers[pos] {
+		state.updateCandidates(peer) // Only the peers of the changed cell are affected by the move
+	}
+
+	return state.stillSolvable(), nil // Report whether the puzzle can still be completed
+}
+
+// updateCandidates recomputes the candidates of a single cell from the current grid.
+func (state *MoveState) updateCandidates(pos string) {
+	if state.Grid[pos] != '.' { // Filled cells have no candidates
+		delete(state.Candidates, pos)
+		return
+	}
+	candidates := make(map[rune]bool)
+	for _, num := range state.Shape.Digits() { // Try every digit in the cell
+		if isValid(state.Grid, pos, num) {
+			candidates[num] = true
+		}
+	}
+	state.Candidates[pos] = candidates
+}
+
+// stillSolvable checks that no empty cell has run out of candidates and that every unit
+// still has at least one possible place for each digit it is missing.
+func (state *MoveState) stillSolvable() bool {
+	for _, candidates := range state.Candidates {
+		if len(candidates) == 0 { // An empty cell with nothing left to place is a dead end
+			return false
+		}
+	}
+	for _, unit := range state.Shape.tables().units {
+		for _, num := range state.Shape.Digits() { // Every digit must either be placed or still fit somewhere in the unit
+			found := false
+			for _, pos := range unit {
+				if state.Grid[pos] == num || state.Candidates[pos][num] {
+					found = true
+					break
+				}
+			}
+			if !found { // The digit can no longer be placed anywhere in this unit
+				return false
+			}
+		}
+	}
+	return true
+}
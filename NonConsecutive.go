@@ -0,0 +1,100 @@
+/*
+This file adds the non-consecutive rule: orthogonally adjacent cells may not contain consecutive digits (a 4 can't
+sit next to a 3 or a 5). Checking placements alone makes for a slow search, so the constraint also prunes
+candidates before anything is placed: a digit can be removed from a cell when a neighbor has no candidate left
+that could sit next to it.
+
+Functions:
+- **`NewNonConsecutiveConstraint`**: Builds the constraint for a board of the given shape.
+*/
+
+package sudokux
+
+// NonConsecutiveConstraint forbids consecutive digits in orthogonally adjacent cells.
+type NonConsecutiveConstraint struct {
+	shape     Shape
+	cells     []string            // Every cell in row-major order, so eliminations are deterministic
+	neighbors map[string][]string // Orthogonal neighbors of each cell
+	adjacent  [][]int             // The same neighbors, as indexes into cells
+}
+
+// NewNonConsecutiveConstraint creates the non-consecutive rule for a board of the given shape.
+func NewNonConsecutiveConstraint(shape Shape) *NonConsecutiveConstraint {
+	c := &NonConsecutiveConstraint{
+		shape:     shape,
+		cells:     shape.Cells(),
+		neighbors: make(map[string][]string),
+		adjacent:  make([][]int, shape.Size*shape.Size),
+	}
+	for row := 0; row < shape.Size; row++ {
+		for col := 0; col < shape.Size; col++ {
+			pos := shape.Pos(row, col)
+			for _, step := range [][2]int{{-1, 0}, {0, -1}, {0, 1}, {1, 0}} {
+				r, col2 := row+step[0], col+step[1]
+				if r >= 0 && r < shape.Size && col2 >= 0 && col2 < shape.Size {
+					c.neighbors[pos] = append(c.neighbors[pos], shape.Pos(r, col2))
+					c.adjacent[row*shape.Size+col] = append(c.adjacent[row*shape.Size+col], r*shape.Size+col2)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *NonConsecutiveConstraint) Name() string {
+	return "non-consecutive"
+}
+
+// Allows reports whether no orthogonal neighbor of pos holds a digit consecutive to digit.
+func (c *NonConsecutiveConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	value := c.shape.DigitIndex(digit)
+	for _, neighbor := range c.neighbors[pos] {
+		if val := grid[neighbor]; val != '.' && val != 0 && consecutive(value, c.shape.DigitIndex(val)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the orthogonal neighbors of pos.
+func (c *NonConsecutiveConstraint) Peers(pos string) []string {
+	return c.neighbors[pos]
+}
+
+// Eliminate removes a candidate from a cell when one of its empty neighbors has no candidate that could sit next
+// to it: every candidate left there is the same digit or a consecutive one. (The same digit is ruled out too,
+// since neighbors always share a row or a column.)
+func (c *NonConsecutiveConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	// Look up every cell once: the checks below run for every candidate of every cell, after every placement
+	masks := make([]uint32, len(c.cells)) // Candidate values of each empty cell, one bit per value (0 if filled)
+	for i, pos := range c.cells {
+		if grid[pos] == '.' {
+			for _, digit := range candidates[pos] {
+				masks[i] |= 1 << c.shape.DigitIndex(digit)
+			}
+		}
+	}
+
+	var eliminations []Elimination
+	for i, pos := range c.cells {
+		if masks[i] == 0 { // Filled cells (and empty cells without candidates) have nothing to remove
+			continue
+		}
+		for _, digit := range candidates[pos] {
+			blocked := uint32(7) << c.shape.DigitIndex(digit) >> 1 // The digit itself and both consecutive digits
+			for _, neighbor := range c.adjacent[i] {
+				if masks[neighbor] != 0 && masks[neighbor]&^blocked == 0 { // Filled neighbors are handled by Allows
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+					break
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
+
+// consecutive reports whether two digit values differ by exactly one.
+func consecutive(a, b int) bool {
+	return a-b == 1 || b-a == 1
+}
@@ -0,0 +1,73 @@
+/*
+This file defines `Observer`, which the backtracking search notifies at its key events, so that metrics, tracing,
+visualization, and teaching tools can follow a solve without forking the search loop:
+- **`OnPlace`**: A digit is tried in a cell, before the eliminations the placement causes.
+- **`OnEliminate`**: A candidate is removed from an empty cell, by a placement or by the elimination hooks of the
+  constraints (see Constraint.go).
+- **`OnBacktrack`**: A digit is taken back out of a cell, restoring the candidates its placement removed.
+
+`OnStep` (see Search.go) reports placements and backtracks too, but it is the hook of the animations of the
+program, which also wait on `Delay` and `StepGate` after every event; observers are called in passing and only
+add the cost of the calls. Eliminations are only reported to observers, as there are many more of them.
+
+Set the observer on `SearchState.Observer`, or pass it to `Solve` with `WithObserver`. `ObserverFuncs` turns a
+few functions into an observer, for callers that only care about some of the events.
+*/
+
+package sudokux
+
+// Observer receives the events of a backtracking search. It is called from the goroutine running the search,
+// which waits for it to return.
+type Observer interface {
+	// OnPlace is called when the search tries a digit in a cell.
+	OnPlace(event StepEvent)
+	// OnEliminate is called when a candidate is removed from an empty cell; Kind is StepEliminate.
+	OnEliminate(event StepEvent)
+	// OnBacktrack is called when the search takes a digit back out of a cell.
+	OnBacktrack(event StepEvent)
+}
+
+// StepEliminate is the kind of the events of Observer.OnEliminate: a candidate was removed from a cell.
+const StepEliminate = "eliminate"
+
+// ObserverFuncs is an Observer made of functions, any of which can be nil to ignore its events.
+type ObserverFuncs struct {
+	Place     func(event StepEvent)
+	Eliminate func(event StepEvent)
+	Backtrack func(event StepEvent)
+}
+
+// OnPlace calls Place, if it is set.
+func (o ObserverFuncs) OnPlace(event StepEvent) {
+	if o.Place != nil {
+		o.Place(event)
+	}
+}
+
+// OnEliminate calls Eliminate, if it is set.
+func (o ObserverFuncs) OnEliminate(event StepEvent) {
+	if o.Eliminate != nil {
+		o.Eliminate(event)
+	}
+}
+
+// OnBacktrack calls Backtrack, if it is set.
+func (o ObserverFuncs) OnBacktrack(event StepEvent) {
+	if o.Backtrack != nil {
+		o.Backtrack(event)
+	}
+}
+
+// event returns the event of the given kind at the current point of the search.
+func (state *SearchState) event(kind, pos string, digit rune) StepEvent {
+	return StepEvent{Kind: kind, Pos: pos, Digit: digit, Depth: len(state.Stack), Nodes: state.Nodes}
+}
+
+// observeEliminations reports to the observer every digit of old missing from candidates.
+func (state *SearchState) observeEliminations(pos string, old, candidates []rune) {
+	for _, digit := range old {
+		if !containsRune(candidates, digit) {
+			state.Observer.OnEliminate(state.event(StepEliminate, pos, digit))
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+This file contains `Solve`, the configurable entry point of the solver. Rather than one function per combination of
+behaviors (a timeout, a different engine, a shuffled search, statistics...), `Solve` takes any number of options:
+
+	result, err := sudokux.Solve(grid, sudokux.WithTimeout(time.Second), sudokux.WithTrace())
+
+With no options it behaves like `SolveSudoku`, but returns a `Result` (see Result.go) with the outcome and the
+statistics of the search, and tells why it has no solution to return with one of the errors of Errors.go.
+The options are:
+- **`WithTimeout`**: Gives up once the search has run for a given time.
+- **`WithMaxNodes`**: Gives up once the backtracking search has visited a given number of nodes.
+- **`WithMaxSolutions`**: Stops after a given number of solutions (1 skips the uniqueness check, like `SolveAny`).
+- **`WithEngine`**: Solves with another engine, such as Dancing Links (see Engine.go).
+- **`WithHeuristic`**: Chooses how the backtracking search picks the next cell.
+- **`WithSeed`**: Tries digits in a shuffled, reproducible order.
+- **`WithStats`**: Fills in the statistics of the search once it is done, in addition to the result.
+- **`WithTrace`**: Records every placement and backtrack of the search in the result.
+- **`WithObserver`**: Notifies an observer of every placement, elimination, and backtrack (see Observer.go).
+
+Engines other than the backtracking search always look for two solutions with their own cell order, so
+`WithMaxSolutions`, `WithMaxNodes`, `WithHeuristic`, `WithSeed`, `WithTrace`, and `WithObserver` only apply to the
+backtracking search.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"maps"
+	"time"
+)
+
+// Option changes how Solve searches.
+type Option func(*solveConfig)
+
+// solveConfig gathers the options of a call to Solve.
+type solveConfig struct {
+	timeout      time.Duration // Zero means no time limit
+	maxNodes     int           // Zero means no node budget
+	maxSolutions int           // Number of solutions to look for (2 by default, to check uniqueness)
+	engine       Engine        // Nil means the backtracking search
+	heuristic    string        // Cell-selection heuristic of the backtracking search
+	seed         int64         // Non-zero to shuffle the digits of the backtracking search
+	stats        *SolveStats   // Filled in once the search is done, if not nil
+	trace        bool          // Whether to record the events of the backtracking search
+	observer     Observer      // Notified of the events of the backtracking search, if not nil
+}
+
+// SolveStats describes the work done by a call to Solve.
+type SolveStats struct {
+	Engine    string        `json:"engine"`    // Name of the engine that searched
+	Nodes     int           `json:"nodes"`     // Number of search nodes visited
+	Solutions int           `json:"solutions"` // Number of solutions found (never more than the maximum asked for)
+	Elapsed   time.Duration `json:"elapsed"`   // Time spent searching
+	TimedOut  bool          `json:"timed_out"` // Whether the search gave up on its timeout or node budget
+}
+
+// WithTimeout makes Solve give up once the search has run for d (0 means no limit).
+func WithTimeout(d time.Duration) Option {
+	return func(c *solveConfig) { c.timeout = d }
+}
+
+// WithMaxNodes makes the backtracking search give up once it has visited n nodes (0 means no budget). Unlike a
+// timeout, the budget gives the same outcome on every machine, however loaded.
+func WithMaxNodes(n int) Option {
+	return func(c *solveConfig) { c.maxNodes = n }
+}
+
+// WithMaxSolutions makes Solve stop after n solutions. The default of 2 is enough to tell whether the solution is
+// unique; 1 returns the first solution found without checking, and more count further solutions in the statistics.
+func WithMaxSolutions(n int) Option {
+	return func(c *solveConfig) { c.maxSolutions = n }
+}
+
+// WithEngine makes Solve use engine instead of the backtracking search.
+func WithEngine(engine Engine) Option {
+	return func(c *solveConfig) { c.engine = engine }
+}
+
+// WithHeuristic sets how the backtracking search picks the next cell (HeuristicMRV or HeuristicFirst).
+func WithHeuristic(heuristic string) Option {
+	return func(c *solveConfig) { c.heuristic = heuristic }
+}
+
+// WithSeed makes the backtracking search try digits in an order shuffled by seed (0 keeps ascending order).
+func WithSeed(seed int64) Option {
+	return func(c *solveConfig) { c.seed = seed }
+}
+
+// WithStats makes Solve fill in stats once the search is done.
+func WithStats(stats *SolveStats) Option {
+	return func(c *solveConfig) { c.stats = stats }
+}
+
+// WithTrace makes Solve record every placement, backtrack, and solution of the backtracking search in
+// Result.Trace. Traces of hard puzzles can be long.
+func WithTrace() Option {
+	return func(c *solveConfig) { c.trace = true }
+}
+
+// WithObserver makes the backtracking search notify observer of every placement, elimination, and backtrack.
+func WithObserver(observer Observer) Option {
+	return func(c *solveConfig) { c.observer = observer }
+}
+
+// Solve solves the grid under the classic rules as configured by opts. The result holds the first solution found if
+// it is the only one (or, with WithMaxSolutions(1), if there is any solution at all). Otherwise the error wraps
+// ErrInvalidGrid, ErrNoSolution, ErrMultipleSolutions, or ErrTimeout, and the result still tells how far the search
+// went; options Solve doesn't know, such as an unknown heuristic, are an error too. The grid is never modified.
+func Solve(grid map[string]rune, opts ...Option) (Result, error) {
+	config := solveConfig{maxSolutions: 2}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	heuristic := config.heuristic
+	if backtracking, ok := config.engine.(BacktrackingEngine); ok && heuristic == "" {
+		heuristic = backtracking.Heuristic
+	}
+	switch heuristic {
+	case "", HeuristicMRV, HeuristicFirst:
+	default: // Rather than searching with MRV as if nothing was asked
+		return Result{Status: StatusInvalid}, fmt.Errorf("unknown heuristic %q (expected %s or %s)", heuristic, HeuristicMRV, HeuristicFirst)
+	}
+	if conflict := findClueConflict(grid, ShapeOf(grid)); conflict != nil { // Conflicting clues could keep the search busy for long
+		return Result{Status: StatusInvalid}, fmt.Errorf("%w: %v", ErrInvalidGrid, conflict)
+	}
+	result := Result{Stats: SolveStats{Engine: BacktrackingEngine{Heuristic: config.heuristic}.Name()}}
+	start := time.Now()
+	var solution map[string]rune
+	if backtracking, ok := config.engine.(BacktrackingEngine); config.engine == nil || ok {
+		if ok && config.heuristic == "" { // The heuristic of the engine, unless WithHeuristic overrides it
+			config.heuristic = backtracking.Heuristic
+			result.Stats.Engine = backtracking.Name()
+		}
+		solution = solveBacktracking(grid, config, &result)
+	} else {
+		result.Stats.Engine = config.engine.Name()
+		solution = solveWithEngine(grid, config, &result.Stats)
+	}
+	result.Stats.Elapsed = time.Since(start)
+	result.SolutionCount = result.Stats.Solutions
+	if config.stats != nil {
+		*config.stats = result.Stats
+	}
+	switch {
+	case result.Stats.TimedOut:
+		result.Status = StatusTimeout
+		if config.maxNodes > 0 && result.Stats.Nodes >= config.maxNodes {
+			return result, fmt.Errorf("%w after %d nodes", ErrTimeout, result.Stats.Nodes)
+		}
+		return result, fmt.Errorf("%w after %v", ErrTimeout, config.timeout)
+	case result.SolutionCount == 0:
+		result.Status = StatusNoSolution
+	case result.SolutionCount > 1 && config.maxSolutions != 1:
+		result.Status = StatusMultipleSolutions
+	default:
+		result.Solution = solution
+	}
+	return result, result.Status.Err()
+}
+
+// solveBacktracking runs the backtracking search of Search.go as configured, checking the clock every 10000 nodes
+// when there is a timeout, and stopping on the node budget if there is one. It returns the first solution found, or
+// nil.
+func solveBacktracking(grid map[string]rune, config solveConfig, result *Result) map[string]rune {
+	state := NewSearchState(grid, config.maxSolutions)
+	state.Heuristic = config.heuristic
+	state.Seed = config.seed
+	state.Observer = config.observer
+	if config.trace {
+		state.OnStep = func(event StepEvent) { result.Trace = append(result.Trace, event) }
+	}
+	deadline := time.Now().Add(config.timeout)
+	for {
+		budget := 0 // Runs to the end without a timeout or a node budget
+		if config.timeout > 0 {
+			budget = 10000
+		}
+		if config.maxNodes > 0 {
+			left := config.maxNodes - state.Nodes
+			if left <= 0 {
+				result.Stats.TimedOut = true
+				break
+			}
+			if budget == 0 || left < budget {
+				budget = left
+			}
+		}
+		if state.Run(budget) {
+			break
+		}
+		if config.timeout > 0 && time.Now().After(deadline) {
+			result.Stats.TimedOut = true
+			break
+		}
+	}
+	result.Stats.Nodes, result.Stats.Solutions = state.Nodes, len(state.Solutions)
+	if len(state.Solutions) == 0 {
+		return nil
+	}
+	return state.Solutions[0]
+}
+
+// solveWithEngine solves grid with the configured engine. A StoppableEngine is stopped at the timeout; other engines
+// can't be interrupted, so on a timeout Solve stops waiting and the engine finishes in the background.
+func solveWithEngine(grid map[string]rune, config solveConfig, stats *SolveStats) map[string]rune {
+	if engine, ok := config.engine.(StoppableEngine); ok {
+		var stop func() bool // Nil, so the engine never stops, without a timeout
+		if config.timeout > 0 {
+			deadline := time.Now().Add(config.timeout)
+			stop = func() bool { return time.Now().After(deadline) }
+		}
+		solution, count, nodes, finished := engine.SolveUntil(grid, stop)
+		stats.Nodes, stats.Solutions, stats.TimedOut = nodes, count, !finished
+		if !finished {
+			return nil
+		}
+		return solution
+	}
+	type answer struct {
+		solution     map[string]rune
+		count, nodes int
+	}
+	done := make(chan answer, 1) // Buffered, so an engine that finishes after the timeout doesn't block forever
+	grid = maps.Clone(grid)      // The engine may still be reading it after Solve returns
+	go func() {
+		solution, count, nodes := config.engine.Solve(grid)
+		done <- answer{solution, count, nodes}
+	}()
+	var timeout <-chan time.Time // Nil, so it never fires, without a timeout
+	if config.timeout > 0 {
+		timer := time.NewTimer(config.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case result := <-done:
+		stats.Nodes, stats.Solutions = result.nodes, result.count
+		return result.solution
+	case <-timeout:
+		stats.TimedOut = true
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package sudokux
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSolveUnknownHeuristic(t *testing.T) {
+	grid, err := GridFromString(hardPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range [][]Option{
+		{WithHeuristic("fewest")},
+		{WithEngine(BacktrackingEngine{Heuristic: "fewest"})},
+	} {
+		if _, err := Solve(grid, opts...); err == nil || errors.Is(err, ErrTimeout) {
+			t.Errorf("Solve with an unknown heuristic = %v, want an error", err)
+		}
+	}
+	if _, err := Solve(grid, WithHeuristic(HeuristicFirst), WithMaxNodes(100)); !errors.Is(err, ErrTimeout) { // Searches, but not for long
+		t.Errorf("Solve with HeuristicFirst = %v, want a timeout after 100 nodes", err)
+	}
+}
+
+func TestSolveUntilStops(t *testing.T) {
+	grid := NewGrid(Classic)
+	for _, engine := range DefaultEngines() {
+		stoppable, ok := engine.(StoppableEngine)
+		if !ok {
+			t.Errorf("%s can't be stopped", engine.Name())
+			continue
+		}
+		if _, _, _, finished := stoppable.SolveUntil(grid, func() bool { return true }); finished {
+			t.Errorf("%s: SolveUntil finished though stopped", engine.Name())
+		}
+		if _, count, _, finished := stoppable.SolveUntil(grid, func() bool { return false }); !finished || count != 2 {
+			t.Errorf("%s: SolveUntil = %d solutions, finished %v, want 2 solutions, finished", engine.Name(), count, finished)
+		}
+	}
+}
+
+// TestSolveEngineTimeout checks that the engines stop at the timeout of Solve, rather than going on in the
+// background.
+func TestSolveEngineTimeout(t *testing.T) {
+	grid := NewGrid(Classic)
+	before := runtime.NumGoroutine()
+	for _, engine := range []Engine{DLXEngine{}, SATEngine{}} {
+		result, err := Solve(grid, WithEngine(engine), WithTimeout(time.Nanosecond))
+		if !errors.Is(err, ErrTimeout) || result.Status != StatusTimeout {
+			t.Errorf("%s: Solve() = %v, %v, want a timeout", engine.Name(), result.Status, err)
+		}
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines left running after the timeouts", after-before)
+	}
+}
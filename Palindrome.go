@@ -0,0 +1,82 @@
+/*
+This file adds palindrome lines: the digits along a line read the same forwards and backwards, so the first cell
+matches the last, the second matches the second to last, and so on.
+
+Lines are read from a clue file (see Clues.go) as "palindrome", a colon, and the cells along the line in order:
+
+	palindrome: A1 B2 C2 D3
+
+Functions:
+- **`NewPalindromeConstraint`**: Builds the constraint enforcing every palindrome line.
+*/
+
+package sudokux
+
+// PalindromeConstraint requires the digits along every palindrome line to read the same in both directions.
+type PalindromeConstraint struct {
+	shape   Shape
+	lines   [][]string
+	mirrors map[string][]string // The cells mirroring each cell (one per line through it)
+}
+
+// NewPalindromeConstraint creates the constraint for the given lines on a board of the given shape.
+func NewPalindromeConstraint(shape Shape, lines [][]string) *PalindromeConstraint {
+	c := &PalindromeConstraint{
+		shape:   shape,
+		lines:   lines,
+		mirrors: make(map[string][]string),
+	}
+	for _, line := range lines {
+		for i, pos := range line {
+			if mirror := line[len(line)-1-i]; mirror != pos { // The middle cell of an odd line mirrors itself
+				c.mirrors[pos] = append(c.mirrors[pos], mirror)
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *PalindromeConstraint) Name() string {
+	return "palindromes"
+}
+
+// Lines returns the palindrome lines of the constraint.
+func (c *PalindromeConstraint) Lines() [][]string {
+	return c.lines
+}
+
+// Allows reports whether digit at pos matches every filled cell mirroring pos.
+func (c *PalindromeConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	for _, mirror := range c.mirrors[pos] {
+		if val := grid[mirror]; val != '.' && val != 0 && val != digit {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the cells mirroring pos.
+func (c *PalindromeConstraint) Peers(pos string) []string {
+	return c.mirrors[pos]
+}
+
+// Eliminate removes a candidate from an empty cell when one of its mirrors can't hold the same digit: the mirror
+// is empty and lacks the candidate (filled mirrors are handled by Allows).
+func (c *PalindromeConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, line := range c.lines { // Lines and their cells are visited in order, so eliminations are deterministic
+		for i, pos := range line {
+			mirror := line[len(line)-1-i]
+			if mirror == pos || grid[pos] != '.' || grid[mirror] != '.' {
+				continue
+			}
+			for _, digit := range candidates[pos] {
+				if !containsRune(candidates[mirror], digit) {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
@@ -1,58 +1,106 @@
 /*
 This program parses and validates a Sudoku puzzle provided via command-line arguments and ensures it is solvable.
-The main function, `ParseInput`, reads a 9x9 grid of Sudoku input from the command line, verifies its correctness,
-and ensures that there are no conflicts in rows, columns, or 3x3 subgrids. It also checks that the grid contains
-at least 17 clues (non-empty cells) and is not completely empty. The supporting functions help ensure that the
-grid is valid according to Sudoku rules:
+The main function, `ParseInput`, reads a 9x9 grid (or a grid of another size, such as 6x6 or 16x16) of Sudoku input from the command line,
+verifies its correctness, and ensures that there are no conflicts in rows, columns, or subgrids. It also checks that
+a 9x9 grid contains at least 17 clues (non-empty cells) and that the grid is not completely empty. `ParseRows` does
+the same for rows that don't come from the command line, `ParseRowsShape` for boards whose boxes aren't the
+default ones for their size (see Shape.go), and `ParseRowsWithConstraints` for variants with rules of their own
+(see Variant.go). `ParseLine` reads a classic puzzle written on one line, and `ReadPuzzles` a whole collection of
+them, one per line. `ParseBytes` reads a puzzle from raw bytes in either form, such as the body of a request.
 
-- `validateInitialGrid`: Ensures no duplicates exist in the initial grid's rows, columns, or subgrids.
+The input is untrusted: it can come from the command line, a file, or the network. Every function here returns an
+error, and never panics, whatever the bytes: invalid UTF-8, multi-byte characters, NULs, or megabytes of them.
+Rows are measured in characters rather than bytes, and `ParseBytes` refuses input longer than `MaxInputBytes`
+before looking at it. The supporting functions help ensure that the grid is valid according to Sudoku rules:
+
+- `ValidateClues`: Ensures the clues respect a list of constraints, for variants with rules of their own.
 - `isEmptyGrid`: Checks whether the grid is completely empty.
 - `isValid`: Determines whether placing a specific number in a given position is valid according to Sudoku rules.
 
 These functions work together to ensure that the input is valid before attempting to solve the Sudoku puzzle.
+Grids that aren't parsed from text can be checked with `Validate` (see Validate.go), which reports every problem
+rather than the first one.
 */
 
 package sudokux
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"unicode/utf8"
 )
 
+// MaxInputBytes is the most bytes ParseBytes reads, and the longest line ReadPuzzles accepts. A 25x25 puzzle written
+// with a separator after every cell takes 1250 bytes, so it leaves room for comments and formatting.
+const MaxInputBytes = 64 << 10
+
 // ParseInput parses command-line arguments into a Sudoku grid and validates it.
+// The number of arguments decides the board size: 9 rows for a classic grid, or 4 to 25 rows for other boards.
 func ParseInput() (map[string]rune, error) {
-	// Ensure we have exactly 9 rows from the input (os.Args[1] to os.Args[9])
-	if len(os.Args) != 10 { // os.Args[0] is the program name, so we expect 10 arguments in total
-		return nil, fmt.Errorf("expected 9 rows of input, got %d", len(os.Args)-1) // Return an error if the count is incorrect
+	return ParseRows(os.Args[1:]) // os.Args[0] is the program name, the rows follow it
+}
+
+// ParseRows parses one string per row into a Sudoku grid and validates it, using the default boxes for the
+// number of rows.
+func ParseRows(rows []string) (map[string]rune, error) {
+	// Work out the board from the number of rows (9 for a classic grid, 4 to 25 for other boards)
+	shape, err := ShapeForSize(len(rows))
+	if err != nil {
+		return nil, fmt.Errorf("expected 9 rows of input, got %d: %v", len(rows), err) // Return an error if the count is incorrect
+	}
+	return ParseRowsShape(rows, shape)
+}
+
+// ParseRowsShape parses one string per row into a grid of the given shape and validates it against the boxes of
+// that shape, for boards such as a 6x6 grid with 3x2 boxes.
+func ParseRowsShape(rows []string, shape Shape) (map[string]rune, error) {
+	return ParseRowsWithConstraints(rows, shape, nil)
+}
+
+// ParseRowsWithConstraints parses one string per row into a grid of the given shape and validates its clues
+// against the given constraints. A nil list of constraints means the classic rules; otherwise the constraints
+// replace them, as in SolveWithConstraints, so that variants such as Jigsaw Sudoku can do without the boxes.
+// The minimum clue count of classic puzzles only applies to the classic rules, since other rules let puzzles
+// get by with fewer clues (or none at all).
+func ParseRowsWithConstraints(rows []string, shape Shape, constraints []Constraint) (map[string]rune, error) {
+	if err := shape.check(); err != nil {
+		return nil, err
+	}
+	if len(rows) != shape.Size {
+		return nil, fmt.Errorf("expected %d rows of input, got %d", shape.Size, len(rows)) // Return an error if the count is incorrect
 	}
 
 	// Create a map to store the Sudoku grid, where the key is the position (e.g., "A1") and the value is the rune at that position.
 	grid := make(map[string]rune)
 
-	// Define the mapping of row indices (0-8) to row names (A-I), corresponding to Sudoku grid rows.
-	rowNames := []rune{'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I'}
-
 	// Counter for the number of clues (non-empty cells in the grid).
 	clueCount := 0
 
-	// Iterate through each of the 9 rows in the command-line input (os.Args[1] to os.Args[9]).
-	for i := 1; i <= 9; i++ {
-		row := os.Args[i] // Get the row string from the arguments
-
-		// Ensure the row is exactly 9 characters long (standard for a Sudoku row).
-		if len(row) != 9 {
-			return nil, fmt.Errorf("row %d is not 9 characters long", i) // Return an error if the row length is not 9
+	// Iterate through each row of the input.
+	for i, row := range rows {
+		// Ensure the row is exactly as long as the board is wide, counting characters rather than bytes.
+		if utf8.RuneCountInString(row) != shape.Size {
+			return nil, fmt.Errorf("row %d is not %d characters long", i+1, shape.Size) // Return an error if the row length is wrong
 		}
 
 		// Iterate through each character in the row to check if it's valid and to populate the grid.
-		for j, char := range row {
-			// Check if the character is either a number ('1'-'9') or a dot ('.'). If not, return an error.
-			if (char < '1' || char > '9') && char != '.' {
-				return nil, fmt.Errorf("you can only input numbers and dots (invalid character found in row %d)", i)
+		j := -1 // Column of the character (range gives byte offsets, which multi-byte characters would throw off)
+		for _, char := range row {
+			j++
+			// Check if the character is either a digit of the board or a dot ('.'). If not, return an error.
+			if !shape.IsDigit(char) && char != '.' {
+				if shape.Size == Classic.Size {
+					return nil, fmt.Errorf("you can only input numbers and dots (invalid character found in row %d)", i+1)
+				}
+				return nil, fmt.Errorf("you can only input %s and dots (invalid character found in row %d)", symbols[:shape.Size], i+1)
 			}
 
 			// Generate the grid key in the format "A1", "A2", etc., where 'A' is the row name and '1' is the column number.
-			pos := string(rowNames[i-1]) + string(rune('1'+j))
+			pos := shape.Pos(i, j)
 
 			// Add the character to the grid at the corresponding position. If it's a dot ('.'), it means the cell is empty.
 			grid[pos] = char
@@ -64,39 +112,134 @@ func ParseInput() (map[string]rune, error) {
 		}
 	}
 
-	// After processing all rows, check if there are fewer than 17 clues.
-	if clueCount < 17 {
-		return nil, fmt.Errorf("invalid grid: less than 17 clues (only %d clues)", clueCount) // Return an error if there are fewer than 17 clues
+	// After processing all rows, check that there are enough clues for a unique solution to be possible.
+	if minimum := minimumClues(shape); constraints == nil && clueCount < minimum {
+		return nil, fmt.Errorf("%w: less than %d clues (only %d clues)", ErrInvalidGrid, minimum, clueCount) // Return an error if there are too few clues
 	}
 
 	// Additional validations
-	// 1. Validate the initial grid for conflicts (no duplicates in rows, columns, or subgrids).
-	if !validateInitialGrid(grid) {
-		return nil, fmt.Errorf("invalid grid: contains conflicts in rows, columns, or subgrids") // Return an error if there are conflicts
+	// 1. Validate the initial grid for conflicts (no duplicates in rows, columns, or subgrids) under the classic rules.
+	if conflict := findClueConflict(grid, shape); constraints == nil && conflict != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGrid, conflict) // Return an error naming the conflicting clues
+	}
+
+	// 2. Check if the grid is completely empty (variants such as Killer Sudoku can do without clues).
+	if constraints == nil && isEmptyGrid(grid) {
+		return nil, fmt.Errorf("%w: the entire grid is empty", ErrInvalidGrid) // Return an error if the grid is completely empty
 	}
 
-	// 2. Check if the grid is completely empty.
-	if isEmptyGrid(grid) {
-		return nil, fmt.Errorf("invalid grid: the entire grid is empty") // Return an error if the grid is completely empty
+	// 3. Validate the clues against the rules of the variant instead, if any.
+	if err := ValidateClues(grid, constraints); err != nil {
+		return nil, err
 	}
 
 	// If all checks pass, return the populated grid and no error.
 	return grid, nil
 }
 
-// validateInitialGrid checks if the starting grid is valid (no duplicates in rows, columns, or subgrids).
-func validateInitialGrid(grid map[string]rune) bool {
-	for pos, val := range grid { // Iterate over the grid to check for conflicts
-		if val != '.' { // If the cell is not empty
-			original := grid[pos]              // Temporarily store the original value
-			grid[pos] = '.'                    // Set the cell to empty to test the validity of placing the original value
-			if !isValid(grid, pos, original) { // If the original value is not valid in its position
-				return false // Return false if there's a conflict
+// ParseLine parses a classic puzzle written on one line as its 81 cells in reading order (such as "53..7....6..195
+// ..."), as in the .sdm collections of benchmark puzzles, and validates it as ParseRows does. Empty cells may be
+// written as '0' as well as '.'.
+func ParseLine(line string) (map[string]rune, error) {
+	size := Classic.Size
+	if count := utf8.RuneCountInString(line); count != size*size { // Counted before copying a line that may be huge
+		return nil, fmt.Errorf("expected %d cells, got %d", size*size, count)
+	}
+	cells := []rune(strings.ReplaceAll(line, "0", "."))
+	rows := make([]string, size)
+	for i := range rows {
+		rows[i] = string(cells[i*size : (i+1)*size])
+	}
+	return ParseRows(rows)
+}
+
+// ParseBytes parses a puzzle from raw bytes, written either as its rows on separate lines (as ParseRows takes them)
+// or as every cell on one line (as ParseLine takes them, for a classic puzzle). Surrounding spaces, blank lines,
+// lines starting with '#', and Windows line endings are ignored. It returns an error for input longer than
+// MaxInputBytes or that isn't valid UTF-8, and never panics, so it can be fed anything, including by a fuzzer.
+func ParseBytes(data []byte) (map[string]rune, error) {
+	if len(data) > MaxInputBytes {
+		return nil, fmt.Errorf("%w: the input is longer than %d bytes", ErrInvalidGrid, MaxInputBytes)
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: the input is not valid UTF-8 text", ErrInvalidGrid)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line) // Also drops the '\r' of Windows line endings
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	switch len(lines) {
+	case 0:
+		return nil, fmt.Errorf("%w: the input has no rows", ErrInvalidGrid)
+	case 1:
+		return ParseLine(lines[0])
+	}
+	return ParseRows(lines)
+}
+
+// ReadPuzzles reads classic puzzles from r, one per line (see ParseLine). Blank lines and lines starting with '#'
+// are skipped, and lines longer than MaxInputBytes are an error.
+func ReadPuzzles(r io.Reader) ([]Grid, error) {
+	var puzzles []Grid
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, MaxInputBytes)
+	number := 1
+	for ; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		grid, err := ParseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", number, err)
+		}
+		puzzles = append(puzzles, Grid(grid))
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return nil, fmt.Errorf("line %d: longer than %d bytes", number, MaxInputBytes)
+	} else if err != nil {
+		return nil, err
+	}
+	return puzzles, nil
+}
+
+// minimumClues returns the fewest clues a puzzle of the given shape needs to have a unique solution
+// (17 for a classic grid, 4 for a 4x4 grid), or 1 when no such bound is known.
+func minimumClues(shape Shape) int {
+	switch shape.Size {
+	case 4:
+		return 4
+	case 9:
+		return 17
+	}
+	return 1
+}
+
+// ValidateClues checks that the clues of grid respect every constraint, for variants whose rules go beyond
+// rows, columns, and subgrids (see Variant.go). It returns an error naming the first clue that breaks a rule,
+// scanning clues in row-major order.
+func ValidateClues(grid map[string]rune, constraints []Constraint) error {
+	for _, pos := range ShapeOf(grid).Cells() {
+		val := grid[pos]
+		if val == '.' || val == 0 {
+			continue // Only clues can break a rule
+		}
+		for _, constraint := range constraints {
+			if constraint.Allows(grid, pos, val) {
+				continue
+			}
+			for _, other := range constraint.Peers(pos) { // Name the clue it conflicts with, if there is one
+				if grid[other] == val {
+					return fmt.Errorf("%w: clues %s and %s both contain %c in the %s", ErrInvalidGrid, pos, other, val, constraint.Name())
+				}
 			}
-			grid[pos] = original // Restore the original value in the grid
+			return fmt.Errorf("%w: clue %c at %s breaks the %s rule", ErrInvalidGrid, val, pos, constraint.Name())
 		}
 	}
-	return true // Return true if no conflicts are found
+	return nil
 }
 
 // isEmptyGrid checks if the entire grid is empty (i.e., all cells contain '.').
@@ -111,32 +254,18 @@ func isEmptyGrid(grid map[string]rune) bool {
 
 // isValid checks if placing a number (num) at a given position (pos) is valid according to Sudoku rules.
 func isValid(grid map[string]rune, pos string, num rune) bool {
-	row := rune(pos[0]) // Extract the row from the position
-	col := rune(pos[1]) // Extract the column from the position
-
-	// Check the row for duplicates
-	for i := '1'; i <= '9'; i++ { // Loop through all columns in the current row
-		if grid[string(row)+string(i)] == num { // If the number already exists in the row
-			return false // Return false if a duplicate is found
-		}
+	peers, ok := ShapeOf(grid).tables().peers[pos] // Look up the cells sharing a row, column, or box with pos
+	if !ok {
+		return false // A position outside the board can never hold a number
 	}
 
-	// Check the column for duplicates
-	for i := 'A'; i <= 'I'; i++ { // Loop through all rows in the current column
-		if grid[string(i)+string(col)] == num { // If the number already exists in the column
-			return false // Return false if a duplicate is found
-		}
+	// Check the cell itself and every cell sharing its row, column, or box for duplicates
+	if grid[pos] == num {
+		return false
 	}
-
-	// Check the 3x3 subgrid for duplicates
-	startRow := rune('A') + (row-'A')/3*3 // Calculate the starting row of the 3x3 subgrid
-	startCol := rune('1') + (col-'1')/3*3 // Calculate the starting column of the 3x3 subgrid
-	for i := 0; i < 3; i++ {              // Loop through 3 rows of the subgrid
-		for j := 0; j < 3; j++ { // Loop through 3 columns of the subgrid
-			posCheck := string(startRow+rune(i)) + string(startCol+rune(j)) // Generate the position for subgrid cells
-			if grid[posCheck] == num {                                      // If the number already exists in the subgrid
-				return false // Return false if a duplicate is found
-			}
+	for _, peer := range peers {
+		if grid[peer] == num { // If the number already exists in the row, column, or box
+			return false // Return false if a duplicate is found
 		}
 	}
 
@@ -0,0 +1,118 @@
+package sudokux
+
+import (
+	"strings"
+	"testing"
+)
+
+// easyPuzzle is the puzzle of the readme, with its solution.
+const (
+	easyPuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	easySolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// rowsOf returns the cells of a grid of the given size as its rows, one per line.
+func rowsOf(cells string, size int) string {
+	var sb strings.Builder
+	for i := 0; i < len(cells); i += size {
+		sb.WriteString(cells[i:i+size] + "\n")
+	}
+	return sb.String()
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // Cells of the grid, or "" for an error
+	}{
+		{"rows", rowsOf(easyPuzzle, 9), easyPuzzle},
+		{"line", easyPuzzle, easyPuzzle},
+		{"line with zeros", strings.ReplaceAll(easyPuzzle, ".", "0"), easyPuzzle},
+		{"comments and blank lines", "# The readme\n\n" + rowsOf(easyPuzzle, 9) + "\n\n", easyPuzzle},
+		{"windows line endings", strings.ReplaceAll(rowsOf(easyPuzzle, 9), "\n", "\r\n"), easyPuzzle},
+		{"surrounding spaces", "  " + easyPuzzle + "\t\n", easyPuzzle},
+		{"4x4", "1...\n.2..\n..3.\n...4\n", "1....2....3....4"},
+		{"solved", easySolution, easySolution},
+		{"empty", "", ""},
+		{"only comments", "# Nothing\n\n", ""},
+		{"short line", easyPuzzle[:80], ""},
+		{"long line", easyPuzzle + ".", ""},
+		{"invalid character", "x" + easyPuzzle[1:], ""},
+		{"repeated digit", "55" + easyPuzzle[2:], ""},
+		{"uneven rows", "1...\n.2.\n..3.\n...4\n", ""},
+		{"nul", "\x00" + easyPuzzle[1:], ""},
+		{"multi-byte character", "é" + easyPuzzle[1:], ""},
+		{"invalid utf-8", "\xff" + easyPuzzle[1:], ""},
+		{"too long", strings.Repeat(".", MaxInputBytes+1), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid, err := ParseBytes([]byte(tt.input))
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("ParseBytes() = %s, want an error", CanonicalString(grid))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBytes() error: %v", err)
+			}
+			if got := CanonicalString(grid); got != tt.want {
+				t.Errorf("ParseBytes() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadPuzzles(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int // Number of puzzles, or -1 for an error
+	}{
+		{"none", "", 0},
+		{"one", easyPuzzle + "\n", 1},
+		{"comments and blank lines", "# Two puzzles\n" + easyPuzzle + "\n\n" + hardPuzzle, 2},
+		{"bad line", easyPuzzle + "\n" + easyPuzzle[:80] + "\n", -1},
+		{"too long", strings.Repeat(".", MaxInputBytes+1), -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puzzles, err := ReadPuzzles(strings.NewReader(tt.input))
+			switch {
+			case tt.want < 0 && err == nil:
+				t.Errorf("ReadPuzzles() read %d puzzles, want an error", len(puzzles))
+			case tt.want >= 0 && err != nil:
+				t.Errorf("ReadPuzzles() error: %v", err)
+			case tt.want >= 0 && len(puzzles) != tt.want:
+				t.Errorf("ReadPuzzles() read %d puzzles, want %d", len(puzzles), tt.want)
+			}
+		})
+	}
+}
+
+// FuzzParse feeds the parsers arbitrary input, which they must reject with an error rather than a panic. A grid
+// ParseBytes accepts must read back the same from its cells.
+func FuzzParse(f *testing.F) {
+	f.Add([]byte(rowsOf(easyPuzzle, 9)))
+	f.Add([]byte(easyPuzzle))
+	f.Add([]byte("1...\n.2..\r\n..3.\n...4"))
+	f.Add([]byte("# Comment\n\n" + hardPuzzle))
+	f.Add([]byte("é\x00\xff"))
+	f.Add([]byte("r1c1"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		grid, err := ParseBytes(data)
+		if err == nil {
+			again, err := GridFromString(CanonicalString(grid))
+			if err != nil {
+				t.Fatalf("GridFromString(%q) error: %v", CanonicalString(grid), err)
+			}
+			if CanonicalString(again) != CanonicalString(grid) {
+				t.Fatalf("GridFromString(%q) = %q", CanonicalString(grid), CanonicalString(again))
+			}
+		}
+		ReadPuzzles(strings.NewReader(string(data)))
+		ParseCoord(string(data))
+	})
+}
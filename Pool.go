@@ -0,0 +1,209 @@
+/*
+This file provides `SolverPool`, which distributes puzzles across a fixed number of worker goroutines so
+that batch jobs can use every core. Solving is safe to run concurrently: each call to `Solve` works
+on its own `SearchState` and copy of the grid, and the only mutable global state of the package is the engine
+registry, which is guarded by a lock.
+
+Usage:
+1. Create a pool with `NewSolverPool(workers)`, passing options of `Solve` such as `WithTimeout` if needed.
+2. Read from `Results()` in one goroutine while submitting puzzles with `Submit` in another.
+3. Call `Close` once every puzzle has been submitted; the results channel is closed when the last one is solved.
+
+Results arrive in completion order, not submission order; each result carries the index of its puzzle.
+`NewSolverPoolFunc` makes a pool whose workers solve some other way, such as by sending the puzzles to a server.
+
+`GenerateMany` runs generation (see Generate.go) on worker goroutines the same way. Hard grades reject most dug
+puzzles, so every worker keeps digging new grids, each with a seed of its own, and accepted puzzles are streamed
+as they are found until the target count is reached.
+*/
+
+package sudokux
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// PoolResult is the outcome of one puzzle solved by a SolverPool.
+type PoolResult struct {
+	Index    int             // Position of the puzzle in submission order, starting at 0
+	Puzzle   map[string]rune // The submitted puzzle
+	Solution map[string]rune // The unique solution, if Solved is true
+	Solved   bool            // Whether the puzzle has exactly one solution
+	Err      error           // Why it isn't solved (see Errors.go), if Solved is false
+}
+
+// poolJob is a puzzle waiting for a worker.
+type poolJob struct {
+	index  int
+	puzzle map[string]rune
+}
+
+// SolverPool solves puzzles on a fixed number of worker goroutines.
+type SolverPool struct {
+	jobs    chan poolJob    // Puzzles waiting to be solved
+	results chan PoolResult // Solved puzzles, in completion order
+	wg      sync.WaitGroup  // Tracks running workers so results can be closed after the last one
+	mu      sync.Mutex      // Guards next
+	next    int             // Index given to the next submitted puzzle
+	solve   SolveFunc       // Solves one puzzle
+}
+
+// SolveFunc solves a puzzle for a SolverPool, returning its unique solution, or an error saying why it has none.
+type SolveFunc func(grid map[string]rune) (map[string]rune, error)
+
+// NewSolverPool starts a pool with the given number of workers (runtime.NumCPU() if workers <= 0), solving every
+// puzzle with Solve and opts.
+func NewSolverPool(workers int, opts ...Option) *SolverPool {
+	return NewSolverPoolFunc(workers, func(grid map[string]rune) (map[string]rune, error) {
+		result, err := Solve(grid, opts...)
+		return result.Solution, err
+	})
+}
+
+// NewSolverPoolFunc starts a pool like NewSolverPool, whose workers solve the puzzles with solve. solve is called
+// from several goroutines at once.
+func NewSolverPoolFunc(workers int, solve SolveFunc) *SolverPool {
+	if workers <= 0 { // Default to one worker per core
+		workers = runtime.NumCPU()
+	}
+	pool := &SolverPool{
+		jobs:    make(chan poolJob, workers),
+		results: make(chan PoolResult, workers),
+		solve:   solve,
+	}
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
+		go pool.work()
+	}
+	go func() {
+		pool.wg.Wait()      // Once every worker has stopped...
+		close(pool.results) // ...no more results can arrive
+	}()
+	return pool
+}
+
+// Submit queues a puzzle and returns its index. The grid must not be modified until its result is received.
+// Submit blocks when every worker is busy and the results are not being read.
+func (pool *SolverPool) Submit(grid map[string]rune) int {
+	pool.mu.Lock()
+	index := pool.next
+	pool.next++
+	pool.mu.Unlock()
+	pool.jobs <- poolJob{index: index, puzzle: grid}
+	return index
+}
+
+// Close signals that no more puzzles will be submitted. The results channel is closed once they are all solved.
+func (pool *SolverPool) Close() {
+	close(pool.jobs)
+}
+
+// Results returns the channel on which solved puzzles are delivered.
+func (pool *SolverPool) Results() <-chan PoolResult {
+	return pool.results
+}
+
+// work solves queued puzzles until the pool is closed.
+func (pool *SolverPool) work() {
+	defer pool.wg.Done()
+	for job := range pool.jobs {
+		solution, err := pool.solve(job.puzzle) // Each call of Solve has its own search state, so workers never share data
+		pool.results <- PoolResult{Index: job.index, Puzzle: job.puzzle, Solution: solution, Solved: err == nil, Err: err}
+	}
+}
+
+// GeneratedPuzzle is one puzzle found by GenerateMany.
+type GeneratedPuzzle struct {
+	Index    int             // Position of the puzzle in the stream, starting at 0
+	Seed     int64           // Seed that generates the same puzzle with Generate
+	Puzzle   map[string]rune // The puzzle, with a unique solution
+	Solution map[string]rune // Its solution
+	Err      error           // Set on the last value of the stream instead of a puzzle if generation failed
+}
+
+// GenerateMany generates count puzzles matching opts on the given number of workers (runtime.NumCPU() if
+// workers <= 0), and delivers them on the returned channel as they are found. The channel is closed once count
+// puzzles have been delivered, or after a value carrying an error if generation fails (when no grid satisfies the
+// rules of the variant), and must be read until it is closed. Every attempt gets its own seed, counting up from
+// opts.Seed (or from a random seed when it is 0), so each puzzle can be generated again alone from its Seed; which
+// attempts succeed first, and so the order of the stream, depends on timing. opts.MaxAttempts and opts.TimeLimit
+// are ignored. It returns an error if the options are invalid.
+func GenerateMany(opts GenerateOptions, count, workers int) (<-chan GeneratedPuzzle, error) {
+	if _, err := opts.check(); err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		return nil, errors.New("the number of puzzles to generate must be positive")
+	}
+	if workers <= 0 { // Default to one worker per core
+		workers = runtime.NumCPU()
+	}
+	base := opts.Seed
+	if base == 0 {
+		base = time.Now().UnixNano()
+	}
+	opts.MaxAttempts, opts.TimeLimit = 1, 0 // Each attempt is a call to Generate with a seed of its own
+
+	results := make(chan GeneratedPuzzle, workers)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex // Guards attempt, found, and failed
+		attempt int64      // Offset from base of the next seed to try
+		found   int        // Number of puzzles accepted so far
+		failed  bool       // Whether an attempt failed, stopping generation
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if found == count {
+					mu.Unlock()
+					return
+				}
+				attempt++
+				seed := base + attempt - 1
+				mu.Unlock()
+				if seed == 0 { // 0 would ask Generate for a random seed
+					continue
+				}
+
+				attemptOpts := opts
+				attemptOpts.Seed = seed
+				puzzle, solution, err := Generate(attemptOpts)
+				if err != nil {
+					if errors.Is(err, errNoMatch) {
+						continue // Rejected: the dug puzzle didn't have the grade or the number of givens
+					}
+					mu.Lock()
+					first := !failed
+					failed, found = true, count // Every other attempt would fail the same way
+					mu.Unlock()
+					if first {
+						results <- GeneratedPuzzle{Index: -1, Seed: seed, Err: err}
+					}
+					return
+				}
+
+				mu.Lock()
+				if found == count { // Another worker reached the target first
+					mu.Unlock()
+					return
+				}
+				index := found
+				found++
+				mu.Unlock()
+				results <- GeneratedPuzzle{Index: index, Seed: seed, Puzzle: puzzle, Solution: solution}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()      // Once every worker has stopped...
+		close(results) // ...no more puzzles can arrive
+	}()
+	return results, nil
+}
@@ -0,0 +1,176 @@
+/*
+This file adds quadruple clues: a circle on the corner where four cells meet lists up to four digits, and every
+listed digit must appear among those four cells. A digit listed twice must appear twice (in diagonally opposite
+cells, since side-by-side cells share a row or a column).
+
+Quadruples are read from a clue file (see Clues.go) as "quad", the top-left cell of the four, a colon, and the
+digits in the circle:
+
+	quad B2: 1 3 7
+	quad E5: 4 4
+
+Functions:
+- **`NewQuadrupleConstraint`**: Builds the constraint enforcing every quadruple.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quadruple is a circle on the corner between four cells, listing digits that must appear among them.
+type Quadruple struct {
+	Cells  []string // The four cells around the corner: top-left, top-right, bottom-left, bottom-right
+	Digits []rune   // The digits in the circle, with repeats
+}
+
+// parseQuadruple reads a quadruple from the top-left cell of its four cells and the digits in its circle.
+func parseQuadruple(corner, text string, shape Shape) (Quadruple, error) {
+	row, col, ok := shape.ParsePos(corner)
+	if !ok {
+		return Quadruple{}, fmt.Errorf("%s is not a cell of the board", corner)
+	}
+	if row == shape.Size-1 || col == shape.Size-1 {
+		return Quadruple{}, fmt.Errorf("%s must have cells to its right and below it, as the top-left cell of a quadruple", corner)
+	}
+	quad := Quadruple{Cells: []string{corner, shape.Pos(row, col+1), shape.Pos(row+1, col), shape.Pos(row+1, col+1)}}
+	counts := make(map[rune]int)
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' || r == '\t' }) {
+		runes := []rune(strings.ToUpper(field))
+		if len(runes) != 1 || !shape.IsDigit(runes[0]) {
+			return Quadruple{}, fmt.Errorf("invalid digit %q in the quadruple", field)
+		}
+		digit := runes[0]
+		if counts[digit]++; counts[digit] > 2 {
+			return Quadruple{}, fmt.Errorf("digit %c can appear at most twice among four cells", digit)
+		}
+		quad.Digits = append(quad.Digits, digit)
+	}
+	if len(quad.Digits) == 0 || len(quad.Digits) > 4 {
+		return Quadruple{}, fmt.Errorf("a quadruple lists 1 to 4 digits, got %d", len(quad.Digits))
+	}
+	return quad, nil
+}
+
+// QuadrupleConstraint requires the digits of every quadruple to appear among its four cells.
+type QuadrupleConstraint struct {
+	shape  Shape
+	quads  []Quadruple
+	byCell map[string][]int    // Indexes of the quadruples each cell belongs to
+	peers  map[string][]string // The other cells of the quadruples containing each cell
+}
+
+// NewQuadrupleConstraint creates the constraint for the given quadruples on a board of the given shape.
+func NewQuadrupleConstraint(shape Shape, quads []Quadruple) *QuadrupleConstraint {
+	c := &QuadrupleConstraint{
+		shape:  shape,
+		quads:  quads,
+		byCell: make(map[string][]int),
+		peers:  make(map[string][]string),
+	}
+	for index, quad := range quads {
+		for _, pos := range quad.Cells {
+			c.byCell[pos] = append(c.byCell[pos], index)
+			for _, other := range quad.Cells {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *QuadrupleConstraint) Name() string {
+	return "quadruples"
+}
+
+// Quadruples returns the quadruples of the constraint.
+func (c *QuadrupleConstraint) Quadruples() []Quadruple {
+	return c.quads
+}
+
+// Allows reports whether digit at pos leaves enough empty cells in every quadruple through pos for the digits it
+// still misses.
+func (c *QuadrupleConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	for _, index := range c.byCell[pos] {
+		missing, empty := c.missing(grid, c.quads[index], pos, digit)
+		if len(missing) > empty {
+			return false
+		}
+	}
+	return true
+}
+
+// missing returns the digits of quad not yet placed among its cells, with repeats, once digit is placed at pos
+// (pass "" as pos to look at the grid as it is), along with the number of cells left empty.
+func (c *QuadrupleConstraint) missing(grid map[string]rune, quad Quadruple, pos string, digit rune) ([]rune, int) {
+	placed := make(map[rune]int)
+	empty := 0
+	for _, cell := range quad.Cells {
+		switch val := grid[cell]; {
+		case cell == pos:
+			placed[digit]++
+		case val != '.' && val != 0:
+			placed[val]++
+		default:
+			empty++
+		}
+	}
+	var missing []rune
+	for _, d := range quad.Digits {
+		if placed[d] > 0 {
+			placed[d]--
+		} else {
+			missing = append(missing, d)
+		}
+	}
+	return missing, empty
+}
+
+// Peers returns the other cells of the quadruples containing pos.
+func (c *QuadrupleConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate looks at the digits each quadruple still misses. When there are as many as empty cells, every empty
+// cell must take one of them, so other candidates are removed. It returns false if a missing digit is a candidate
+// of none of the empty cells.
+func (c *QuadrupleConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, quad := range c.quads { // Quadruples and their cells are visited in order, so eliminations are deterministic
+		missing, empty := c.missing(grid, quad, "", 0)
+		if len(missing) > empty {
+			return nil, false
+		}
+		for _, d := range missing {
+			found := false
+			for _, pos := range quad.Cells {
+				if grid[pos] == '.' && containsRune(candidates[pos], d) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, false
+			}
+		}
+		if len(missing) < empty {
+			continue
+		}
+		for _, pos := range quad.Cells {
+			if grid[pos] != '.' {
+				continue
+			}
+			for _, digit := range candidates[pos] {
+				if !containsRune(missing, digit) {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
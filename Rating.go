@@ -0,0 +1,143 @@
+/*
+This file grades how hard a puzzle is for a person, by the hardest technique of Logic.go it needs:
+- **Easy**: Naked singles solve the whole grid.
+- **Medium**: Hidden singles are needed as well.
+- **Hard**: Logic gets stuck, so at least one guess is needed (see Backdoor.go to count them).
+
+For finer sorting, puzzles also get a numeric score on the scale of Sudoku Explainer, from the techniques of
+Score.go: pointing and claiming, pairs and triples, and X-Wings and Swordfish on top of the singles. Scores up to
+4.0 match those of Sudoku Explainer; harder puzzles score 4.2 or more, by the search nodes they take rather than by
+the techniques Sudoku Explainer would use (see Score.go).
+
+A rating also estimates how long a person takes to solve the puzzle. Each deduction costs a set time depending on
+its technique (spotting a hidden single is quicker than checking every digit for a naked single), and every cell
+left once logic gets stuck costs half a minute of trial and error, more for puzzles with a higher score. The estimate is
+given as a range around that total, since solvers vary.
+
+Functions:
+- **`Rate`**: Grades a puzzle.
+- **`RatePuzzle`**: Grades a puzzle and reports the techniques it needs, without revealing the solution.
+- **`Rating.SolveTime`**: Estimates how long a person takes to solve a rated puzzle.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"time"
+)
+
+// Difficulty is the grade of a puzzle, from DifficultyEasy to DifficultyHard.
+type Difficulty string
+
+// Grades of puzzles, from the easiest to the hardest.
+const (
+	DifficultyEasy   Difficulty = "easy"   // Naked singles are enough
+	DifficultyMedium Difficulty = "medium" // Hidden singles are needed
+	DifficultyHard   Difficulty = "hard"   // Guessing is needed
+)
+
+// difficulties lists the grades from the easiest to the hardest.
+var difficulties = []Difficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+
+// ParseDifficulty returns the grade with the given name (such as "hard").
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, difficulty := range difficulties {
+		if string(difficulty) == name {
+			return difficulty, nil
+		}
+	}
+	return "", fmt.Errorf("unknown difficulty %q (expected easy, medium, or hard)", name)
+}
+
+// rank returns the position of the grade from the easiest (0) to the hardest.
+func (d Difficulty) rank() int {
+	for i, difficulty := range difficulties {
+		if difficulty == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// TechniqueUse counts the deductions a puzzle needs from one technique.
+type TechniqueUse struct {
+	Technique string // Name of the technique (e.g. "hidden single")
+	Count     int    // Number of deductions made with it
+}
+
+// Rating describes how hard a puzzle is, without revealing any of its digits.
+type Rating struct {
+	Difficulty    Difficulty     // Grade of the puzzle
+	Score         float64        // Numeric score on the Sudoku Explainer scale (see Score.go), rounded to one decimal
+	Techniques    []TechniqueUse // Techniques used by logic, from the simplest to the hardest (unused ones left out)
+	NeedsGuessing bool           // Whether logic gets stuck before the grid is full
+	LogicFilled   int            // Number of empty cells logic fills
+	Empty         int            // Number of empty cells in the puzzle
+}
+
+// RatePuzzle grades a puzzle under the classic rules and reports the techniques it needs. The puzzle is expected
+// to have a unique solution.
+func RatePuzzle(grid map[string]rune) Rating {
+	rating := grade(grid)
+	rating.Score = score(grid)
+	return rating
+}
+
+// grade returns the rating of a puzzle without its score, which takes much longer to work out.
+func grade(grid map[string]rune) Rating {
+	_, deductions, solved := SolveLogically(grid)
+	rating := Rating{NeedsGuessing: !solved, LogicFilled: len(deductions), Empty: len(grid) - CountGivens(grid)}
+	for _, t := range techniques {
+		use := TechniqueUse{Technique: t.name}
+		for _, deduction := range deductions {
+			if deduction.Technique == t.name {
+				use.Count++
+			}
+		}
+		if use.Count > 0 {
+			rating.Techniques = append(rating.Techniques, use)
+		}
+	}
+
+	switch {
+	case !solved:
+		rating.Difficulty = DifficultyHard
+	case len(rating.Techniques) > 0 && rating.Techniques[len(rating.Techniques)-1].Technique == "hidden single":
+		rating.Difficulty = DifficultyMedium
+	default:
+		rating.Difficulty = DifficultyEasy
+	}
+	return rating
+}
+
+// deductionTimes is the time a person takes, on average, to find a deduction with each technique of Logic.go.
+var deductionTimes = map[string]time.Duration{
+	"naked single":  15 * time.Second,
+	"hidden single": 10 * time.Second,
+}
+
+// guessTime is the time a person takes, on average, to fill a cell by trial and error once logic is stuck.
+const guessTime = 30 * time.Second
+
+// SolveTime returns the range of times a person takes to solve the rated puzzle, rounded to whole minutes (at
+// least one): the deductions of each technique and the cells left to trial and error add up to an estimate, and
+// the range goes from a quarter less to a quarter more than it.
+func (r Rating) SolveTime() (time.Duration, time.Duration) {
+	var estimate time.Duration
+	for _, use := range r.Techniques {
+		estimate += time.Duration(use.Count) * deductionTimes[use.Technique]
+	}
+	if r.NeedsGuessing {
+		factor := 1 + max(r.Score-stuckWeight, 0)/4 // Harder searches take longer per cell
+		estimate += time.Duration(float64(r.Empty-r.LogicFilled) * factor * float64(guessTime))
+	}
+	low := max(time.Duration(float64(estimate)*0.75).Round(time.Minute), time.Minute)
+	high := max(time.Duration(float64(estimate)*1.25).Round(time.Minute), low)
+	return low, high
+}
+
+// Rate grades a classic puzzle by the hardest technique it needs. The puzzle is expected to have a unique solution.
+func Rate(grid map[string]rune) Difficulty {
+	return grade(grid).Difficulty
+}
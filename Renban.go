@@ -0,0 +1,132 @@
+/*
+This file adds Renban lines: the digits along a line are all different and form a set of consecutive digits, in
+any order (a line of 4 cells may hold 3, 6, 4, 5, but not 3, 4, 6, 7). A line of n cells covers one window of n
+consecutive digits, so the constraint prunes the candidates of every cell to the windows still open to the line.
+
+Lines are read from a clue file (see Clues.go) as "renban", a colon, and the cells along the line in order:
+
+	renban: A1 A2 B3
+
+Functions:
+- **`NewRenbanConstraint`**: Builds the constraint enforcing every Renban line.
+*/
+
+package sudokux
+
+import "fmt"
+
+// checkRenban checks that a Renban line has no more cells than there are digits.
+func checkRenban(cells []string, shape Shape) error {
+	if len(cells) > shape.Size {
+		return fmt.Errorf("a Renban line of %d cells needs more than the %d digits of the board", len(cells), shape.Size)
+	}
+	return nil
+}
+
+// RenbanConstraint requires the digits along every Renban line to be different and consecutive.
+type RenbanConstraint struct {
+	shape  Shape
+	lines  [][]string
+	byCell map[string][]int    // Indexes of the lines each cell is on
+	peers  map[string][]string // The other cells of the lines containing each cell
+}
+
+// NewRenbanConstraint creates the constraint for the given lines on a board of the given shape.
+func NewRenbanConstraint(shape Shape, lines [][]string) *RenbanConstraint {
+	c := &RenbanConstraint{
+		shape:  shape,
+		lines:  lines,
+		byCell: make(map[string][]int),
+		peers:  make(map[string][]string),
+	}
+	for index, line := range lines {
+		for _, pos := range line {
+			c.byCell[pos] = append(c.byCell[pos], index)
+			for _, other := range line {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *RenbanConstraint) Name() string {
+	return "renban"
+}
+
+// Lines returns the Renban lines of the constraint.
+func (c *RenbanConstraint) Lines() [][]string {
+	return c.lines
+}
+
+// Allows reports whether digit at pos keeps every line through pos different and within a window as long as the
+// line.
+func (c *RenbanConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	value := digitValue(c.shape, digit)
+	for _, index := range c.byCell[pos] {
+		line := c.lines[index]
+		low, high := value, value
+		for _, cell := range line {
+			if val := grid[cell]; cell != pos && val != '.' && val != 0 {
+				if val == digit {
+					return false
+				}
+				low, high = min(low, digitValue(c.shape, val)), max(high, digitValue(c.shape, val))
+			}
+		}
+		if high-low >= len(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the other cells of the lines containing pos.
+func (c *RenbanConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate keeps, in every empty cell of a line, only the candidates inside a window the line can still cover:
+// the window holds every digit already on the line, and every digit of the window missing from the line is a
+// candidate of one of its empty cells. It returns false if no window is left for a line.
+func (c *RenbanConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, line := range c.lines { // Lines and their cells are visited in order, so eliminations are deterministic
+		var placed, offered uint32 // Digits on the line, and candidates of its empty cells, one bit per value
+		for _, pos := range line {
+			if val := grid[pos]; val != '.' && val != 0 {
+				placed |= 1 << digitValue(c.shape, val)
+				continue
+			}
+			for _, digit := range candidates[pos] {
+				offered |= 1 << digitValue(c.shape, digit)
+			}
+		}
+
+		var open uint32 // Digits within at least one window the line can still cover
+		for low := 1; low+len(line)-1 <= c.shape.Size; low++ {
+			window := uint32(1)<<(low+len(line)) - uint32(1)<<low
+			if placed&^window == 0 && window&^placed&^offered == 0 {
+				open |= window
+			}
+		}
+		if open == 0 {
+			return nil, false
+		}
+
+		for _, pos := range line {
+			if grid[pos] != '.' {
+				continue
+			}
+			for _, digit := range candidates[pos] {
+				if bit := uint32(1) << digitValue(c.shape, digit); open&bit == 0 || placed&bit != 0 {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
@@ -0,0 +1,78 @@
+/*
+This file defines `Result`, everything `Solve` found out about a puzzle: the solution, if there is a unique one, the
+outcome as a `Status`, how many solutions the search found, its statistics, and, with `WithTrace`, every placement
+and backtrack it made. New information goes into new fields, so callers don't break when it is added.
+*/
+
+package sudokux
+
+import "fmt"
+
+// Status is the outcome of solving a puzzle.
+type Status int
+
+// Outcomes of solving a puzzle.
+const (
+	StatusSolved            Status = iota // The puzzle has exactly one solution (or any, with WithMaxSolutions(1))
+	StatusNoSolution                      // The clues can't be completed
+	StatusMultipleSolutions               // The clues can be completed in several ways
+	StatusInvalid                         // The clues break a rule
+	StatusTimeout                         // The search gave up before finding an answer
+)
+
+// String returns the one-word name of the status, such as "solved" or "no-solution".
+func (s Status) String() string {
+	switch s {
+	case StatusSolved:
+		return "solved"
+	case StatusNoSolution:
+		return "no-solution"
+	case StatusMultipleSolutions:
+		return "multiple"
+	case StatusInvalid:
+		return "invalid"
+	case StatusTimeout:
+		return "timeout"
+	}
+	return "unknown"
+}
+
+// MarshalText writes the status as its name in JSON, such as "solved".
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a status from its name, such as "solved".
+func (s *Status) UnmarshalText(text []byte) error {
+	for status := StatusSolved; status <= StatusTimeout; status++ {
+		if status.String() == string(text) {
+			*s = status
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q", text)
+}
+
+// Err returns the sentinel error of the status (see Errors.go), or nil for StatusSolved.
+func (s Status) Err() error {
+	switch s {
+	case StatusSolved:
+		return nil
+	case StatusNoSolution:
+		return ErrNoSolution
+	case StatusMultipleSolutions:
+		return ErrMultipleSolutions
+	case StatusInvalid:
+		return ErrInvalidGrid
+	}
+	return ErrTimeout
+}
+
+// Result describes the outcome of a call to Solve.
+type Result struct {
+	Solution      Grid        `json:"solution,omitempty"` // The solution, or nil unless Status is StatusSolved
+	Status        Status      `json:"status"`             // Outcome of the search
+	SolutionCount int         `json:"solution_count"`     // Number of solutions found (never more than the maximum asked for)
+	Stats         SolveStats  `json:"stats"`              // Work done by the search
+	Trace         []StepEvent `json:"trace,omitempty"`    // Every placement, backtrack, and solution, with WithTrace
+}
@@ -0,0 +1,277 @@
+/*
+This file implements a third solving engine, which treats Sudoku as a boolean satisfiability (SAT) problem. Every
+candidate placement ("digit d in cell r,c") is a boolean variable, and the rules become clauses, each of which
+needs at least one of its literals to be true:
+1. **At least one**: every cell holds a digit, and every digit appears in every row, column, and box.
+2. **At most one**: no two digits share a cell, and no digit appears twice in a row, column, or box (one clause
+   "not a or not b" for every pair).
+The givens are variables set to true before the search starts.
+
+The solver is a plain DPLL search: it sets a variable, propagates the clauses left with a single literal that
+isn't false (unit propagation, with two watched literals per clause so that only the clauses of the literal just
+made false are looked at), and backtracks on a clause whose literals are all false. It branches on a literal of
+the at-least-one clause with the fewest literals left, which is the MRV heuristic of Search.go once more.
+
+Like DLX.go, it shares no code with the other engines, which makes it a good check on them (see Bench.go), and
+it only knows the classic rules, for any board shape.
+
+- `SATEngine`: The engine, named "sat".
+*/
+
+package sudokux
+
+// SATEngine solves puzzles under the classic rules with a SAT solver.
+type SATEngine struct{}
+
+// Name returns "sat".
+func (SATEngine) Name() string {
+	return "sat"
+}
+
+// Solve searches for up to two solutions of grid.
+func (e SATEngine) Solve(grid map[string]rune) (map[string]rune, int, int) {
+	solution, count, nodes, _ := e.SolveUntil(grid, nil)
+	return solution, count, nodes
+}
+
+// SolveUntil searches for up to two solutions of grid, giving up once stop returns true (nil never stops).
+func (SATEngine) SolveUntil(grid map[string]rune, stop func() bool) (map[string]rune, int, int, bool) {
+	shape := ShapeOf(grid)
+	s := sudokuFormula(grid, shape)
+	if s == nil {
+		return nil, 0, 0, true
+	}
+	s.stop = stop
+	var first []bool // Variables of the first solution found
+	count := 0
+	s.search(func() bool {
+		if count == 0 {
+			first = make([]bool, len(s.value))
+			for v, val := range s.value {
+				first[v] = val > 0
+			}
+		}
+		count++
+		return count < 2 // Two solutions are enough to tell unique puzzles apart
+	})
+	if count == 0 {
+		return nil, 0, s.nodes, !s.stopped
+	}
+	solution := make(map[string]rune)
+	copyGrid(grid, solution)
+	n, digits := shape.Size, shape.Digits()
+	for v, val := range first {
+		if val {
+			cell, digit := v/n, v%n
+			solution[shape.Pos(cell/n, cell%n)] = digits[digit]
+		}
+	}
+	return solution, count, s.nodes, !s.stopped
+}
+
+// Count returns the number of solutions of grid, stopping at limit (0 means no limit).
+func (SATEngine) Count(grid map[string]rune, limit int) int {
+	s := sudokuFormula(grid, ShapeOf(grid))
+	if s == nil {
+		return 0
+	}
+	count := 0
+	s.search(func() bool {
+		count++
+		return limit <= 0 || count < limit
+	})
+	return count
+}
+
+// satSolver is a formula in conjunctive normal form and the state of its search. Variable v is written as the
+// literal 2v when it is true and 2v+1 when it is false, so that flipping the last bit negates a literal.
+type satSolver struct {
+	clauses [][]int     // Literals of every clause; the first two are watched
+	watches [][]int     // Clauses watching every literal
+	choices []int       // At-least-one clauses, which the search branches on
+	value   []int8      // Value of every variable: 1 for true, -1 for false, 0 when unassigned
+	trail   []int       // Literals made true, in order
+	head    int         // Number of literals of the trail already propagated
+	nodes   int         // Number of decisions made by the search
+	stop    func() bool // Called at every decision, to give up when it returns true, if not nil
+	stopped bool        // Whether stop made the search give up
+}
+
+// sudokuFormula returns the formula of grid under the classic rules, with its givens already set, or nil if two
+// givens conflict. The variable of digit d in cell r,c is (r*n+c)*n+d, as the rows of the DLX matrix.
+func sudokuFormula(grid map[string]rune, shape Shape) *satSolver {
+	n := shape.Size
+	s := &satSolver{watches: make([][]int, 2*n*n*n), value: make([]int8, n*n*n)}
+	exactlyOne := func(vars []int) {
+		lits := make([]int, len(vars))
+		for i, v := range vars {
+			lits[i] = 2 * v
+		}
+		s.choices = append(s.choices, len(s.clauses))
+		s.addClause(lits)
+		for i := range vars {
+			for j := i + 1; j < len(vars); j++ {
+				s.addClause([]int{2*vars[i] + 1, 2*vars[j] + 1})
+			}
+		}
+	}
+	units := make([][]int, 3*n) // Cells of every row, column, and box
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			cell := r*n + c
+			units[r] = append(units[r], cell)
+			units[n+c] = append(units[n+c], cell)
+			units[2*n+shape.BoxOf(r, c)] = append(units[2*n+shape.BoxOf(r, c)], cell)
+			vars := make([]int, n)
+			for digit := range vars {
+				vars[digit] = cell*n + digit
+			}
+			exactlyOne(vars)
+		}
+	}
+	for _, unit := range units {
+		for digit := 0; digit < n; digit++ {
+			vars := make([]int, n)
+			for i, cell := range unit {
+				vars[i] = cell*n + digit
+			}
+			exactlyOne(vars)
+		}
+	}
+	for r := 0; r < n; r++ { // The givens are set before the search starts
+		for c := 0; c < n; c++ {
+			digit := shape.DigitIndex(grid[shape.Pos(r, c)])
+			if digit >= 0 && !s.assign(2*((r*n+c)*n+digit)) {
+				return nil
+			}
+		}
+	}
+	if !s.propagate() { // The givens conflict
+		return nil
+	}
+	return s
+}
+
+// addClause adds a clause of two literals or more, watching its first two.
+func (s *satSolver) addClause(lits []int) {
+	s.watches[lits[0]] = append(s.watches[lits[0]], len(s.clauses))
+	s.watches[lits[1]] = append(s.watches[lits[1]], len(s.clauses))
+	s.clauses = append(s.clauses, lits)
+}
+
+// valueOf returns the value of a literal: 1 for true, -1 for false, 0 when its variable is unassigned.
+func (s *satSolver) valueOf(lit int) int8 {
+	if lit&1 == 1 {
+		return -s.value[lit>>1]
+	}
+	return s.value[lit>>1]
+}
+
+// assign makes lit true, and returns false if it is already false.
+func (s *satSolver) assign(lit int) bool {
+	switch s.valueOf(lit) {
+	case -1:
+		return false
+	case 1:
+		return true
+	}
+	s.value[lit>>1] = 1 - 2*int8(lit&1)
+	s.trail = append(s.trail, lit)
+	return true
+}
+
+// propagate makes true the last literal left of every clause whose other literals are all false, until there is
+// no such clause. It returns false if a clause has all its literals false.
+func (s *satSolver) propagate() bool {
+	for s.head < len(s.trail) {
+		falseLit := s.trail[s.head] ^ 1
+		s.head++
+		watching := s.watches[falseLit]
+		kept := watching[:0] // The clauses that still watch falseLit, filtered in place
+		for i, index := range watching {
+			clause := s.clauses[index]
+			if clause[0] == falseLit { // Keep the false literal second
+				clause[0], clause[1] = clause[1], clause[0]
+			}
+			if s.valueOf(clause[0]) == 1 { // The clause is satisfied
+				kept = append(kept, index)
+				continue
+			}
+			moved := false
+			for k := 2; k < len(clause); k++ { // Watch another literal that isn't false, if there is one
+				if s.valueOf(clause[k]) != -1 {
+					clause[1], clause[k] = clause[k], clause[1]
+					s.watches[clause[1]] = append(s.watches[clause[1]], index)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			kept = append(kept, index)
+			if !s.assign(clause[0]) { // Every literal of the clause is false
+				kept = append(kept, watching[i+1:]...)
+				s.watches[falseLit] = kept
+				return false
+			}
+		}
+		s.watches[falseLit] = kept
+	}
+	return true
+}
+
+// undo unassigns the literals of the trail after the first length ones.
+func (s *satSolver) undo(length int) {
+	for _, lit := range s.trail[length:] {
+		s.value[lit>>1] = 0
+	}
+	s.trail = s.trail[:length]
+	s.head = length
+}
+
+// search looks for every assignment that satisfies the clauses, calling found with the variables set to each one
+// until it returns false, or until s.stop does. It reports whether the search should go on.
+func (s *satSolver) search(found func() bool) bool {
+	if !s.propagate() {
+		return true
+	}
+	best, bestCount := -1, 0 // The at-least-one clause with the fewest unassigned literals, if any isn't satisfied
+	for _, index := range s.choices {
+		count, satisfied := 0, false
+		for _, lit := range s.clauses[index] {
+			switch s.valueOf(lit) {
+			case 1:
+				satisfied = true
+			case 0:
+				count++
+			}
+		}
+		if !satisfied && (best < 0 || count < bestCount) {
+			best, bestCount = index, count
+		}
+	}
+	if best < 0 { // Every clause is satisfied, and every variable is assigned
+		return found()
+	}
+	var lit int
+	for _, lit = range s.clauses[best] {
+		if s.valueOf(lit) == 0 {
+			break
+		}
+	}
+	if s.nodes++; s.stop != nil && s.stop() { // Decisions take long enough, with their propagation, to call stop at every one
+		s.stopped = true
+		return false
+	}
+	length := len(s.trail)
+	for _, branch := range []int{lit, lit ^ 1} { // Either the literal is true, or it is false
+		s.assign(branch)
+		more := s.search(found)
+		s.undo(length)
+		if !more {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+/*
+This file defines `Document`, the JSON form of a puzzle and of what solving it found out, for programs that store
+puzzles or exchange them with services built on this package. It is the format of the `--format json` output of
+the program, and is meant to stay readable by the programs written against it:
+
+	{"schema_version": 1, "shape": {"size": 9, "box_rows": 3, "box_cols": 3},
+	 "puzzle": ["53..7....", ...], "solution": ["534678912", ...], "status": "solved", "solution_count": 1,
+	 "stats": {"engine": "backtrack", "nodes": 51, ...}, "trace": [{"kind": "place", "pos": "A3", "digit": "4", ...}]}
+
+Grids are written as their rows, with '.' for empty cells, rather than as maps from positions to runes, whose
+digits would come out as character codes. Every field but the version and the shape is optional.
+
+The rules of the schema:
+1. **Fields are only ever added**: a reader must ignore the fields it doesn't know, as encoding/json does, and a
+   writer can leave out the optional fields it has nothing to say about.
+2. **`schema_version` changes only when a field changes meaning or goes away**, which should be never. Readers
+   reject documents with a version they don't know, rather than misread them.
+
+Functions:
+- **`NewDocument`**: Returns the document of a puzzle, and of the result of solving it if there is one.
+- **`MarshalDocument`** / **`UnmarshalDocument`**: Convert a document to JSON and back, checking its version.
+- **`Grids`**: Returns the puzzle and the solution of a document as grids.
+- **`StepEvent.MarshalJSON`** / **`UnmarshalJSON`**: Write the events of traces with their digit as a string.
+*/
+
+package sudokux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaVersion is the version of the JSON documents written by this package (see Document).
+const SchemaVersion = 1
+
+// Document is the versioned JSON form of a puzzle and of the outcome of solving it.
+type Document struct {
+	SchemaVersion int         `json:"schema_version"`           // Version of the schema, SchemaVersion when written by this package
+	Shape         Shape       `json:"shape"`                    // Dimensions of the board
+	Puzzle        []string    `json:"puzzle,omitempty"`         // Rows of the puzzle, with '.' for empty cells
+	Solution      []string    `json:"solution,omitempty"`       // Rows of the solution, if the puzzle has a unique one
+	Status        *Status     `json:"status,omitempty"`         // Outcome of solving the puzzle, if it was solved
+	SolutionCount int         `json:"solution_count,omitempty"` // Number of solutions found, if it was solved
+	Stats         *SolveStats `json:"stats,omitempty"`          // Work done solving the puzzle (the elapsed time is in nanoseconds)
+	Trace         []StepEvent `json:"trace,omitempty"`          // Every placement, backtrack, and solution of the search, if recorded
+}
+
+// NewDocument returns the document of puzzle, a board of the given shape, with what result found out about it if
+// result isn't nil. puzzle may be nil for a document that only holds a solution.
+func NewDocument(shape Shape, puzzle map[string]rune, result *Result) Document {
+	doc := Document{SchemaVersion: SchemaVersion, Shape: shape}
+	if puzzle != nil {
+		doc.Puzzle = gridRows(puzzle, shape)
+	}
+	if result != nil {
+		status := result.Status
+		doc.Status, doc.SolutionCount, doc.Trace = &status, result.SolutionCount, result.Trace
+		if result.Stats != (SolveStats{}) {
+			stats := result.Stats
+			doc.Stats = &stats
+		}
+		if result.Solution != nil {
+			doc.Solution = gridRows(result.Solution, shape)
+		}
+	}
+	return doc
+}
+
+// MarshalDocument returns the JSON of doc, writing the current SchemaVersion if doc has none.
+func MarshalDocument(doc Document) ([]byte, error) {
+	if doc.SchemaVersion == 0 {
+		doc.SchemaVersion = SchemaVersion
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalDocument reads a document from its JSON. It returns an error if the document has no schema_version or
+// a later one than SchemaVersion, or if its shape isn't one NewShape returns; fields it doesn't know are ignored.
+func UnmarshalDocument(data []byte) (Document, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Document{}, err
+	}
+	switch {
+	case doc.SchemaVersion == 0:
+		return Document{}, fmt.Errorf("the document has no schema_version")
+	case doc.SchemaVersion > SchemaVersion:
+		return Document{}, fmt.Errorf("the document has schema version %d, but only versions up to %d are known", doc.SchemaVersion, SchemaVersion)
+	}
+	if err := doc.Shape.check(); err != nil {
+		return Document{}, fmt.Errorf("invalid shape in the document: %v", err)
+	}
+	return doc, nil
+}
+
+// Grids returns the puzzle and the solution of the document as grids, or nil for those it doesn't have. The rows
+// are checked against the shape, but not against the rules of the puzzle, which the document doesn't record.
+func (doc Document) Grids() (puzzle, solution map[string]rune, err error) {
+	if puzzle, err = rowsGrid(doc.Puzzle, doc.Shape); err != nil {
+		return nil, nil, fmt.Errorf("puzzle: %w", err)
+	}
+	if solution, err = rowsGrid(doc.Solution, doc.Shape); err != nil {
+		return nil, nil, fmt.Errorf("solution: %w", err)
+	}
+	return puzzle, solution, nil
+}
+
+// gridRows returns the rows of grid, with '.' for the cells that aren't filled.
+func gridRows(grid map[string]rune, shape Shape) []string {
+	rows := make([]string, shape.Size)
+	for r := range rows {
+		var row strings.Builder
+		for c := 0; c < shape.Size; c++ {
+			if val, ok := grid[shape.Pos(r, c)]; ok && val != 0 {
+				row.WriteRune(val)
+			} else {
+				row.WriteByte('.')
+			}
+		}
+		rows[r] = row.String()
+	}
+	return rows
+}
+
+// rowsGrid returns the grid of the given rows, or nil if there are none.
+func rowsGrid(rows []string, shape Shape) (map[string]rune, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	if err := shape.check(); err != nil {
+		return nil, err
+	}
+	if len(rows) != shape.Size {
+		return nil, fmt.Errorf("%w: expected %d rows, got %d", ErrInvalidGrid, shape.Size, len(rows))
+	}
+	grid := NewGrid(shape)
+	for r, row := range rows {
+		if utf8.RuneCountInString(row) != shape.Size {
+			return nil, fmt.Errorf("%w: row %d is not %d characters long", ErrInvalidGrid, r+1, shape.Size)
+		}
+		c := 0
+		for _, val := range row {
+			if val != '.' && !shape.IsDigit(val) {
+				return nil, fmt.Errorf("%w: invalid character %q in row %d", ErrInvalidGrid, val, r+1)
+			}
+			grid[shape.Pos(r, c)] = val
+			c++
+		}
+	}
+	return grid, nil
+}
+
+// stepEventJSON is the JSON form of a StepEvent, with the digit as a string rather than a character code.
+type stepEventJSON struct {
+	Kind  string `json:"kind"`
+	Pos   string `json:"pos,omitempty"`
+	Digit string `json:"digit,omitempty"`
+	Depth int    `json:"depth"`
+	Nodes int    `json:"nodes"`
+}
+
+// MarshalJSON writes the event with its digit as a string, such as "5".
+func (e StepEvent) MarshalJSON() ([]byte, error) {
+	event := stepEventJSON{Kind: e.Kind, Pos: e.Pos, Depth: e.Depth, Nodes: e.Nodes}
+	if e.Digit != 0 {
+		event.Digit = string(e.Digit)
+	}
+	return json.Marshal(event)
+}
+
+// UnmarshalJSON reads an event written by MarshalJSON.
+func (e *StepEvent) UnmarshalJSON(data []byte) error {
+	var event stepEventJSON
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	*e = StepEvent{Kind: event.Kind, Pos: event.Pos, Depth: event.Depth, Nodes: event.Nodes}
+	if event.Digit != "" {
+		digit, size := utf8.DecodeRuneInString(event.Digit)
+		if size != len(event.Digit) {
+			return fmt.Errorf("invalid digit %q in a step event", event.Digit)
+		}
+		e.Digit = digit
+	}
+	return nil
+}
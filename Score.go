@@ -0,0 +1,331 @@
+/*
+This file computes the numeric score of Rating.go, on the scale of Sudoku Explainer: the weight of the hardest
+technique a puzzle needs when solved by always applying the easiest technique available. Unlike the techniques of
+Logic.go, which only place digits, most of these remove candidates, so the solver here keeps the candidates of
+every empty cell from one step to the next.
+
+The techniques, from the easiest to the hardest, with their weights in Sudoku Explainer:
+- **Hidden single**: 1.2 in a box, 1.5 in a row or column.
+- **Naked single**: 2.3.
+- **Pointing** / **Claiming**: 2.6 / 2.8. The candidates of a digit in a box all lie on one line (or those of a line
+  all lie in one box), so the digit is removed from the rest of the line (or of the box).
+- **Naked pair** / **triple**: 3.0 / 3.6. Two (three) cells of a unit hold only two (three) digits between them,
+  which are removed from the rest of the unit.
+- **X-Wing** / **Swordfish**: 3.2 / 3.8. A digit can only go in the same two (three) columns of two (three) rows, so
+  it is removed from the rest of those columns, and the same with rows and columns swapped.
+- **Hidden pair** / **triple**: 3.4 / 4.0. Two (three) digits can only go in the same two (three) cells of a unit,
+  so the other candidates of those cells are removed.
+
+Sudoku Explainer goes on with wings, uniqueness techniques, and chains, scoring from 4.2 (the XY-Wing) up to about
+11, which this solver doesn't know. In their place, a puzzle on which the techniques above get stuck scores 4.2 plus
+half the base-2 logarithm of the number of search nodes needed to finish it, so that such puzzles still sort by how
+much trial and error they take; only scores up to 4.0 match those of Sudoku Explainer exactly. The "direct" variants
+of Sudoku Explainer (a pointing or a hidden pair that directly gives a hidden single, from 1.7) are left out too, so
+a few puzzles score a little higher here than there.
+*/
+
+package sudokux
+
+import (
+	"math"
+	"math/bits"
+)
+
+// scoreTechnique is a technique of the scoring solver.
+type scoreTechnique struct {
+	name   string
+	weight float64                  // Weight of the technique on the Sudoku Explainer scale
+	apply  func(b *scoreBoard) bool // Makes one deduction, if it finds one that changes the board
+}
+
+// scoreTechniques lists the techniques of the scoring solver from the easiest to the hardest.
+var scoreTechniques = []scoreTechnique{
+	{name: "hidden single in a box", weight: 1.2, apply: func(b *scoreBoard) bool { return b.hiddenSingle(2*b.shape.Size, 3*b.shape.Size) }},
+	{name: "hidden single in a line", weight: 1.5, apply: func(b *scoreBoard) bool { return b.hiddenSingle(0, 2*b.shape.Size) }},
+	{name: "naked single", weight: 2.3, apply: (*scoreBoard).nakedSingle},
+	{name: "pointing", weight: 2.6, apply: (*scoreBoard).pointing},
+	{name: "claiming", weight: 2.8, apply: (*scoreBoard).claiming},
+	{name: "naked pair", weight: 3.0, apply: func(b *scoreBoard) bool { return b.nakedSubset(2) }},
+	{name: "x-wing", weight: 3.2, apply: func(b *scoreBoard) bool { return b.fish(2) }},
+	{name: "hidden pair", weight: 3.4, apply: func(b *scoreBoard) bool { return b.hiddenSubset(2) }},
+	{name: "naked triple", weight: 3.6, apply: func(b *scoreBoard) bool { return b.nakedSubset(3) }},
+	{name: "swordfish", weight: 3.8, apply: func(b *scoreBoard) bool { return b.fish(3) }},
+	{name: "hidden triple", weight: 4.0, apply: func(b *scoreBoard) bool { return b.hiddenSubset(3) }},
+}
+
+// stuckWeight is the weight of the XY-Wing, the easiest technique of Sudoku Explainer that scoreTechniques lack.
+const stuckWeight = 4.2
+
+// scoreBoard is the grid of the scoring solver, with the candidates of its empty cells.
+type scoreBoard struct {
+	shape      Shape
+	tables     *shapeTables
+	grid       map[string]rune         // Digits placed so far
+	candidates map[string]CandidateSet // Candidates of every empty cell
+}
+
+// score returns the numeric score of a puzzle (see the top of the file).
+func score(grid map[string]rune) float64 {
+	shape := ShapeOf(grid)
+	b := &scoreBoard{shape: shape, tables: shape.tables(), grid: make(map[string]rune, len(grid)), candidates: make(map[string]CandidateSet)}
+	copyGrid(grid, b.grid)
+	for _, pos := range b.tables.cells {
+		if grid[pos] == '.' {
+			b.candidates[pos] = Candidates(grid, pos)
+		}
+	}
+	score := 0.0
+	for len(b.candidates) > 0 {
+		progress := false
+		for _, t := range scoreTechniques { // Always the easiest technique that makes progress
+			if t.apply(b) {
+				score, progress = max(score, t.weight), true
+				break
+			}
+		}
+		if !progress {
+			state := NewSearchState(b.grid, 1)
+			state.Run(0)
+			return math.Round((stuckWeight+math.Log2(float64(state.Nodes+1))/2)*10) / 10
+		}
+	}
+	return score
+}
+
+// place puts the digit of index i at pos, and removes it from the candidates of the peers of pos.
+func (b *scoreBoard) place(pos string, i int) {
+	b.grid[pos] = rune(symbols[i])
+	delete(b.candidates, pos)
+	for _, peer := range b.tables.peers[pos] {
+		if set, ok := b.candidates[peer]; ok {
+			b.candidates[peer] = set &^ (1 << i)
+		}
+	}
+}
+
+// remove removes the digits of set from the candidates of the empty cells of cells, except those for which keep
+// returns true. It reports whether any candidate was removed.
+func (b *scoreBoard) remove(cells []string, set CandidateSet, keep func(pos string) bool) bool {
+	removed := false
+	for _, pos := range cells {
+		if old, ok := b.candidates[pos]; ok && old&set != 0 && !keep(pos) {
+			b.candidates[pos], removed = old&^set, true
+		}
+	}
+	return removed
+}
+
+// where returns the cells of unit in which the digit of index i is a candidate, as a bitmask of their indexes in
+// the unit.
+func (b *scoreBoard) where(unit []string, i int) CandidateSet {
+	var cells CandidateSet
+	for j, pos := range unit {
+		if b.candidates[pos]&(1<<i) != 0 {
+			cells |= 1 << j
+		}
+	}
+	return cells
+}
+
+// hiddenSingle places a digit with a single possible cell in one of the units from index first up to last.
+func (b *scoreBoard) hiddenSingle(first, last int) bool {
+	for _, unit := range b.tables.units[first:last] {
+		for i := 0; i < b.shape.Size; i++ {
+			if cells := b.where(unit, i); cells.Count() == 1 {
+				b.place(unit[bits.TrailingZeros32(uint32(cells))], i)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nakedSingle places the only candidate of a cell.
+func (b *scoreBoard) nakedSingle() bool {
+	for _, pos := range b.tables.cells {
+		if set, ok := b.candidates[pos]; ok && set.Count() == 1 {
+			b.place(pos, bits.TrailingZeros32(uint32(set)))
+			return true
+		}
+	}
+	return false
+}
+
+// pointing removes a digit from a row or column when its candidates in a box all lie on that line.
+func (b *scoreBoard) pointing() bool {
+	size := b.shape.Size
+	for n, box := range b.tables.units[2*size:] {
+		inBox := func(pos string) bool { return b.tables.cellUnits[pos][2] == 2*size+n }
+		for i := 0; i < size; i++ {
+			var lines []int // Row and column of the first candidate, or -1 once another candidate is off them
+			for _, pos := range box {
+				if b.candidates[pos]&(1<<i) == 0 {
+					continue
+				}
+				units := b.tables.cellUnits[pos]
+				if lines == nil {
+					lines = []int{units[0], units[1]}
+				}
+				for k, line := range lines {
+					if line != units[k] {
+						lines[k] = -1
+					}
+				}
+			}
+			for _, line := range lines {
+				if line >= 0 && b.remove(b.tables.units[line], 1<<i, inBox) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// claiming removes a digit from a box when its candidates in a row or column all lie in that box.
+func (b *scoreBoard) claiming() bool {
+	size := b.shape.Size
+	for l, line := range b.tables.units[:2*size] {
+		for i := 0; i < size; i++ {
+			box := -1
+			for _, pos := range line {
+				if b.candidates[pos]&(1<<i) == 0 {
+					continue
+				}
+				switch cell := b.tables.cellUnits[pos][2]; {
+				case box == -1:
+					box = cell
+				case box != cell:
+					box = -2
+				}
+			}
+			onLine := func(pos string) bool { return b.tables.cellUnits[pos][l/size] == l }
+			if box >= 0 && b.remove(b.tables.units[box], 1<<i, onLine) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nakedSubset removes the digits of n cells of a unit from the rest of the unit, when those cells hold only n
+// digits between them.
+func (b *scoreBoard) nakedSubset(n int) bool {
+	for _, unit := range b.tables.units {
+		var cells []string
+		var sets []CandidateSet
+		for _, pos := range unit {
+			if set, ok := b.candidates[pos]; ok && set.Count() <= n {
+				cells, sets = append(cells, pos), append(sets, set)
+			}
+		}
+		found := eachSubset(sets, n, func(chosen []int, digits CandidateSet) bool {
+			inSubset := func(pos string) bool {
+				for _, c := range chosen {
+					if cells[c] == pos {
+						return true
+					}
+				}
+				return false
+			}
+			return b.remove(unit, digits, inSubset)
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenSubset removes the other candidates of n cells of a unit, when n digits can only go in those cells.
+func (b *scoreBoard) hiddenSubset(n int) bool {
+	for _, unit := range b.tables.units {
+		var digits []int
+		var places []CandidateSet // Cells of each digit, as bitmasks of their indexes in the unit
+		for i := 0; i < b.shape.Size; i++ {
+			if cells := b.where(unit, i); cells.Count() >= 2 && cells.Count() <= n {
+				digits, places = append(digits, i), append(places, cells)
+			}
+		}
+		found := eachSubset(places, n, func(chosen []int, cells CandidateSet) bool {
+			var keep CandidateSet
+			for _, c := range chosen {
+				keep |= 1 << digits[c]
+			}
+			removed := false
+			for j, pos := range unit {
+				if old := b.candidates[pos]; cells&(1<<j) != 0 && old&^keep != 0 {
+					b.candidates[pos], removed = old&keep, true
+				}
+			}
+			return removed
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// fish removes a digit from n columns when its candidates in n rows all lie in those columns (an X-Wing for 2, a
+// Swordfish for 3), and the same with rows and columns swapped.
+func (b *scoreBoard) fish(n int) bool {
+	size := b.shape.Size
+	for i := 0; i < size; i++ {
+		for _, base := range []int{0, size} { // Rows as the base lines, then columns
+			var lines []int
+			var covers []CandidateSet // Cross lines of the candidates of each base line, as bitmasks
+			for line := base; line < base+size; line++ {
+				if cells := b.where(b.tables.units[line], i); cells.Count() >= 2 && cells.Count() <= n {
+					lines, covers = append(lines, line), append(covers, cells)
+				}
+			}
+			cover := size - base // Index of the first cross line among the units
+			found := eachSubset(covers, n, func(chosen []int, crosses CandidateSet) bool {
+				inBase := func(pos string) bool {
+					for _, c := range chosen {
+						if b.tables.cellUnits[pos][base/size] == lines[c] {
+							return true
+						}
+					}
+					return false
+				}
+				removed := false
+				for j := 0; j < size; j++ {
+					if crosses&(1<<j) != 0 && b.remove(b.tables.units[cover+j], 1<<i, inBase) {
+						removed = true
+					}
+				}
+				return removed
+			})
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// eachSubset calls visit with every choice of n of the sets whose union has exactly n elements, along with the
+// union, until visit returns true. It reports whether visit did.
+func eachSubset(sets []CandidateSet, n int, visit func(chosen []int, union CandidateSet) bool) bool {
+	chosen := make([]int, 0, n)
+	var choose func(start int, union CandidateSet) bool
+	choose = func(start int, union CandidateSet) bool {
+		if union.Count() > n {
+			return false
+		}
+		if len(chosen) == n {
+			return union.Count() == n && visit(chosen, union)
+		}
+		for i := start; i < len(sets); i++ {
+			chosen = append(chosen, i)
+			if choose(i+1, union|sets[i]) {
+				return true
+			}
+			chosen = chosen[:len(chosen)-1]
+		}
+		return false
+	}
+	return choose(0, 0)
+}
@@ -0,0 +1,72 @@
+package sudokux
+
+import "testing"
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		puzzle string
+		want   float64 // Score, or the least score for puzzles the techniques get stuck on
+	}{
+		{"hidden singles in boxes", easyPuzzle, 1.2},
+		{"hidden singles in lines", "..8.........731.....42..7...9.5.23.....4....65....847.76......9.....46.3..5......", 1.5},
+		{"pointing", hardPuzzle, 2.6},
+		{"stuck", "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..", stuckWeight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid, err := GridFromString(tt.puzzle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := score(grid)
+			if tt.want == stuckWeight && got < tt.want || tt.want != stuckWeight && got != tt.want {
+				t.Errorf("score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestScoreTechniques checks on generated puzzles that no technique ever removes the digit of the solution from a
+// cell, or places another one.
+func TestScoreTechniques(t *testing.T) {
+	used := make(map[string]bool)
+	for seed := int64(1); seed <= 60; seed++ {
+		puzzle, solution, err := Generate(GenerateOptions{Seed: seed, Difficulty: DifficultyHard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := &scoreBoard{shape: Classic, tables: Classic.tables(), grid: make(map[string]rune), candidates: make(map[string]CandidateSet)}
+		copyGrid(puzzle, b.grid)
+		for pos, digit := range puzzle {
+			if digit == '.' {
+				b.candidates[pos] = Candidates(puzzle, pos)
+			}
+		}
+		for progress := true; progress; {
+			progress = false
+			last := ""
+			for _, technique := range scoreTechniques {
+				if progress = technique.apply(b); progress {
+					used[technique.name], last = true, technique.name
+					break
+				}
+			}
+			for pos, set := range b.candidates {
+				if !set.Has(solution[pos]) {
+					t.Fatalf("seed %d: %s removed the %c of %s", seed, last, solution[pos], pos)
+				}
+			}
+			for pos, digit := range b.grid {
+				if digit != '.' && digit != solution[pos] {
+					t.Fatalf("seed %d: %s placed %c at %s instead of %c", seed, last, digit, pos, solution[pos])
+				}
+			}
+		}
+	}
+	for _, technique := range scoreTechniques {
+		if !used[technique.name] {
+			t.Logf("%s was never needed", technique.name)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+/*
+This file contains the state of the backtracking search as an explicit, serializable struct. Keeping the whole
+search in a `SearchState` value (rather than in the call stack of a recursive function) allows a long-running
+search to be paused, saved to disk or sent to another process, and resumed later exactly where it stopped.
+
+The state consists of:
+1. **The current assignments**: the grid as filled in so far, including the original clues.
+2. **The candidate sets**: the digits still allowed in each empty cell, kept up to date as digits are placed and removed.
+3. **The decision stack**: one frame per guessed cell, recording which digits are still left to try there and
+   which candidates were removed because of the current guess (so they can be restored when backtracking).
+
+The search is deterministic: cells are always scanned in row-major order (A1, A2, ..., I9 on a classic grid), ties between cells
+are broken in favor of the first one in that order, and digits are tried in ascending order. Running the same
+search twice therefore visits the same nodes in the same order, which keeps traces, statistics, and golden
+outputs stable. When a non-zero `Seed` is set, the digit order of each cell is shuffled instead, still
+reproducibly: the shuffle only depends on the seed and the number of nodes visited so far, so a resumed
+search continues exactly as an uninterrupted one would.
+
+For visualization, the search can report every placement, backtrack, and solution to an `OnStep` callback, and
+can be slowed down to human speed with `Delay` or driven one event at a time through a `StepGate` channel. For
+problem reports, a `Logger` receives debug logs of the propagation passes that prune candidates, of every solution
+found, and of the statistics of the search once it is done. Tools that need every elimination as well set an
+`Observer` (see Observer.go).
+
+The rules of the puzzle come from a list of constraints (see Constraint.go). After each placement only the
+peers of the placed cell are re-checked, then the constraints' elimination hooks prune further candidates.
+
+Functions:
+- **`NewSearchState`**: Creates the state for a grid under the classic rules, computing the starting candidates.
+- **`NewConstrainedSearchState`**: Same as `NewSearchState`, for an explicit list of constraints.
+- **`Run`**: Advances the search until it finishes or a node budget is used up.
+- **`Next`**: Advances the search just far enough to find one more solution.
+- **`Snapshot`** / **`RestoreSearchState`**: Serialize the state to JSON and back so it can be resumed later.
+- **`step`**: Performs a single step of the search (choosing a cell, trying a digit, or backtracking).
+- **`nextCell`**: Chooses the next cell to fill using the Minimum Remaining Values (MRV) heuristic
+  (or simply the first empty cell, for comparison purposes).
+*/
+
+package sudokux
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// SearchFrame is one decision point of the search: a cell being filled and the digits still left to try there.
+type SearchFrame struct {
+	Pos       string            `json:"pos"`       // Position of the cell being guessed (e.g. "E5")
+	Remaining []rune            `json:"remaining"` // Digits that have not been tried yet in this cell
+	Trail     []CandidateChange `json:"trail"`     // Candidate changes caused by the digit currently placed
+}
+
+// CandidateChange records the candidates a cell had before they were narrowed, so they can be restored.
+type CandidateChange struct {
+	Pos string `json:"pos"` // Position of the cell
+	Old []rune `json:"old"` // Candidates of the cell before the change
+}
+
+// SearchState holds everything needed to continue a backtracking search.
+//
+// The constraints and the callbacks are not serialized: after RestoreSearchState, set Constraints again if the
+// search was not using the classic rules, and OnStep or Observer if it had them.
+type SearchState struct {
+	Grid        map[string]rune   `json:"grid"`       // Current assignments, including the original clues
+	Candidates  map[string][]rune `json:"candidates"` // Digits still allowed in each empty cell
+	Stack       []SearchFrame     `json:"stack"`      // Decision points from the root of the search to the current node
+	Expand      bool              `json:"expand"`     // Whether the next step must choose a new cell (after a placement)
+	Limit       int               `json:"limit"`      // Stop after this many solutions (0 means enumerate all of them)
+	Solutions   []map[string]rune `json:"solutions"`  // Solutions found so far
+	Nodes       int               `json:"nodes"`      // Number of placements tried so far
+	Done        bool              `json:"done"`       // Whether the search has finished
+	Shape       Shape             `json:"shape"`      // Dimensions of the board
+	Heuristic   string            `json:"heuristic"`  // How the next cell is chosen (HeuristicMRV when empty)
+	Seed        int64             `json:"seed"`       // Non-zero to try digits in a shuffled (but reproducible) order
+	Constraints []Constraint      `json:"-"`          // Rules of the puzzle (nil means the classic rules)
+	OnStep      func(StepEvent)   `json:"-"`          // Called after every placement, backtrack, and solution
+	Observer    Observer          `json:"-"`          // If set, notified of every placement, elimination, and backtrack
+	Delay       time.Duration     `json:"-"`          // Pause after every event, to animate the search at human speed
+	StepGate    <-chan struct{}   `json:"-"`          // If set, wait for a value after every event (close it to run freely)
+	Logger      *slog.Logger      `json:"-"`          // If set, receives debug logs of the propagation and of the search statistics
+
+	peers      map[string][]string // Cache of the combined peers of each cell across all constraints
+	cells      []string            // Cache of the board's positions in row-major order
+	view       GridSnapshot        // Cells of the grid returned by View, kept up to date by the search once View is called
+	viewShared bool                // Whether a snapshot returned by View holds view.cells, which are then copied before a change
+}
+
+// StepEvent describes one visible event of the search.
+type StepEvent struct {
+	Kind  string // StepPlace, StepBacktrack, or StepSolution
+	Pos   string // Cell concerned (empty for StepSolution)
+	Digit rune   // Digit placed or removed (0 for StepSolution)
+	Depth int    // Number of guesses currently on the stack
+	Nodes int    // Number of placements tried so far
+}
+
+// Kinds of search events.
+const (
+	StepPlace     = "place"     // A digit was placed in a cell
+	StepBacktrack = "backtrack" // A digit was removed from a cell
+	StepSolution  = "solution"  // The grid is full: a solution was found
+)
+
+// Heuristics for choosing the next cell to fill.
+const (
+	HeuristicMRV   = "mrv"   // The empty cell with the fewest candidates (Minimum Remaining Values)
+	HeuristicFirst = "first" // The first empty cell in row-major order
+)
+
+// NewSearchState creates the search state for grid under the classic rules. The search stops once limit
+// solutions are found (0 means no limit). The grid is copied, so the caller's map is never modified by the search.
+func NewSearchState(grid map[string]rune, limit int) *SearchState {
+	return NewConstrainedSearchState(grid, limit, nil)
+}
+
+// NewConstrainedSearchState creates the search state for grid under the given constraints
+// (nil means the classic rules).
+func NewConstrainedSearchState(grid map[string]rune, limit int, constraints []Constraint) *SearchState {
+	state := &SearchState{
+		Grid:        make(map[string]rune),
+		Candidates:  make(map[string][]rune),
+		Expand:      true, // The search starts by choosing its first cell
+		Limit:       limit,
+		Shape:       ShapeOf(grid),
+		Constraints: constraints,
+	}
+	copyGrid(grid, state.Grid) // Work on a private copy of the grid
+	for pos, val := range state.Grid {
+		if val == '.' { // Only empty cells have candidates
+			state.Candidates[pos] = state.allowedDigits(pos, state.Shape.Digits())
+		}
+	}
+	if !state.propagate(nil) { // Eliminations at the root are permanent, so no trail is needed
+		state.Done = true // The constraints can't be satisfied at all
+	}
+	return state
+}
+
+// Run advances the search until it is done or maxNodes more placements have been tried (0 means no budget).
+// It returns true if the search has finished. A search that stopped on its budget can be resumed by calling Run again.
+func (state *SearchState) Run(maxNodes int) bool {
+	startNodes := state.Nodes // Remember where this run started to enforce the node budget
+	for !state.Done {
+		if maxNodes > 0 && state.Nodes-startNodes >= maxNodes { // If the budget for this run is used up
+			return false // Pause the search; it can be resumed later
+		}
+		state.step()
+	}
+	return true
+}
+
+// Next continues the search until one more solution is found and returns it, or returns false once the
+// search is done. Solutions returned by Next are not kept in Solutions, so enumerating a huge number of
+// solutions this way uses constant memory.
+func (state *SearchState) Next() (map[string]rune, bool) {
+	found := len(state.Solutions) // Solutions already recorded before this call
+	for !state.Done && len(state.Solutions) == found {
+		state.step()
+	}
+	if len(state.Solutions) == found { // The search ended without finding another solution
+		return nil, false
+	}
+	solution := state.Solutions[found]
+	state.Solutions = state.Solutions[:found] // Hand the solution over instead of keeping it
+	return solution, true
+}
+
+// Snapshot serializes the search state to JSON so it can be checkpointed or moved to another process.
+func (state *SearchState) Snapshot() ([]byte, error) {
+	return json.Marshal(state)
+}
+
+// RestoreSearchState rebuilds a search state from a snapshot produced by Snapshot.
+func RestoreSearchState(data []byte) (*SearchState, error) {
+	state := &SearchState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid search state: %v", err)
+	}
+	if state.Grid == nil || state.Candidates == nil { // A snapshot without a grid cannot be resumed
+		return nil, fmt.Errorf("invalid search state: missing grid or candidates")
+	}
+	return state, nil
+}
+
+// step performs a single step of the search.
+func (state *SearchState) step() {
+	if state.Expand { // A digit was just placed (or the search is starting), so look for the next cell to fill
+		state.Expand = false
+		pos := state.nextCell() // Find the next cell with the MRV heuristic
+		if pos == "" {          // If no empty cell is found, the grid is fully filled
+			state.recordSolution()
+			return
+		}
+		if len(state.Candidates[pos]) == 0 { // If there are no valid options for this cell
+			return // Dead end: the next step backtracks
+		}
+		remaining := append([]rune(nil), state.Candidates[pos]...) // Copy the candidates as the digits to try
+		if state.Seed != 0 {                                       // Randomization was requested
+			shuffleDigits(remaining, uint64(state.Seed)^uint64(state.Nodes)*0x9E3779B97F4A7C15)
+		}
+		state.Stack = append(state.Stack, SearchFrame{Pos: pos, Remaining: remaining})
+		return
+	}
+
+	if len(state.Stack) == 0 { // Every decision has been exhausted
+		state.finish()
+		return
+	}
+	top := &state.Stack[len(state.Stack)-1]     // The most recent decision point
+	if num := state.Grid[top.Pos]; num != '.' { // Undo the digit tried previously in this cell (backtrack)
+		state.unplace(top)
+		if state.Observer != nil {
+			state.Observer.OnBacktrack(state.event(StepBacktrack, top.Pos, num))
+		}
+		state.emit(StepBacktrack, top.Pos, num)
+	}
+	if len(top.Remaining) == 0 { // If every digit has been tried in this cell
+		state.Stack = state.Stack[:len(state.Stack)-1] // Return to the previous decision point
+		return
+	}
+	num := top.Remaining[0] // Try the next digit
+	top.Remaining = top.Remaining[1:]
+	state.Nodes++
+	if state.Observer != nil { // Before the placement, so that the eliminations it causes come after it
+		state.Observer.OnPlace(state.event(StepPlace, top.Pos, num))
+	}
+	ok := state.place(top, num)
+	state.emit(StepPlace, top.Pos, num)
+	if ok { // Continue from the new placement unless it immediately contradicts a constraint
+		state.Expand = true
+	}
+}
+
+// emit reports an event to OnStep, then waits as long as Delay and StepGate require.
+func (state *SearchState) emit(kind, pos string, digit rune) {
+	if state.OnStep != nil {
+		state.OnStep(state.event(kind, pos, digit))
+	}
+	if state.Delay > 0 {
+		time.Sleep(state.Delay)
+	}
+	if state.StepGate != nil {
+		if _, ok := <-state.StepGate; !ok { // A closed gate releases the search for good
+			state.StepGate = nil
+		}
+	}
+}
+
+// recordSolution stores a copy of the (full) grid as a solution and stops the search once the limit is reached.
+func (state *SearchState) recordSolution() {
+	solution := make(map[string]rune)
+	copyGrid(state.Grid, solution)
+	state.Solutions = append(state.Solutions, solution)
+	state.emit(StepSolution, "", 0)
+	if state.Logger != nil {
+		state.Logger.Debug("solution found", "solution", len(state.Solutions), "nodes", state.Nodes, "depth", len(state.Stack))
+	}
+	if state.Limit > 0 && len(state.Solutions) >= state.Limit { // Enough solutions have been found
+		state.finish()
+	}
+}
+
+// finish ends the search and logs its statistics.
+func (state *SearchState) finish() {
+	state.Done = true
+	if state.Logger != nil {
+		state.Logger.Debug("search finished", "nodes", state.Nodes, "solutions", len(state.Solutions), "limit", state.Limit, "heuristic", state.Heuristic, "seed", state.Seed)
+	}
+}
+
+// nextCell returns the next empty cell to fill according to the heuristic, or "" if the grid is full.
+// With the default MRV heuristic this is the empty cell with the fewest candidates.
+func (state *SearchState) nextCell() string {
+	firstOnly := state.Heuristic == HeuristicFirst // Take the first empty cell instead of comparing options
+	if state.cells == nil {
+		state.cells = state.Shape.Cells()
+	}
+	minOptions := state.Shape.Size + 1 // Start with a value greater than the max possible options
+	bestCell := ""                     // Store the position of the best cell (least options)
+	for _, pos := range state.cells {  // Loop through all cells in row-major order
+		if state.Grid[pos] != '.' { // Skip filled cells
+			continue
+		}
+		options := len(state.Candidates[pos])
+		if firstOnly {
+			return pos
+		}
+		if options < minOptions { // If this cell has fewer options than the current minimum
+			minOptions = options
+			bestCell = pos
+			if options <= 1 { // A cell can't do better than a single option (or none), so stop looking
+				return bestCell
+			}
+		}
+	}
+	return bestCell
+}
+
+// place puts num in the frame's cell, narrows the candidates of its peers and runs the elimination hooks.
+// Every candidate change is recorded in the frame's trail. It returns false if a constraint can no longer be satisfied.
+func (state *SearchState) place(frame *SearchFrame, num rune) bool {
+	pos := frame.Pos
+	state.Grid[pos] = num
+	state.setView(pos, num)
+	state.setCandidates(frame, pos, nil) // Filled cells have no candidates
+	for _, peer := range state.peersOf(pos) {
+		if state.Grid[peer] == '.' { // Re-check the remaining candidates of every empty peer
+			state.setCandidates(frame, peer, state.allowedDigits(peer, state.Candidates[peer]))
+		}
+	}
+	return state.propagate(frame)
+}
+
+// unplace empties the frame's cell and restores every candidate changed since the digit was placed.
+func (state *SearchState) unplace(frame *SearchFrame) {
+	state.Grid[frame.Pos] = '.'
+	state.setView(frame.Pos, '.')
+	for i := len(frame.Trail) - 1; i >= 0; i-- { // Undo the changes in reverse order
+		change := frame.Trail[i]
+		state.Candidates[change.Pos] = change.Old
+	}
+	frame.Trail = nil
+}
+
+// propagate runs the elimination hooks of every constraint until none of them removes anything more.
+// Changes are recorded in frame's trail (frame is nil at the root of the search, where nothing is ever undone).
+func (state *SearchState) propagate(frame *SearchFrame) bool {
+	passes, eliminated := 0, 0
+	for changed := true; changed; {
+		changed = false
+		passes++
+		for _, constraint := range state.constraints() {
+			eliminations, ok := constraint.Eliminate(state.Grid, state.Candidates)
+			if !ok { // The constraint can't be satisfied anymore
+				state.logPropagation(passes, eliminated, false)
+				return false
+			}
+			for _, elimination := range eliminations {
+				old := state.Candidates[elimination.Pos]
+				if updated := removeRune(old, elimination.Digit); len(updated) != len(old) { // Only record real changes
+					state.setCandidates(frame, elimination.Pos, updated)
+					changed = true
+					eliminated++
+				}
+			}
+		}
+	}
+	state.logPropagation(passes, eliminated, true)
+	return true
+}
+
+// logPropagation logs a propagation that removed candidates or found a contradiction; the many that do neither
+// would only drown the others.
+func (state *SearchState) logPropagation(passes, eliminated int, consistent bool) {
+	if state.Logger == nil || consistent && eliminated == 0 {
+		return
+	}
+	state.Logger.Debug("propagation", "depth", len(state.Stack), "nodes", state.Nodes, "passes", passes, "eliminated", eliminated, "consistent", consistent)
+}
+
+// setCandidates replaces the candidates of pos, recording the old ones in the frame's trail if they changed.
+func (state *SearchState) setCandidates(frame *SearchFrame, pos string, candidates []rune) {
+	old, ok := state.Candidates[pos]
+	if ok && len(old) == len(candidates) { // Candidates only ever shrink, so the same length means no change
+		return
+	}
+	if frame != nil {
+		frame.Trail = append(frame.Trail, CandidateChange{Pos: pos, Old: old})
+	}
+	if candidates == nil && state.Grid[pos] != '.' { // Filled cells are removed from the candidate map entirely
+		delete(state.Candidates, pos)
+		return
+	}
+	if state.Observer != nil {
+		state.observeEliminations(pos, old, candidates)
+	}
+	state.Candidates[pos] = candidates
+}
+
+// allowedDigits returns the digits among options that every constraint allows at pos.
+func (state *SearchState) allowedDigits(pos string, options []rune) []rune {
+	allowed := make([]rune, 0, len(options))
+	for _, num := range options {
+		ok := true
+		for _, constraint := range state.constraints() {
+			if !constraint.Allows(state.Grid, pos, num) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			allowed = append(allowed, num)
+		}
+	}
+	return allowed
+}
+
+// constraints returns the rules of the search, falling back to the classic rules for the board's shape.
+func (state *SearchState) constraints() []Constraint {
+	if state.Constraints == nil {
+		state.Constraints = ClassicConstraintsFor(state.Shape)
+	}
+	return state.Constraints
+}
+
+// peersOf returns the cells affected by pos under any of the constraints, computed once per cell.
+func (state *SearchState) peersOf(pos string) []string {
+	if state.peers == nil {
+		state.peers = make(map[string][]string)
+	}
+	if peers, ok := state.peers[pos]; ok {
+		return peers
+	}
+	seen := map[string]bool{pos: true}
+	var peers []string
+	for _, constraint := range state.constraints() {
+		for _, peer := range constraint.Peers(pos) {
+			if !seen[peer] {
+				seen[peer] = true
+				peers = append(peers, peer)
+			}
+		}
+	}
+	state.peers[pos] = peers
+	return peers
+}
+
+// removeRune returns the digits with num removed, without modifying the original slice.
+func removeRune(digits []rune, num rune) []rune {
+	for i, d := range digits {
+		if d == num {
+			result := make([]rune, 0, len(digits)-1)
+			result = append(result, digits[:i]...)
+			return append(result, digits[i+1:]...)
+		}
+	}
+	return digits // The digit was not a candidate, nothing to remove
+}
+
+// shuffleDigits shuffles digits in place (Fisher-Yates) using a small splitmix64 generator started from seed,
+// so the same seed always produces the same order.
+func shuffleDigits(digits []rune, seed uint64) {
+	next := func() uint64 { // splitmix64: cheap, with no allocation and good mixing
+		seed += 0x9E3779B97F4A7C15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		return z ^ (z >> 31)
+	}
+	for i := len(digits) - 1; i > 0; i-- {
+		j := int(next() % uint64(i+1))
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+}
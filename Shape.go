@@ -0,0 +1,208 @@
+/*
+This file describes the dimensions of a board, so that the parser, the solver, and the printer are not tied to
+the classic 9x9 grid. A `Shape` gives the number of rows, columns, and digits of the board and the size of its
+boxes (the 3x3 subgrids of a classic grid, or 4x4 boxes on a 16x16 hexadoku board). Boxes don't have to be
+square: a 6x6 board has boxes of 2 rows by 3 columns, and a 12x12 board boxes of 3 rows by 4 columns. Every board
+from 4x4 to 25x25 whose size can be split into boxes is supported.
+
+Grids don't record their boxes, so functions that take only a grid use the default boxes for its size (the most
+square ones, with no more rows than columns). Other boxes, such as 3x2 boxes on a 6x6 board, are chosen with
+`NewShape` and passed explicitly, through `ParseRowsShape` and `ClassicConstraintsFor`.
+
+Positions keep the familiar format on every board: a row letter followed by a column number, so a 9x9 grid goes
+from "A1" to "I9" and a 16x16 grid from "A1" to "P16". The digits of a board are the first `Size` symbols of
+"123456789ABCDEFGHIJKLMNOP", so a 16x16 board uses 1-9 followed by A-G, and a 25x25 board 1-9 followed by A-P.
+
+Functions:
+- **`NewShape`**: Returns the shape of a board with boxes of the given dimensions.
+- **`ShapeForSize`**: Returns the default shape of a board with the given number of rows.
+- **`ShapeOf`**: Works out the shape of a grid from its number of cells.
+- **`supportedShapes`**: Lists every supported shape, from the smallest to the largest.
+- **`Cells`**, **`Units`**, **`Peers`**: List the cells, the units (rows, columns, and boxes), and the peers of a cell,
+  copied from the precomputed tables of Tables.go.
+- **`Pos`** / **`ParsePos`**: Convert between (row, column) indexes and position strings.
+- **`boxCells`**: Lists the cells of a box, for the iterators of Grid.go.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// Shape describes the dimensions of a board.
+type Shape struct {
+	Size    int `json:"size"`     // Number of rows, columns, and digits (9 for a classic grid)
+	BoxRows int `json:"box_rows"` // Height of a box (3 for a classic grid)
+	BoxCols int `json:"box_cols"` // Width of a box (3 for a classic grid)
+}
+
+// Classic is the shape of the standard 9x9 grid with 3x3 subgrids.
+var Classic = Shape{Size: 9, BoxRows: 3, BoxCols: 3}
+
+// Hexadoku is the shape of a 16x16 grid with 4x4 boxes.
+var Hexadoku = Shape{Size: 16, BoxRows: 4, BoxCols: 4}
+
+// symbols lists the digits used on boards, in order. A board of size n uses the first n of them.
+const symbols = "123456789ABCDEFGHIJKLMNOP"
+
+// NewShape returns the shape of a board with boxes of boxRows rows by boxCols columns. The board is as wide as a
+// box has cells, so 2x3 boxes make a 6x6 board.
+func NewShape(boxRows, boxCols int) (Shape, error) {
+	if boxRows < 2 || boxCols < 2 {
+		return Shape{}, fmt.Errorf("boxes must be at least 2x2, got %dx%d", boxRows, boxCols)
+	}
+	if boxRows*boxCols > len(symbols) {
+		return Shape{}, fmt.Errorf("%dx%d boxes make a board larger than %dx%d", boxRows, boxCols, len(symbols), len(symbols))
+	}
+	return Shape{Size: boxRows * boxCols, BoxRows: boxRows, BoxCols: boxCols}, nil
+}
+
+// supportedShapes returns the default shape of every supported board size, from the smallest board to the largest.
+func supportedShapes() []Shape {
+	var shapes []Shape
+	for size := 4; size <= len(symbols); size++ {
+		if shape, err := ShapeForSize(size); err == nil {
+			shapes = append(shapes, shape)
+		}
+	}
+	return shapes
+}
+
+// ShapeForSize returns the default shape of a board with size rows: the most square boxes that split the board
+// evenly, with no more rows than columns (3x3 on a 9x9 board, 2x3 on a 6x6 board, 3x4 on a 12x12 board).
+func ShapeForSize(size int) (Shape, error) {
+	if size <= len(symbols) {
+		boxRows := 1
+		for boxRows*boxRows <= size { // Start from the square root of the size, rounded down
+			boxRows++
+		}
+		for boxRows--; boxRows >= 2; boxRows-- {
+			if size%boxRows == 0 {
+				return NewShape(boxRows, size/boxRows)
+			}
+		}
+	}
+	return Shape{}, fmt.Errorf("unsupported board size %d (supported sizes go from 4 to %d and can't be prime)", size, len(symbols))
+}
+
+// ShapeOf returns the shape of grid, worked out from its number of cells. Grids that don't match a supported
+// board size are treated as classic 9x9 grids.
+func ShapeOf(grid map[string]rune) Shape {
+	for _, shape := range defaultShapes {
+		if len(grid) == shape.Size*shape.Size {
+			return shape
+		}
+	}
+	return Classic
+}
+
+// Digits returns the digits of the board in ascending order.
+func (s Shape) Digits() []rune {
+	return []rune(symbols[:s.Size])
+}
+
+// IsDigit reports whether r is one of the digits of the board.
+func (s Shape) IsDigit(r rune) bool {
+	return s.DigitIndex(r) >= 0
+}
+
+// DigitIndex returns the position of r among the digits of the board (0 for '1'), or -1 if it isn't one of them.
+func (s Shape) DigitIndex(r rune) int {
+	for i, symbol := range symbols[:s.Size] {
+		if symbol == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Pos returns the position string of the cell at the given row and column indexes (starting at 0).
+func (s Shape) Pos(row, col int) string {
+	return string(rune('A'+row)) + strconv.Itoa(col+1)
+}
+
+// ParsePos returns the row and column indexes (starting at 0) of a position string such as "A1" or "P16".
+func (s Shape) ParsePos(pos string) (int, int, bool) {
+	if len(pos) < 2 || len(pos) > 3 || pos[1] == '0' { // A letter and one or two digits, without a leading zero
+		return 0, 0, false
+	}
+	row := int(pos[0]) - 'A' // The row is a single ASCII letter, so any other byte (including the start of a multi-byte character) is out of range
+	col := 0
+	for i := 1; i < len(pos); i++ {
+		if pos[i] < '0' || pos[i] > '9' {
+			return 0, 0, false
+		}
+		col = col*10 + int(pos[i]-'0')
+	}
+	if row < 0 || row >= s.Size || col < 1 || col > s.Size {
+		return 0, 0, false
+	}
+	return row, col - 1, true
+}
+
+// check returns an error unless the shape is one NewShape returns, so that a Shape built by hand can't make the
+// parser index past the digits or divide by zero.
+func (s Shape) check() error {
+	shape, err := NewShape(s.BoxRows, s.BoxCols)
+	if err != nil {
+		return err
+	}
+	if shape != s {
+		return fmt.Errorf("a board with %dx%d boxes has %d rows, not %d", s.BoxRows, s.BoxCols, shape.Size, s.Size)
+	}
+	return nil
+}
+
+// Cells returns every position of the board in row-major order.
+func (s Shape) Cells() []string {
+	return slices.Clone(s.tables().cells)
+}
+
+// BoxOf returns the index of the box containing the cell at row and col, numbering boxes in row-major order.
+func (s Shape) BoxOf(row, col int) int {
+	return row/s.BoxRows*(s.Size/s.BoxCols) + col/s.BoxCols
+}
+
+// Units returns every unit of the board: all the rows, then all the columns, then all the boxes.
+func (s Shape) Units() [][]string {
+	units := make([][]string, 0, 3*s.Size)
+	for _, unit := range s.tables().units {
+		units = append(units, slices.Clone(unit))
+	}
+	return units
+}
+
+// UnitName describes the unit at index u of Units(), such as "row A", "column 5", or "subgrid D4"
+// (boxes are named after their top-left cell).
+func (s Shape) UnitName(u int) string {
+	switch {
+	case u < s.Size:
+		return fmt.Sprintf("row %c", 'A'+u)
+	case u < 2*s.Size:
+		return fmt.Sprintf("column %d", u-s.Size+1)
+	default:
+		corner := s.boxCells(u - 2*s.Size)[0]
+		return "subgrid " + corner.String()
+	}
+}
+
+// boxCells returns the cells of box b (numbered in row-major order, like BoxOf) in row-major order.
+func (s Shape) boxCells(b int) []Coord {
+	perRow := s.Size / s.BoxCols                        // Number of boxes side by side
+	top, left := b/perRow*s.BoxRows, b%perRow*s.BoxCols // Top-left cell of the box
+	cells := make([]Coord, 0, s.Size)
+	for row := top; row < top+s.BoxRows; row++ {
+		for col := left; col < left+s.BoxCols; col++ {
+			cells = append(cells, Coord{Row: row, Col: col})
+		}
+	}
+	return cells
+}
+
+// Peers returns every position sharing a row, column, or box with pos (excluding pos itself), in row-major order.
+func (s Shape) Peers(pos string) []string {
+	return slices.Clone(s.tables().peers[pos])
+}
@@ -0,0 +1,102 @@
+/*
+This file defines `GridSnapshot`, a read-only copy of a grid that can be handed to other goroutines (a UI drawing
+the board, a server answering a request) while the solver keeps changing its own working grid.
+
+A snapshot keeps the cells in a flat slice in row-major order rather than in a map, so taking one is a single small
+allocation, and since nothing ever writes to that slice, snapshots and their copies share it freely. A running
+search goes further: once `SearchState.View` has been called, the search keeps its own slice of the cells up to
+date as it places and removes digits, and View hands that slice out without copying it. The slice is copied on
+write: the next change after View copies it before writing, so the snapshots handed out never change, and a search
+nobody views, or views between every change, copies the board at most once per View.
+
+Functions:
+- **`Snapshot`**: Takes a snapshot of a grid.
+- **`View`**: Returns a snapshot of the current grid of a search, sharing its cells until the search changes them.
+- **`Get`** / **`At`**: Read a cell by (row, column) index or by position string.
+- **`Grid`**: Copies the snapshot back into a grid that can be changed.
+*/
+
+package sudokux
+
+import "slices"
+
+// GridSnapshot is a read-only copy of a grid. The zero value is an empty snapshot with no cells.
+type GridSnapshot struct {
+	shape Shape
+	cells []rune // Every cell in row-major order, never modified once the snapshot is taken
+}
+
+// Snapshot returns a read-only copy of the grid, which later changes to the grid don't affect.
+func (g Grid) Snapshot() GridSnapshot {
+	shape := g.Shape()
+	cells := make([]rune, 0, shape.Size*shape.Size)
+	for _, pos := range shape.tables().cells {
+		cells = append(cells, g[pos])
+	}
+	return GridSnapshot{shape: shape, cells: cells}
+}
+
+// View returns a read-only snapshot of the current grid of the search. The first call copies the grid; later calls
+// return the cells the search keeps up to date, copying them only when the search changed them while a snapshot
+// held them, so a UI can call View on every event or every frame cheaply. Call it from the goroutine running the
+// search (from OnStep, for example) and hand the snapshot to others. Changes made to Grid directly, rather than by
+// the search, are not noticed.
+func (state *SearchState) View() GridSnapshot {
+	if state.view.cells == nil {
+		state.view = Grid(state.Grid).Snapshot()
+	}
+	state.viewShared = true
+	return state.view
+}
+
+// setView records that the search put digit at pos in the cells of View, if View was ever called. The cells are
+// copied first if a snapshot holds them.
+func (state *SearchState) setView(pos string, digit rune) {
+	if state.view.cells == nil {
+		return
+	}
+	if state.viewShared {
+		state.view.cells = slices.Clone(state.view.cells)
+		state.viewShared = false
+	}
+	size := state.view.shape.Size
+	units := state.view.shape.tables().cellUnits[pos] // Indexes of the row, then of the column after the rows
+	state.view.cells[units[0]*size+units[1]-size] = digit
+}
+
+// Shape returns the shape of the grid the snapshot was taken from.
+func (s GridSnapshot) Shape() Shape {
+	return s.shape
+}
+
+// Get returns the digit at the given row and column (counted from 0), '.' if the cell is empty, or 0 if it is
+// outside the board.
+func (s GridSnapshot) Get(row, col int) rune {
+	if row < 0 || row >= s.shape.Size || col < 0 || col >= s.shape.Size {
+		return 0
+	}
+	return s.cells[row*s.shape.Size+col]
+}
+
+// At returns the digit at a position such as "A1", '.' if the cell is empty, or 0 if the position is not on the board.
+func (s GridSnapshot) At(pos string) rune {
+	row, col, ok := s.shape.ParsePos(pos)
+	if !ok {
+		return 0
+	}
+	return s.Get(row, col)
+}
+
+// Grid returns a copy of the snapshot as a grid, which can be changed without changing the snapshot.
+func (s GridSnapshot) Grid() Grid {
+	grid := make(Grid, len(s.cells))
+	for i, digit := range s.cells {
+		grid[s.shape.Pos(i/s.shape.Size, i%s.shape.Size)] = digit
+	}
+	return grid
+}
+
+// String returns every cell of the snapshot on one line in row-major order, like Grid.String.
+func (s GridSnapshot) String() string {
+	return string(s.cells)
+}
@@ -0,0 +1,47 @@
+package sudokux
+
+import "testing"
+
+// TestView checks that the snapshots of View follow the search without changing once handed out.
+func TestView(t *testing.T) {
+	grid, err := GridFromString(hardPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := NewSearchState(grid, 2)
+	first := state.View()
+	if got := first.String(); got != hardPuzzle {
+		t.Fatalf("View() = %s, want %s", got, hardPuzzle)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { state.View() }); allocs != 0 {
+		t.Errorf("View() of an unchanged search made %v allocations, want 0", allocs)
+	}
+	for i := 0; i < 50; i++ {
+		state.Run(1)
+		view := state.View()
+		if got, want := view.String(), Grid(state.Grid).Snapshot().String(); got != want {
+			t.Fatalf("View() after %d nodes = %s, want %s", state.Nodes, got, want)
+		}
+	}
+	if got := first.String(); got != hardPuzzle {
+		t.Errorf("the first snapshot changed to %s", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	grid, err := GridFromString(hardPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := Grid(grid).Snapshot()
+	grid["A2"] = '1'
+	if snapshot.At("A2") != '.' || snapshot.Get(0, 0) != '4' || snapshot.Get(9, 0) != 0 || snapshot.At("Z1") != 0 {
+		t.Errorf("Snapshot() = %s, changed by the grid or misread", snapshot)
+	}
+	if got := CanonicalString(snapshot.Grid()); got != hardPuzzle {
+		t.Errorf("Snapshot().Grid() = %s, want %s", got, hardPuzzle)
+	}
+	if allocs := testing.AllocsPerRun(10, func() { Grid(grid).Snapshot() }); allocs != 1 {
+		t.Errorf("Snapshot() made %v allocations, want 1", allocs)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+This file contains `SolveInto`, a solving path for servers that solve puzzles at a high rate and can't afford to
+allocate memory on every request. It solves under the classic rules only, without traces, statistics, or timeouts,
+and writes the solution into a grid the caller provides.
+
+Instead of the maps of `SearchState`, the search works on arrays: one byte per cell, and one bitmask of used digits
+per row, column, and box, so the candidates of a cell are a couple of bitwise operations away (see Candidates.go for
+the bitmask). The arrays live in a scratch value taken from a `sync.Pool` and given back after the solve, and the
+search recurses on the goroutine stack, so once the pool is warm, a solve into a grid that already has every cell
+makes no heap allocations at all. The errors returned are the sentinels of Errors.go themselves, for the same reason.
+
+Functions:
+- **`SolveInto`**: Solves a grid into another one.
+- **`countSolutions`**: Counts the solutions of a grid, up to two, for the uniqueness checks of Generate, which
+  can give up at a deadline.
+- **`arraySearch`**: Counts the solutions of the arrays, up to two, keeping the first one.
+*/
+
+package sudokux
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// arraySolver is the scratch space of SolveInto. Its slices grow to the largest board solved with it.
+type arraySolver struct {
+	size      int
+	cells     []uint8        // Digit index plus one in every cell, in row-major order (0 for an empty cell)
+	solution  []uint8        // Cells of the first solution found
+	rows      []CandidateSet // Digits used in every row
+	cols      []CandidateSet // Digits used in every column
+	boxes     []CandidateSet // Digits used in every box
+	boxOf     []int          // Box of every cell
+	solutions int            // Number of solutions found so far
+	deadline  time.Time      // When the search gives up, or the zero time for never
+	nodes     int            // Nodes visited so far, to look at the clock every deadlineCheck nodes
+	timedOut  bool           // Whether the search gave up at the deadline
+}
+
+// deadlineCheck is how many nodes arraySearch visits between two looks at the clock.
+const deadlineCheck = 4096
+
+// arraySolvers keeps the scratch space of finished solves for the next ones.
+var arraySolvers = sync.Pool{New: func() any { return new(arraySolver) }}
+
+// SolveInto solves src under the classic rules, with the default boxes for its size, and writes the solution into
+// dst if it is unique. It returns ErrInvalidGrid if src has a cell that isn't on the board, holds something other than
+// a digit of the board or '.', or repeats a digit within a unit, and ErrNoSolution or ErrMultipleSolutions when
+// there isn't exactly one solution; dst is left unchanged in those cases. src is never modified, and can be dst.
+//
+// SolveInto makes no heap allocations once it has solved a puzzle of the same size before, as long as dst already
+// has every cell of the board (NewGrid, Clone, or a previous solution); otherwise it adds the missing cells to dst.
+func SolveInto(dst, src Grid) error {
+	s := arraySolvers.Get().(*arraySolver)
+	defer arraySolvers.Put(s)
+	t, err := s.load(src)
+	if err != nil {
+		return err
+	}
+	s.arraySearch()
+	switch s.solutions {
+	case 0:
+		return ErrNoSolution
+	case 1:
+		for i, pos := range t.cells {
+			dst[pos] = rune(symbols[s.solution[i]-1])
+		}
+		return nil
+	}
+	return ErrMultipleSolutions
+}
+
+// countSolutions returns the number of solutions of grid under the classic rules, up to two, or 0 if its clues
+// conflict. It is the fast way to check that a puzzle is unique, which Generate does for every clue it digs out.
+// Unless deadline is the zero time, the search gives up once it has passed, and countSolutions returns false.
+func countSolutions(grid map[string]rune, deadline time.Time) (int, bool) {
+	s := arraySolvers.Get().(*arraySolver)
+	defer arraySolvers.Put(s)
+	if _, err := s.load(grid); err != nil {
+		return 0, true
+	}
+	s.deadline = deadline
+	s.arraySearch()
+	return s.solutions, !s.timedOut
+}
+
+// load fills the scratch space with the clues of grid, and returns the tables of its shape. It returns
+// ErrInvalidGrid if a cell is missing, holds something other than a digit of the board or '.', or repeats a digit.
+func (s *arraySolver) load(grid map[string]rune) (*shapeTables, error) {
+	shape := ShapeOf(grid)
+	t := shape.tables()
+	if len(grid) != len(t.cells) {
+		return nil, ErrInvalidGrid // Only ShapeOf's fallback to a classic grid can get here
+	}
+	s.reset(shape, t)
+	for i, pos := range t.cells {
+		val, ok := grid[pos]
+		if !ok {
+			return nil, ErrInvalidGrid
+		}
+		if val == '.' {
+			continue
+		}
+		digit := shape.DigitIndex(val)
+		if digit < 0 || !s.place(i, digit) {
+			return nil, ErrInvalidGrid
+		}
+	}
+	return t, nil
+}
+
+// reset prepares the scratch space for a board of the given shape, growing it if needed.
+func (s *arraySolver) reset(shape Shape, t *shapeTables) {
+	n := shape.Size
+	s.size, s.solutions = n, 0
+	s.deadline, s.nodes, s.timedOut = time.Time{}, 0, false
+	s.cells = resize(s.cells, n*n)
+	s.solution = resize(s.solution, n*n)
+	s.rows, s.cols, s.boxes = resize(s.rows, n), resize(s.cols, n), resize(s.boxes, n)
+	s.boxOf = resize(s.boxOf, n*n)
+	for i, pos := range t.cells {
+		s.boxOf[i] = t.cellUnits[pos][2] - 2*n
+	}
+}
+
+// resize returns a zeroed slice of length n, reusing the array of slice if it is large enough.
+func resize[T any](slice []T, n int) []T {
+	if cap(slice) < n {
+		return make([]T, n)
+	}
+	slice = slice[:n]
+	clear(slice)
+	return slice
+}
+
+// place puts digit (an index counted from 0) in cell i, and returns false if its row, column, or box already has it.
+func (s *arraySolver) place(i, digit int) bool {
+	bit := CandidateSet(1) << digit
+	row, col, box := i/s.size, i%s.size, s.boxOf[i]
+	if (s.rows[row]|s.cols[col]|s.boxes[box])&bit != 0 {
+		return false
+	}
+	s.cells[i] = uint8(digit + 1)
+	s.rows[row] |= bit
+	s.cols[col] |= bit
+	s.boxes[box] |= bit
+	return true
+}
+
+// unplace empties cell i, which holds digit.
+func (s *arraySolver) unplace(i, digit int) {
+	bit := CandidateSet(1) << digit
+	s.cells[i] = 0
+	s.rows[i/s.size] &^= bit
+	s.cols[i%s.size] &^= bit
+	s.boxes[s.boxOf[i]] &^= bit
+}
+
+// arraySearch fills the empty cells by backtracking, always branching on the empty cell with the fewest candidates.
+// It returns true once two solutions have been found, which is enough to stop, or once the deadline has passed.
+func (s *arraySolver) arraySearch() bool {
+	if !s.deadline.IsZero() {
+		if s.nodes++; s.nodes%deadlineCheck == 0 && time.Now().After(s.deadline) {
+			s.timedOut = true
+		}
+		if s.timedOut {
+			return true
+		}
+	}
+	all := CandidateSet(1)<<s.size - 1
+	best, bestCandidates, bestCount := -1, CandidateSet(0), s.size+1
+	for i, digit := range s.cells {
+		if digit != 0 {
+			continue
+		}
+		candidates := all &^ (s.rows[i/s.size] | s.cols[i%s.size] | s.boxes[s.boxOf[i]])
+		if count := candidates.Count(); count < bestCount {
+			best, bestCandidates, bestCount = i, candidates, count
+			if count == 0 { // A dead end: no need to look further
+				return false
+			}
+		}
+	}
+	if best < 0 { // Every cell is filled: a solution
+		s.solutions++
+		if s.solutions == 1 {
+			copy(s.solution, s.cells)
+		}
+		return s.solutions >= 2
+	}
+	for candidates := bestCandidates; candidates != 0; candidates &= candidates - 1 {
+		digit := bits.TrailingZeros32(uint32(candidates))
+		s.place(best, digit)
+		done := s.arraySearch()
+		s.unplace(best, digit)
+		if done {
+			return true
+		}
+	}
+	return false
+}
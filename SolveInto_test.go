@@ -0,0 +1,55 @@
+package sudokux
+
+import "testing"
+
+// hardPuzzle is a puzzle of top95 that needs a deep search.
+const hardPuzzle = "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"
+
+func TestSolveInto(t *testing.T) {
+	src, err := GridFromString(hardPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := NewGrid(ShapeOf(src))
+	if err := SolveInto(dst, Grid(src)); err != nil {
+		t.Fatal(err)
+	}
+	want := "417369825632158947958724316825437169791586432346912758289643571573291684164875293"
+	if got := CanonicalString(dst); got != want {
+		t.Errorf("SolveInto() = %s, want %s", got, want)
+	}
+}
+
+func TestSolveIntoAllocs(t *testing.T) {
+	src, err := GridFromString(hardPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := NewGrid(ShapeOf(src))
+	if err := SolveInto(dst, Grid(src)); err != nil { // Warms the pool up
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(20, func() {
+		if err := SolveInto(dst, Grid(src)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("SolveInto made %v allocations per run, want 0", allocs)
+	}
+}
+
+func BenchmarkSolveInto(b *testing.B) {
+	src, err := GridFromString(hardPuzzle)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dst := NewGrid(ShapeOf(src))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := SolveInto(dst, Grid(src)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -4,7 +4,9 @@ The grid is represented as a map where each key is a string (representing a posi
 and the value is a rune (the number or an empty cell).
 
 The solution process involves:
-1. **Tracking used numbers** in each row, column, and 3x3 subgrid using maps.
+1. **Tracking candidates**: the digits still allowed in each empty cell are kept up to date as numbers
+   are placed and removed (see `SearchState` in Search.go). The rules deciding which digits are allowed
+   come from a list of constraints, the classic row, column, and subgrid rules by default.
 2. **Minimum Remaining Values (MRV) heuristic**: This is used to select the next cell to fill,
    prioritizing cells with the fewest valid options.
 3. **Backtracking algorithm**: The program tries placing numbers in the empty cells while ensuring
@@ -12,126 +14,105 @@ The solution process involves:
    it backtracks and tries a different number.
 
 Functions:
-- **`SolveSudoku`**: Main function that orchestrates solving the Sudoku puzzle. It keeps track of
-  the numbers used in rows, columns, and subgrids, and calls helper functions to find the next cell
-  to fill and backtrack when necessary. It returns the solved grid if there is exactly one solution,
-  or `false` if there are no solutions or multiple solutions.
-- **`findNextCell`**: Helper function to find the next empty cell to fill, using the MRV heuristic
-  (i.e., the cell with the fewest valid options).
-- **`solve`**: Recursive function that attempts to solve the puzzle by placing numbers in empty cells,
-  backtracking when necessary.
+- **`SolveSudoku`**: Main function that solves the Sudoku puzzle. It returns the solved grid if there
+  is exactly one solution, or `false` if there are no solutions or multiple solutions.
+- **`SolveAny`**: Variant of `SolveSudoku` that stops at the first solution found and returns it,
+  without checking whether the puzzle's solution is unique.
+- **`IsSolvable`**: Reports whether the grid has at least one solution, stopping at the first one found.
+- **`Solutions`**: Lazily enumerates every solution of a grid, one at a time.
+- **`SolveWithConstraints`**: Same as `SolveSudoku`, but under an explicit list of constraints (see Constraint.go),
+  which is how variant rules are solved.
+- **`searchSolutions`**: Shared search used by the entry points above. It stops once a given number of
+  solutions has been found.
 - **`copyGrid`**: Helper function to copy the current state of the grid when a solution is found.
 
+All the functions above are safe for concurrent use: the search state lives in a value created for each
+call and the package's only global state is the lookup tables of Tables.go and the engine registry of Engine.go,
+which are safe for concurrent use (see Pool.go for solving many puzzles in parallel).
+None of them write to the grid they are given, not even temporarily, and the grid they return is always a new
+map, so the caller's puzzle is left untouched whatever happens to the result.
+
 The goal is to solve the Sudoku puzzle, ensuring there is exactly one solution. If multiple solutions
 or no solution exists, the program will return false.
 */
 
 package sudokux
 
-func SolveSudoku(grid map[string]rune) (map[string]rune, bool) {
-	// Maps to track used numbers in each row, column, and 3x3 subgrid
-	usedRows := make(map[rune]map[rune]bool)       // Map to track numbers used in each row
-	usedCols := make(map[rune]map[rune]bool)       // Map to track numbers used in each column
-	usedSubgrids := make(map[string]map[rune]bool) // Map to track numbers used in each 3x3 subgrid
+import (
+	"iter"
+	"maps"
+)
 
-	// Initialize the usedRows and usedCols maps for all rows (A-I) and columns (1-9)
-	for i := 'A'; i <= 'I'; i++ { // Loop through all rows (A-I)
-		usedRows[i] = make(map[rune]bool) // Initialize map to track numbers for each row
-	}
-	for j := '1'; j <= '9'; j++ { // Loop through all columns (1-9)
-		usedCols[j] = make(map[rune]bool) // Initialize map to track numbers for each column
+// SolveSudoku solves the grid and returns the solution only if it is unique, or a copy of the grid otherwise.
+// It is safe to call from several goroutines at once: every call searches its own copy of the grid.
+func SolveSudoku(grid map[string]rune) (map[string]rune, bool) {
+	solvedGrid, solutionCount := searchSolutions(grid, 2, nil) // Two solutions are enough to prove the puzzle is not unique
+	if solutionCount != 1 {                                    // If there isn't exactly one solution
+		return maps.Clone(grid), false // Return a copy of the grid and false (no solution or multiple solutions)
 	}
+	return solvedGrid, true // Return the solved grid and true (exactly one solution found)
+}
 
-	// Populate the tracking maps based on the initial grid values
-	for i := 'A'; i <= 'I'; i++ { // Loop through all rows (A-I)
-		for j := '1'; j <= '9'; j++ { // Loop through all columns (1-9)
-			pos := string(i) + string(j) // Create a position string, e.g., "A1", "B2"
-			val := grid[pos]             // Get the value (rune) at this position
-			if val != '.' {              // If the cell is not empty ('.')
-				usedRows[i][val] = true                                      // Mark the number as used in the current row
-				usedCols[j][val] = true                                      // Mark the number as used in the current column
-				subgrid := string('A'+(i-'A')/3*3) + string('1'+(j-'1')/3*3) // Calculate subgrid identifier
-				if usedSubgrids[subgrid] == nil {                            // If the subgrid map is not initialized, do so
-					usedSubgrids[subgrid] = make(map[rune]bool)
-				}
-				usedSubgrids[subgrid][val] = true // Mark the number as used in the current subgrid
-			}
-		}
+// SolveAny returns the first solution found, even if the puzzle has more than one.
+// This is useful when the input is known to be a valid puzzle, or when filling an empty grid.
+func SolveAny(grid map[string]rune) (map[string]rune, bool) {
+	solvedGrid, solutionCount := searchSolutions(grid, 1, nil) // Stop as soon as the first solution is found
+	if solutionCount == 0 {                                    // If the grid has no solution at all
+		return maps.Clone(grid), false // Return a copy of the grid and false
 	}
+	return solvedGrid, true // Return the first solution found
+}
 
-	// Variable to keep track of how many solutions have been found
-	solutionCount := 0
-	// Map to store the solved grid (when exactly one solution is found)
-	solvedGrid := make(map[string]rune)
+// IsSolvable reports whether the grid can be completed at all, for callers that only care whether
+// the clues are consistent. Unlike SolveSudoku it stops at the first solution instead of looking for a second one.
+func IsSolvable(grid map[string]rune) bool {
+	if findClueConflict(grid, ShapeOf(grid)) != nil { // Clues that already conflict can never be completed
+		return false
+	}
+	_, solutionCount := searchSolutions(grid, 1, nil) // Stop as soon as the first solution is found
+	return solutionCount > 0
+}
 
-	// Function to find the position with the Minimum Remaining Values (MRV)
-	findNextCell := func() (string, int) {
-		minOptions := 10              // Start with a value greater than the max possible options (9)
-		bestCell := ""                // Store the position of the best cell (least options)
-		for i := 'A'; i <= 'I'; i++ { // Loop through all rows (A-I)
-			for j := '1'; j <= '9'; j++ { // Loop through all columns (1-9)
-				pos := string(i) + string(j) // Create the position string
-				if grid[pos] == '.' {        // If the cell is empty
-					options := 0                                                 // Count valid options for this cell
-					subgrid := string('A'+(i-'A')/3*3) + string('1'+(j-'1')/3*3) // Calculate subgrid identifier
-					for num := '1'; num <= '9'; num++ {                          // Try numbers 1-9
-						if !usedRows[i][num] && !usedCols[j][num] && !usedSubgrids[subgrid][num] { // Check if the number can be placed
-							options++ // Increment options count if valid
-						}
-					}
-					if options < minOptions { // If this cell has fewer options than the current minimum
-						minOptions = options // Update the minimum options
-						bestCell = pos       // Set this cell as the best option
-					}
-				}
+// Solutions returns an iterator over every solution of the grid. Solutions are found lazily as the loop asks
+// for them, so a few solutions of a heavily underconstrained grid can be sampled without enumerating all of them:
+//
+//	for solution := range sudokux.Solutions(grid) {
+//		// use solution, break when done
+//	}
+func Solutions(grid map[string]rune) iter.Seq[map[string]rune] {
+	return func(yield func(map[string]rune) bool) {
+		state := NewSearchState(grid, 0) // No limit: the loop decides when to stop
+		for {
+			solution, ok := state.Next() // Search only as far as the next solution
+			if !ok || !yield(solution) {
+				return
 			}
 		}
-		return bestCell, minOptions // Return the best cell and its number of options
 	}
+}
 
-	// Recursive helper function to solve the grid using backtracking with MRV heuristic
-	var solve func() bool
-	solve = func() bool {
-		pos, minOptions := findNextCell() // Find the next cell with the MRV heuristic
-		if pos == "" {                    // If no empty cell is found, the grid is fully filled
-			return true // Return true to indicate that the grid is solved
-		}
-		if minOptions == 0 { // If there are no valid options for this cell
-			return false // Backtrack by returning false
-		}
-
-		row := rune(pos[0])                                              // Extract the row (letter) from the position
-		col := rune(pos[1])                                              // Extract the column (number) from the position
-		subgrid := string('A'+(row-'A')/3*3) + string('1'+(col-'1')/3*3) // Calculate the subgrid identifier
-
-		for num := '1'; num <= '9'; num++ { // Try numbers 1-9 in the empty cell
-			if !usedRows[row][num] && !usedCols[col][num] && !usedSubgrids[subgrid][num] { // Check if number is valid in row, column, and subgrid
-				grid[pos] = num                                                                       // Place the number in the grid
-				usedRows[row][num], usedCols[col][num], usedSubgrids[subgrid][num] = true, true, true // Mark the number as used
-
-				if solve() { // Recursively attempt to solve the rest of the grid
-					solutionCount++         // Increment solution count if a solution is found
-					if solutionCount == 1 { // If this is the first solution found
-						copyGrid(grid, solvedGrid) // Copy the grid as the solved grid
-					}
-					if solutionCount > 1 { // If more than one solution is found
-						return false // Return false to indicate multiple solutions (not uniquely solvable)
-					}
-				}
-
-				grid[pos] = '.'                                                                          // Undo the current move (backtrack)
-				usedRows[row][num], usedCols[col][num], usedSubgrids[subgrid][num] = false, false, false // Mark the number as unused
-			}
-		}
-		return false // Return false to continue backtracking
+// SolveWithConstraints solves the grid under the given rules instead of the classic ones, and returns the
+// solution only if it is unique. The constraints replace the classic rules, so include ClassicConstraints()
+// in the list to extend them rather than replace them.
+func SolveWithConstraints(grid map[string]rune, constraints []Constraint) (map[string]rune, bool) {
+	solvedGrid, solutionCount := searchSolutions(grid, 2, constraints) // Two solutions are enough to prove the puzzle is not unique
+	if solutionCount != 1 {
+		return maps.Clone(grid), false
 	}
+	return solvedGrid, true
+}
 
-	solve() // Start solving the grid
+// searchSolutions runs the backtracking search until limit solutions have been found or the search space is exhausted.
+// It returns a copy of the first solution and the number of solutions found (never more than limit).
+// A nil list of constraints means the classic rules.
+func searchSolutions(grid map[string]rune, limit int, constraints []Constraint) (map[string]rune, int) {
+	state := NewConstrainedSearchState(grid, limit, constraints) // Build the explicit search state (on a copy of the grid)
+	state.Run(0)                                                 // Run the search to completion
 
-	if solutionCount != 1 { // If there isn't exactly one solution
-		return grid, false // Return the grid and false (no solution or multiple solutions)
+	if len(state.Solutions) == 0 { // If no solution was found
+		return make(map[string]rune), 0
 	}
-	return solvedGrid, true // Return the solved grid and true (exactly one solution found)
+	return state.Solutions[0], len(state.Solutions) // Return the first solution and how many were found
 }
 
 // copyGrid copies the grid state from src to dest.
@@ -0,0 +1,100 @@
+package sudokux
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// goldenCase is a line of testdata/golden.txt.
+type goldenCase struct {
+	line   int
+	puzzle map[string]rune
+	want   string // The cells of the solution, or the status of Solve
+	nodes  int    // Nodes visited by the backtracking search
+}
+
+// readGolden returns the cases of testdata/golden.txt.
+func readGolden(t testing.TB) []goldenCase {
+	file, err := os.Open("testdata/golden.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var cases []goldenCase
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			t.Fatalf("golden.txt:%d: expected 3 fields, got %d", number, len(fields))
+		}
+		puzzle, err := GridFromString(fields[0])
+		if err != nil {
+			t.Fatalf("golden.txt:%d: %v", number, err)
+		}
+		nodes, err := strconv.Atoi(fields[2])
+		if err != nil {
+			t.Fatalf("golden.txt:%d: %v", number, err)
+		}
+		cases = append(cases, goldenCase{line: number, puzzle: puzzle, want: fields[1], nodes: nodes})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+func TestSolveGolden(t *testing.T) {
+	for _, c := range readGolden(t) {
+		t.Run("line"+strconv.Itoa(c.line), func(t *testing.T) {
+			result, err := Solve(c.puzzle)
+			got := CanonicalString(result.Solution)
+			if err != nil {
+				got = result.Status.String()
+			}
+			if got != c.want {
+				t.Errorf("Solve() = %s (%v), want %s", got, err, c.want)
+			}
+			if result.Stats.Nodes != c.nodes {
+				t.Errorf("Solve() visited %d nodes, want %d", result.Stats.Nodes, c.nodes)
+			}
+		})
+	}
+}
+
+func TestEnginesGolden(t *testing.T) {
+	for _, c := range readGolden(t) {
+		for _, engine := range DefaultEngines() {
+			if engine.Name() == "backtrack-first" && c.nodes > 1000 { // Far too slow on hard puzzles
+				continue
+			}
+			solution, count, _ := engine.Solve(c.puzzle)
+			switch got := CanonicalString(solution); {
+			case count == 1 && got != c.want:
+				t.Errorf("golden.txt:%d: %s found %s, want %s", c.line, engine.Name(), got, c.want)
+			case count == 0 && c.want != StatusNoSolution.String(), count == 2 && c.want != StatusMultipleSolutions.String():
+				t.Errorf("golden.txt:%d: %s found %d solutions, want %s", c.line, engine.Name(), count, c.want)
+			}
+		}
+	}
+}
+
+// TestSolveDeterministic checks that two searches of the same puzzle take the same steps, with and without a seed.
+func TestSolveDeterministic(t *testing.T) {
+	for _, c := range readGolden(t) {
+		for _, seed := range []int64{0, 42} {
+			first, _ := Solve(c.puzzle, WithTrace(), WithSeed(seed))
+			second, _ := Solve(c.puzzle, WithTrace(), WithSeed(seed))
+			if !reflect.DeepEqual(first.Trace, second.Trace) {
+				t.Errorf("golden.txt:%d: two searches with seed %d took different steps", c.line, seed)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+This file precomputes the lookups that the solver, the validator, and the logical techniques need for every cell:
+the positions of the board, its units (rows, columns, and boxes), the three units of each cell, the peers of each
+cell, and the classic constraints. Working them out means building position strings and redoing the box arithmetic,
+which used to happen in the innermost loops; now it happens once per shape.
+
+The tables of every default shape (see `ShapeForSize`) are built when the package is loaded and never change
+afterwards, so they are shared freely between goroutines. Shapes with other boxes, such as 3x2 boxes on a 6x6 board,
+get theirs the first time they are used, kept in a cache that is safe for concurrent use.
+
+Functions:
+- **`tables`**: Returns the lookup tables of a shape.
+- **`newShapeTables`**: Builds them.
+*/
+
+package sudokux
+
+import "sync"
+
+// shapeTables holds the precomputed lookups of a shape. They must never be modified.
+type shapeTables struct {
+	cells     []string            // Every position in row-major order
+	units     [][]string          // Rows, then columns, then boxes, as returned by Shape.Units
+	cellUnits map[string][3]int   // Indexes in units of the row, column, and box of each cell
+	peers     map[string][]string // Cells sharing a unit with each cell, in row-major order
+	classic   []Constraint        // Row, column, and box constraints
+}
+
+// defaultShapes lists the default shape of every supported board size, from the smallest to the largest.
+var defaultShapes = supportedShapes()
+
+// defaultTables holds the tables of every default shape, built once when the package is loaded.
+var defaultTables = func() map[Shape]*shapeTables {
+	all := make(map[Shape]*shapeTables, len(defaultShapes))
+	for _, shape := range defaultShapes {
+		all[shape] = newShapeTables(shape)
+	}
+	return all
+}()
+
+// otherTables caches the tables of the shapes that aren't default ones, built the first time they are needed.
+var otherTables sync.Map // Shape -> *shapeTables
+
+// tables returns the lookup tables of the shape.
+func (s Shape) tables() *shapeTables {
+	if t, ok := defaultTables[s]; ok {
+		return t
+	}
+	if t, ok := otherTables.Load(s); ok {
+		return t.(*shapeTables)
+	}
+	t, _ := otherTables.LoadOrStore(s, newShapeTables(s))
+	return t.(*shapeTables)
+}
+
+// newShapeTables works out the lookup tables of the shape.
+func newShapeTables(s Shape) *shapeTables {
+	t := &shapeTables{
+		cells:     make([]string, 0, s.Size*s.Size),
+		units:     make([][]string, 3*s.Size),
+		cellUnits: make(map[string][3]int, s.Size*s.Size),
+		peers:     make(map[string][]string, s.Size*s.Size),
+	}
+	for row := 0; row < s.Size; row++ {
+		for col := 0; col < s.Size; col++ {
+			pos := s.Pos(row, col)
+			box := 2*s.Size + s.BoxOf(row, col)
+			t.cells = append(t.cells, pos)
+			t.units[row] = append(t.units[row], pos)               // Rows
+			t.units[s.Size+col] = append(t.units[s.Size+col], pos) // Columns
+			t.units[box] = append(t.units[box], pos)               // Boxes
+			t.cellUnits[pos] = [3]int{row, s.Size + col, box}
+		}
+	}
+	for _, pos := range t.cells {
+		units := t.cellUnits[pos]
+		for _, other := range t.cells { // Keep the peers in row-major order
+			otherUnits := t.cellUnits[other]
+			if other != pos && (units[0] == otherUnits[0] || units[1] == otherUnits[1] || units[2] == otherUnits[2]) {
+				t.peers[pos] = append(t.peers[pos], other)
+			}
+		}
+	}
+	n := s.Size
+	t.classic = []Constraint{
+		NewRegionConstraint("rows", t.units[0:n]),
+		NewRegionConstraint("columns", t.units[n:2*n]),
+		NewRegionConstraint("subgrids", t.units[2*n:3*n]),
+	}
+	return t
+}
@@ -0,0 +1,134 @@
+/*
+This file adds thermometers: the digits along a thermometer strictly increase from its bulb to its tip. Digits on
+one thermometer are therefore all different, and the cell n steps from the bulb holds at least n+1; the constraint
+prunes the candidates of every cell to the range left between the digits before it and those after it.
+
+Thermometers are read from a clue file (see Clues.go) as "thermo", a colon, and the cells from the bulb to the tip:
+
+	thermo: A1 A2 B3
+
+Functions:
+- **`NewThermoConstraint`**: Builds the constraint enforcing every thermometer.
+*/
+
+package sudokux
+
+import "fmt"
+
+// checkThermo checks that a thermometer has no more cells than there are digits to increase through.
+func checkThermo(cells []string, shape Shape) error {
+	if len(cells) > shape.Size {
+		return fmt.Errorf("a thermometer of %d cells needs more than the %d digits of the board", len(cells), shape.Size)
+	}
+	return nil
+}
+
+// thermoPlace is the place of a cell on a thermometer.
+type thermoPlace struct {
+	line  int // Index of the thermometer
+	index int // Steps from the bulb
+}
+
+// ThermoConstraint requires the digits along every thermometer to increase from the bulb.
+type ThermoConstraint struct {
+	shape  Shape
+	lines  [][]string
+	byCell map[string][]thermoPlace // Places of each cell on the thermometers
+	peers  map[string][]string      // The other cells of the thermometers containing each cell
+}
+
+// NewThermoConstraint creates the constraint for the given thermometers, each listed from its bulb, on a board of
+// the given shape.
+func NewThermoConstraint(shape Shape, lines [][]string) *ThermoConstraint {
+	c := &ThermoConstraint{
+		shape:  shape,
+		lines:  lines,
+		byCell: make(map[string][]thermoPlace),
+		peers:  make(map[string][]string),
+	}
+	for line, cells := range lines {
+		for index, pos := range cells {
+			c.byCell[pos] = append(c.byCell[pos], thermoPlace{line: line, index: index})
+			for _, other := range cells {
+				if other != pos {
+					c.peers[pos] = append(c.peers[pos], other)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *ThermoConstraint) Name() string {
+	return "thermometer"
+}
+
+// Lines returns the thermometers of the constraint, each from its bulb.
+func (c *ThermoConstraint) Lines() [][]string {
+	return c.lines
+}
+
+// Allows reports whether digit at pos leaves room for the digits before and after it on every thermometer through
+// pos: at least one more per step away from it.
+func (c *ThermoConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	value := digitValue(c.shape, digit)
+	for _, place := range c.byCell[pos] {
+		line := c.lines[place.line]
+		if value <= place.index || value > c.shape.Size-(len(line)-1-place.index) {
+			return false
+		}
+		for index, cell := range line {
+			val := grid[cell]
+			if index == place.index || val == '.' || val == 0 {
+				continue
+			}
+			other := digitValue(c.shape, val)
+			if (index < place.index && other+place.index-index > value) || (index > place.index && value+index-place.index > other) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Peers returns the other cells of the thermometers containing pos.
+func (c *ThermoConstraint) Peers(pos string) []string {
+	return c.peers[pos]
+}
+
+// Eliminate narrows every cell of a thermometer to the digits above the smallest digit the cell before it can hold,
+// going up from the bulb, and below the largest digit the cell after it can hold, going down from the tip. It
+// returns false if a cell is left without a digit.
+func (c *ThermoConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	var eliminations []Elimination
+	for _, line := range c.lines { // Thermometers and their cells are visited in order, so eliminations are deterministic
+		low, high := make([]int, len(line)), make([]int, len(line))
+		for i, pos := range line {
+			var ok bool
+			if low[i], high[i], ok = valueBounds(c.shape, grid, candidates, pos); !ok {
+				return nil, false
+			}
+		}
+		for i := 1; i < len(line); i++ {
+			low[i] = max(low[i], low[i-1]+1)
+		}
+		for i := len(line) - 2; i >= 0; i-- {
+			high[i] = min(high[i], high[i+1]-1)
+		}
+		for i, pos := range line {
+			if low[i] > high[i] {
+				return nil, false
+			}
+			if grid[pos] != '.' {
+				continue
+			}
+			for _, digit := range candidates[pos] {
+				if value := digitValue(c.shape, digit); value < low[i] || value > high[i] {
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
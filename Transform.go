@@ -0,0 +1,206 @@
+/*
+This file transforms grids into equivalent ones: each transformation maps every valid grid to a valid grid, and a
+puzzle to a puzzle with the same number of solutions and the same difficulty, so setters can reuse a puzzle under
+a fresh look. The transformations are:
+- Turning the grid a quarter turn, or reflecting it across its main diagonal. Both turn rows into columns, so they
+  need square boxes.
+- Mirroring the grid left to right, or flipping it top to bottom.
+- Relabeling the digits by a permutation, such as swapping every 1 with every 2.
+- Swapping two rows within a band (a row of boxes), two columns within a stack (a column of boxes), two bands, or
+  two stacks.
+
+Transformations only know the rows, columns, and boxes, so they may break the rules of a variant or the clues of a
+Killer puzzle.
+
+Functions:
+- **`Rotate`**, **`Transpose`**, **`Mirror`**, **`Flip`**: Turn or reflect a grid.
+- **`Relabel`**: Swaps the digits of a grid by a permutation.
+- **`SwapRows`**, **`SwapColumns`**, **`SwapBands`**, **`SwapStacks`**: Swap rows, columns, bands, or stacks.
+- **`Shuffle`**: Applies a random combination of all of them.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// remap returns the grid with the digit of every cell (row, col) moved to the cell move(row, col).
+func remap(grid map[string]rune, shape Shape, move func(row, col int) (int, int)) map[string]rune {
+	moved := make(map[string]rune)
+	for row := 0; row < shape.Size; row++ {
+		for col := 0; col < shape.Size; col++ {
+			newRow, newCol := move(row, col)
+			moved[shape.Pos(newRow, newCol)] = grid[shape.Pos(row, col)]
+		}
+	}
+	return moved
+}
+
+// Rotate returns the grid turned a quarter turn clockwise. It returns an error unless the boxes are square.
+func Rotate(grid map[string]rune, shape Shape) (map[string]rune, error) {
+	if shape.BoxRows != shape.BoxCols {
+		return nil, fmt.Errorf("a grid with %dx%d boxes can't be turned, since its boxes would become %dx%d", shape.BoxRows, shape.BoxCols, shape.BoxCols, shape.BoxRows)
+	}
+	last := shape.Size - 1
+	return remap(grid, shape, func(row, col int) (int, int) { return col, last - row }), nil
+}
+
+// Transpose returns the grid reflected across its main diagonal (A1 to I9 on a 9x9 grid), so rows become columns.
+// It returns an error unless the boxes are square.
+func Transpose(grid map[string]rune, shape Shape) (map[string]rune, error) {
+	if shape.BoxRows != shape.BoxCols {
+		return nil, fmt.Errorf("a grid with %dx%d boxes can't be transposed, since its boxes would become %dx%d", shape.BoxRows, shape.BoxCols, shape.BoxCols, shape.BoxRows)
+	}
+	return remap(grid, shape, func(row, col int) (int, int) { return col, row }), nil
+}
+
+// Mirror returns the grid reflected left to right.
+func Mirror(grid map[string]rune, shape Shape) map[string]rune {
+	last := shape.Size - 1
+	return remap(grid, shape, func(row, col int) (int, int) { return row, last - col })
+}
+
+// Flip returns the grid reflected top to bottom.
+func Flip(grid map[string]rune, shape Shape) map[string]rune {
+	last := shape.Size - 1
+	return remap(grid, shape, func(row, col int) (int, int) { return last - row, col })
+}
+
+// Relabel returns the grid with every digit replaced by its image under mapping. Digits missing from mapping stay
+// as they are, and empty cells stay empty. It returns an error if mapping isn't a permutation of the digits.
+func Relabel(grid map[string]rune, shape Shape, mapping map[rune]rune) (map[string]rune, error) {
+	images := make(map[rune]rune) // The image of every digit, missing ones included
+	used := make(map[rune]bool)
+	for _, digit := range shape.Digits() {
+		image, ok := mapping[digit]
+		if !ok {
+			image = digit
+		}
+		if !shape.IsDigit(image) {
+			return nil, fmt.Errorf("invalid digit %q to relabel %c with", image, digit)
+		}
+		if used[image] {
+			return nil, fmt.Errorf("two digits are relabeled as %c", image)
+		}
+		used[image] = true
+		images[digit] = image
+	}
+	for digit := range mapping {
+		if !shape.IsDigit(digit) {
+			return nil, fmt.Errorf("invalid digit %q to relabel", digit)
+		}
+	}
+
+	relabeled := make(map[string]rune)
+	for pos, val := range grid {
+		if image, ok := images[val]; ok {
+			val = image
+		}
+		relabeled[pos] = val
+	}
+	return relabeled, nil
+}
+
+// SwapRows returns the grid with rows a and b (counted from 0) swapped. It returns an error unless both rows are in
+// the same band, since otherwise the boxes would break.
+func SwapRows(grid map[string]rune, shape Shape, a, b int) (map[string]rune, error) {
+	if err := checkLines("row", a, b, shape.Size); err != nil {
+		return nil, err
+	}
+	if a/shape.BoxRows != b/shape.BoxRows {
+		return nil, fmt.Errorf("rows %d and %d are in different bands", a+1, b+1)
+	}
+	return remap(grid, shape, func(row, col int) (int, int) { return swapIndex(row, a, b, 1), col }), nil
+}
+
+// SwapColumns returns the grid with columns a and b (counted from 0) swapped. It returns an error unless both
+// columns are in the same stack, since otherwise the boxes would break.
+func SwapColumns(grid map[string]rune, shape Shape, a, b int) (map[string]rune, error) {
+	if err := checkLines("column", a, b, shape.Size); err != nil {
+		return nil, err
+	}
+	if a/shape.BoxCols != b/shape.BoxCols {
+		return nil, fmt.Errorf("columns %d and %d are in different stacks", a+1, b+1)
+	}
+	return remap(grid, shape, func(row, col int) (int, int) { return row, swapIndex(col, a, b, 1) }), nil
+}
+
+// SwapBands returns the grid with bands a and b (rows of boxes, counted from 0 at the top) swapped.
+func SwapBands(grid map[string]rune, shape Shape, a, b int) (map[string]rune, error) {
+	if err := checkLines("band", a, b, shape.Size/shape.BoxRows); err != nil {
+		return nil, err
+	}
+	return remap(grid, shape, func(row, col int) (int, int) { return swapIndex(row, a, b, shape.BoxRows), col }), nil
+}
+
+// SwapStacks returns the grid with stacks a and b (columns of boxes, counted from 0 on the left) swapped.
+func SwapStacks(grid map[string]rune, shape Shape, a, b int) (map[string]rune, error) {
+	if err := checkLines("stack", a, b, shape.Size/shape.BoxCols); err != nil {
+		return nil, err
+	}
+	return remap(grid, shape, func(row, col int) (int, int) { return row, swapIndex(col, a, b, shape.BoxCols) }), nil
+}
+
+// checkLines checks that the rows, columns, bands, or stacks a and b exist among count of them.
+func checkLines(kind string, a, b, count int) error {
+	for _, index := range []int{a, b} {
+		if index < 0 || index >= count {
+			return fmt.Errorf("no %s %d, expected 1 to %d", kind, index+1, count)
+		}
+	}
+	return nil
+}
+
+// swapIndex returns the new index of row or column i when the groups a and b of width consecutive rows or columns
+// are swapped.
+func swapIndex(i, a, b, width int) int {
+	switch i / width {
+	case a:
+		return b*width + i%width
+	case b:
+		return a*width + i%width
+	}
+	return i
+}
+
+// Shuffle returns the grid under a random combination of every transformation: a relabeling of the digits, a
+// reordering of the bands, the stacks, and the rows and columns within them, and, with square boxes, a transposition
+// half of the time. Every equivalent grid can come out this way, together with the turns and reflections. A non-zero
+// seed gives the same result every time.
+func Shuffle(grid map[string]rune, shape Shape, seed int64) map[string]rune {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	digits := shape.Digits()
+	mapping := make(map[rune]rune)
+	for i, j := range rng.Perm(len(digits)) {
+		mapping[digits[i]] = digits[j]
+	}
+	grid, _ = Relabel(grid, shape, mapping) // A permutation of the digits can't fail
+
+	rows := shuffledLines(shape.Size, shape.BoxRows, rng)
+	cols := shuffledLines(shape.Size, shape.BoxCols, rng)
+	grid = remap(grid, shape, func(row, col int) (int, int) { return rows[row], cols[col] })
+	if shape.BoxRows == shape.BoxCols && rng.Intn(2) == 0 {
+		grid, _ = Transpose(grid, shape)
+	}
+	return grid
+}
+
+// shuffledLines returns a random new index for each of size rows (or columns), in groups of width, that keeps the
+// members of every group together: the groups are shuffled, and so are the rows within each group.
+func shuffledLines(size, width int, rng *rand.Rand) []int {
+	groups := rng.Perm(size / width)
+	lines := make([]int, size)
+	for group := 0; group < size/width; group++ {
+		for i, j := range rng.Perm(width) {
+			lines[group*width+i] = groups[group]*width + j
+		}
+	}
+	return lines
+}
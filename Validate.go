@@ -0,0 +1,96 @@
+/*
+This file checks a grid against the classic rules without solving it, for grids built by a program as well as grids
+parsed from text (the parser stops at the first problem, see Parser.go). `Validate` reports every problem at once,
+each with the cells involved, so that a UI can highlight all of them:
+1. **Malformed cells**: a cell of the board that is missing, or that holds something other than a digit of the board
+   or '.'.
+2. **Conflicting clues**: two cells with the same digit in a row, column, or box (with the default boxes for the
+   size of the grid). A digit appearing three times in a unit is reported as two conflicts with its first cell.
+3. **Empty grid**: no cell is filled at all.
+
+Functions:
+- **`Validate`**: Returns a `*ValidationError` listing every problem of a grid, or nil if it has none.
+- **`clueConflicts`**: Lists every pair of conflicting clues, in a fixed order.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists the problems found by Validate. It wraps ErrInvalidGrid.
+type ValidationError struct {
+	Problems []Contradiction // Every problem found, malformed cells first, then conflicts in the order of Shape.Units()
+}
+
+// Error describes every problem on one line.
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		reasons[i] = problem.Reason
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidGrid, strings.Join(reasons, "; "))
+}
+
+// Unwrap returns ErrInvalidGrid, so that errors.Is(err, ErrInvalidGrid) holds.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidGrid
+}
+
+// Validate checks grid against the classic rules, with the default boxes for its size. It returns nil if the grid
+// is well formed, has no conflicting clues, and is not empty, or a *ValidationError listing every problem otherwise.
+// Validate doesn't check whether the grid has a solution (see IsSolvable and Diagnose).
+func Validate(grid map[string]rune) error {
+	shape := ShapeOf(grid)
+	var problems []Contradiction
+	for _, pos := range shape.Cells() {
+		val, ok := grid[pos]
+		switch {
+		case !ok:
+			problems = append(problems, Contradiction{Cells: []string{pos}, Reason: fmt.Sprintf("cell %s is missing", pos)})
+		case val != '.' && !shape.IsDigit(val):
+			problems = append(problems, Contradiction{Cells: []string{pos}, Digit: val, Reason: fmt.Sprintf("cell %s holds %q, which is neither a digit of a %dx%d board nor '.'", pos, val, shape.Size, shape.Size)})
+		}
+	}
+	for pos := range grid {
+		if _, _, ok := shape.ParsePos(pos); !ok {
+			problems = append(problems, Contradiction{Cells: []string{pos}, Reason: fmt.Sprintf("cell %s is not on a %dx%d board", pos, shape.Size, shape.Size)})
+		}
+	}
+	problems = append(problems, clueConflicts(grid, shape)...)
+	if len(problems) == 0 && isEmptyGrid(grid) {
+		problems = append(problems, Contradiction{Reason: "the entire grid is empty"})
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// clueConflicts returns every clue sharing a digit with an earlier clue of a unit of shape, paired with the first
+// clue of that digit, scanning units in the order of Shape.Units() (rows, then columns, then subgrids).
+func clueConflicts(grid map[string]rune, shape Shape) []Contradiction {
+	var conflicts []Contradiction
+	for u, unit := range shape.tables().units {
+		seen := make(map[rune]string) // Map each digit to the first cell it was seen in
+		for _, pos := range unit {
+			val := grid[pos]
+			if val == '.' || val == 0 {
+				continue
+			}
+			if first, ok := seen[val]; ok {
+				conflicts = append(conflicts, Contradiction{
+					Cells:  []string{first, pos},
+					Unit:   shape.UnitName(u),
+					Digit:  val,
+					Reason: fmt.Sprintf("clues %s and %s both contain %c in %s", first, pos, val, shape.UnitName(u)),
+				})
+				continue
+			}
+			seen[val] = pos
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,185 @@
+/*
+This file defines the Sudoku variants the solver supports. A variant keeps the classic row, column, and subgrid
+rules and adds constraints of its own (see Constraint.go), so the solver handles it without any special case.
+Variants combine freely: a comma-separated list such as "x,antiknight" adds the constraints of each of them.
+
+Variants:
+- **`classic`**: The standard rules only.
+- **`x`**: X-Sudoku, where both main diagonals must also contain every digit exactly once.
+- **`windoku`**: Windoku (or Hyper Sudoku), where four extra 3x3 windows, one cell in from the edges of a 9x9
+  grid, must also contain every digit exactly once.
+- **`antiknight`**: Identical digits may not be a chess knight's move apart.
+- **`antiking`**: Identical digits may not touch, not even diagonally (a chess king's move apart).
+- **`nonconsecutive`**: Orthogonally adjacent cells may not contain consecutive digits (see NonConsecutive.go).
+- **`disjoint`**: Disjoint groups, where the cells in the same position within their boxes (such as the top-left
+  cell of every box) must also contain every digit exactly once.
+- **`asterisk`**: Asterisk Sudoku, where nine extra cells of a 9x9 grid, laid out like an asterisk around the
+  center, must also contain every digit exactly once.
+- **`centerdot`**: Center-dot Sudoku, where the center cells of the boxes must also contain every digit exactly
+  once.
+
+Functions:
+- **`VariantConstraints`**: Returns every constraint of a variant, for a board of the given shape.
+- **`NewDiagonalConstraint`**: Builds the constraint requiring both main diagonals to hold distinct digits.
+- **`NewWindokuConstraint`**: Builds the constraint requiring the four windows of Windoku to hold distinct digits.
+- **`NewAntiKnightConstraint`**: Builds the constraint forbidding identical digits a knight's move apart.
+- **`NewAntiKingConstraint`**: Builds the constraint forbidding identical digits in diagonally touching cells.
+- **`NewDisjointGroupsConstraint`**: Builds the constraint requiring each disjoint group to hold distinct digits.
+- **`NewAsteriskConstraint`**: Builds the constraint requiring the asterisk cells to hold distinct digits.
+- **`NewCenterDotConstraint`**: Builds the constraint requiring the centers of the boxes to hold distinct digits.
+*/
+
+package sudokux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the supported variants, as accepted by VariantConstraints.
+const (
+	VariantClassic    = "classic"        // The standard rules
+	VariantX          = "x"              // The standard rules plus both main diagonals
+	VariantWindoku    = "windoku"        // The standard rules plus four extra 3x3 windows
+	VariantAntiKnight = "antiknight"     // The standard rules plus no identical digits a knight's move apart
+	VariantAntiKing   = "antiking"       // The standard rules plus no identical digits touching diagonally
+	VariantNonConsec  = "nonconsecutive" // The standard rules plus no consecutive digits side by side
+	VariantDisjoint   = "disjoint"       // The standard rules plus the same position in every box
+	VariantAsterisk   = "asterisk"       // The standard rules plus nine extra cells laid out like an asterisk
+	VariantCenterDot  = "centerdot"      // The standard rules plus the center cells of the boxes
+)
+
+// variantNames lists the supported variants, for error messages.
+var variantNames = []string{VariantClassic, VariantX, VariantWindoku, VariantAntiKnight, VariantAntiKing, VariantNonConsec, VariantDisjoint,
+	VariantAsterisk, VariantCenterDot}
+
+// VariantConstraints returns the constraints of the named variant for a board of the given shape: the classic
+// rules followed by the variant's own constraints. Several variants can be combined by separating their names
+// with commas (e.g. "x,antiknight").
+func VariantConstraints(variant string, shape Shape) ([]Constraint, error) {
+	constraints := ClassicConstraintsFor(shape)
+	for _, name := range strings.Split(variant, ",") {
+		switch strings.TrimSpace(name) {
+		case VariantClassic, "":
+			// The classic rules are always included
+		case VariantX:
+			constraints = append(constraints, NewDiagonalConstraint(shape))
+		case VariantWindoku:
+			if shape != Classic {
+				return nil, fmt.Errorf("the %s variant needs a 9x9 board with 3x3 boxes", name)
+			}
+			constraints = append(constraints, NewWindokuConstraint())
+		case VariantAntiKnight:
+			constraints = append(constraints, NewAntiKnightConstraint(shape))
+		case VariantAntiKing:
+			constraints = append(constraints, NewAntiKingConstraint(shape))
+		case VariantNonConsec:
+			constraints = append(constraints, NewNonConsecutiveConstraint(shape))
+		case VariantDisjoint:
+			constraints = append(constraints, NewDisjointGroupsConstraint(shape))
+		case VariantAsterisk:
+			if shape != Classic {
+				return nil, fmt.Errorf("the %s variant needs a 9x9 board with 3x3 boxes", name)
+			}
+			constraints = append(constraints, NewAsteriskConstraint())
+		case VariantCenterDot:
+			if shape.BoxRows%2 == 0 || shape.BoxCols%2 == 0 {
+				return nil, fmt.Errorf("the %s variant needs boxes with a center cell (an odd number of rows and columns)", name)
+			}
+			constraints = append(constraints, NewCenterDotConstraint(shape))
+		default:
+			return nil, fmt.Errorf("unknown variant %q (supported variants are %s, and %s)", name,
+				strings.Join(variantNames[:len(variantNames)-1], ", "), variantNames[len(variantNames)-1])
+		}
+	}
+	return constraints, nil
+}
+
+// NewDiagonalConstraint returns the X-Sudoku rule: no repeated digit along the main diagonal (from the top-left
+// cell to the bottom-right one) or along the anti-diagonal (from the top-right cell to the bottom-left one).
+func NewDiagonalConstraint(shape Shape) *RegionConstraint {
+	diagonals := make([][]string, 2)
+	for i := 0; i < shape.Size; i++ {
+		diagonals[0] = append(diagonals[0], shape.Pos(i, i))              // Main diagonal
+		diagonals[1] = append(diagonals[1], shape.Pos(i, shape.Size-1-i)) // Anti-diagonal
+	}
+	return NewRegionConstraint("diagonals", diagonals)
+}
+
+// NewWindokuConstraint returns the Windoku rule on a 9x9 grid: no repeated digit within any of the four 3x3
+// windows whose top-left cells are B2, B6, F2, and F6.
+func NewWindokuConstraint() *RegionConstraint {
+	var windows [][]string
+	for _, top := range []int{1, 5} { // Rows B and F
+		for _, left := range []int{1, 5} { // Columns 2 and 6
+			var window []string
+			for row := top; row < top+3; row++ {
+				for col := left; col < left+3; col++ {
+					window = append(window, Classic.Pos(row, col))
+				}
+			}
+			windows = append(windows, window)
+		}
+	}
+	return NewRegionConstraint("windows", windows)
+}
+
+// NewAntiKnightConstraint returns the anti-knight rule: no two cells a chess knight's move apart (two cells in one
+// direction and one in the other) may hold the same digit.
+func NewAntiKnightConstraint(shape Shape) *RegionConstraint {
+	return NewRegionConstraint("anti-knight", movePairs(shape, [][2]int{{1, -2}, {1, 2}, {2, -1}, {2, 1}}))
+}
+
+// NewAntiKingConstraint returns the anti-king (or no-touch) rule: no two diagonally touching cells may hold the
+// same digit. Orthogonally touching cells already share a row or a column, so only diagonals need checking.
+func NewAntiKingConstraint(shape Shape) *RegionConstraint {
+	return NewRegionConstraint("anti-king", movePairs(shape, [][2]int{{1, -1}, {1, 1}}))
+}
+
+// NewDisjointGroupsConstraint returns the disjoint groups rule: no repeated digit among the cells in the same
+// position within their boxes. There is one group per position, with one cell from every box.
+func NewDisjointGroupsConstraint(shape Shape) *RegionConstraint {
+	groups := make([][]string, shape.Size)
+	for row := 0; row < shape.Size; row++ {
+		for col := 0; col < shape.Size; col++ {
+			group := (row%shape.BoxRows)*shape.BoxCols + col%shape.BoxCols // Position of the cell within its box
+			groups[group] = append(groups[group], shape.Pos(row, col))
+		}
+	}
+	return NewRegionConstraint("disjoint groups", groups)
+}
+
+// NewAsteriskConstraint returns the Asterisk rule on a 9x9 grid: no repeated digit among the nine cells B5, C3, C7,
+// E2, E5, E8, G3, G7, and H5.
+func NewAsteriskConstraint() *RegionConstraint {
+	return NewRegionConstraint("asterisk", [][]string{{"B5", "C3", "C7", "E2", "E5", "E8", "G3", "G7", "H5"}})
+}
+
+// NewCenterDotConstraint returns the Center-dot rule: no repeated digit among the center cells of the boxes. The
+// boxes must have an odd number of rows and columns, so that they have a center cell.
+func NewCenterDotConstraint(shape Shape) *RegionConstraint {
+	var centers []string
+	for row := shape.BoxRows / 2; row < shape.Size; row += shape.BoxRows {
+		for col := shape.BoxCols / 2; col < shape.Size; col += shape.BoxCols {
+			centers = append(centers, shape.Pos(row, col))
+		}
+	}
+	return NewRegionConstraint("center dots", [][]string{centers})
+}
+
+// movePairs returns every pair of cells one of the given moves apart, as two-cell regions. Moves only go downwards
+// (or rightwards on the same row), so that each pair appears once.
+func movePairs(shape Shape, moves [][2]int) [][]string {
+	var pairs [][]string
+	for row := 0; row < shape.Size; row++ {
+		for col := 0; col < shape.Size; col++ {
+			for _, move := range moves {
+				r, c := row+move[0], col+move[1]
+				if r < shape.Size && c >= 0 && c < shape.Size {
+					pairs = append(pairs, []string{shape.Pos(row, col), shape.Pos(r, c)})
+				}
+			}
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,120 @@
+/*
+This file adds German Whispers lines: adjacent digits along a line must differ by at least 5 on a 9x9 grid (half the
+largest digit, rounded up, on other sizes). The middle digit can never sit on a line, since no digit is far enough
+from it, and the constraint prunes such candidates before anything is placed, along with every candidate that no
+neighbor on the line could sit next to.
+
+Lines are read from a clue file (see Clues.go) as "whisper", a colon, and the cells along the line in order:
+
+	whisper: A1 A2 B3 C3
+
+Functions:
+- **`NewWhispersConstraint`**: Builds the constraint enforcing every whisper line.
+*/
+
+package sudokux
+
+// WhispersConstraint requires adjacent digits along every whisper line to be far enough apart.
+type WhispersConstraint struct {
+	shape     Shape
+	lines     [][]string
+	cells     []string            // Every cell on a line, in order of appearance, so eliminations are deterministic
+	index     map[string]int      // Index of each cell in cells
+	neighbors map[string][]string // The cells next to each cell along its lines
+	adjacent  [][]int             // The same neighbors, as indexes into cells
+	allowed   []uint32            // For each digit index, the digits that may sit next to it, one bit per index
+}
+
+// NewWhispersConstraint creates the constraint for the given lines on a board of the given shape.
+func NewWhispersConstraint(shape Shape, lines [][]string) *WhispersConstraint {
+	c := &WhispersConstraint{
+		shape:     shape,
+		lines:     lines,
+		index:     make(map[string]int),
+		neighbors: make(map[string][]string),
+		allowed:   make([]uint32, shape.Size),
+	}
+	gap := (shape.Size + 1) / 2 // 5 on a 9x9 grid
+	for a := 0; a < shape.Size; a++ {
+		for b := 0; b < shape.Size; b++ {
+			if abs(a-b) >= gap {
+				c.allowed[a] |= 1 << b
+			}
+		}
+	}
+	for _, line := range lines {
+		for _, pos := range line {
+			if _, seen := c.index[pos]; !seen {
+				c.index[pos] = len(c.cells)
+				c.cells = append(c.cells, pos)
+			}
+		}
+	}
+	c.adjacent = make([][]int, len(c.cells))
+	for _, line := range lines {
+		for i := 1; i < len(line); i++ {
+			a, b := line[i-1], line[i]
+			c.neighbors[a] = append(c.neighbors[a], b)
+			c.neighbors[b] = append(c.neighbors[b], a)
+			c.adjacent[c.index[a]] = append(c.adjacent[c.index[a]], c.index[b])
+			c.adjacent[c.index[b]] = append(c.adjacent[c.index[b]], c.index[a])
+		}
+	}
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c *WhispersConstraint) Name() string {
+	return "whispers"
+}
+
+// Lines returns the whisper lines of the constraint.
+func (c *WhispersConstraint) Lines() [][]string {
+	return c.lines
+}
+
+// Allows reports whether digit at pos is far enough from every filled neighbor along its lines.
+func (c *WhispersConstraint) Allows(grid map[string]rune, pos string, digit rune) bool {
+	allowed := c.allowed[c.shape.DigitIndex(digit)]
+	for _, neighbor := range c.neighbors[pos] {
+		if val := grid[neighbor]; val != '.' && val != 0 && allowed&(1<<c.shape.DigitIndex(val)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers returns the cells next to pos along its lines.
+func (c *WhispersConstraint) Peers(pos string) []string {
+	return c.neighbors[pos]
+}
+
+// Eliminate removes a candidate from a cell on a line when one of its empty neighbors has no candidate far enough
+// from it. This rules out the middle digit everywhere on a line, as well as digits left without a partner.
+func (c *WhispersConstraint) Eliminate(grid map[string]rune, candidates map[string][]rune) ([]Elimination, bool) {
+	masks := make([]uint32, len(c.cells)) // Candidate digits of each empty cell, one bit per index (0 if filled)
+	for i, pos := range c.cells {
+		if grid[pos] == '.' {
+			for _, digit := range candidates[pos] {
+				masks[i] |= 1 << c.shape.DigitIndex(digit)
+			}
+		}
+	}
+
+	var eliminations []Elimination
+	for i, pos := range c.cells {
+		if masks[i] == 0 {
+			continue
+		}
+		for _, digit := range candidates[pos] {
+			allowed := c.allowed[c.shape.DigitIndex(digit)]
+			for _, neighbor := range c.adjacent[i] {
+				if masks[neighbor] != 0 && masks[neighbor]&allowed == 0 { // Filled neighbors are handled by Allows
+					eliminations = append(eliminations, Elimination{Pos: pos, Digit: digit})
+					break
+				}
+			}
+		}
+	}
+	return eliminations, true
+}
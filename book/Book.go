@@ -0,0 +1,135 @@
+/*
+Package book lays out puzzle books: printable PDF booklets (see the pdf package) with the puzzles up front, two to a
+page, and their solutions in an appendix at the back, six to a page. Every puzzle is numbered and labeled with its
+difficulty, and the givens of each solution are set in bold so the solver can find their way back to the puzzle.
+
+It is kept apart from the solver, so that programs using the solver don't build the PDF writer.
+
+Functions:
+- **`Write`**: Writes a booklet of puzzles and their solutions as a PDF file.
+*/
+
+package book
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"sudokux"
+	"sudokux/pdf"
+)
+
+// Puzzle is one puzzle of a book.
+type Puzzle struct {
+	Puzzle     map[string]rune    // The classic puzzle
+	Solution   map[string]rune    // Its solution
+	Difficulty sudokux.Difficulty // Its grade, printed with it
+}
+
+// Layout of the pages of a book, in points.
+const (
+	puzzlesPerPage   = 2
+	puzzleCell       = 30.0 // Side of a cell of a puzzle
+	solutionsPerRow  = 2
+	solutionsPerPage = 6
+	solutionCell     = 20.0 // Side of a cell of a solution
+)
+
+// Write writes the puzzles, with the title on every page, as a PDF booklet to w: the puzzles first, then their
+// solutions in the same order. It returns an error if a puzzle isn't a classic grid.
+func Write(w io.Writer, title string, puzzles []Puzzle) error {
+	for i, p := range puzzles {
+		if sudokux.ShapeOf(p.Puzzle) != sudokux.Classic || sudokux.ShapeOf(p.Solution) != sudokux.Classic {
+			return fmt.Errorf("puzzle %d: only 9x9 puzzles can be printed", i+1)
+		}
+	}
+	var doc pdf.Document
+	side := puzzleCell * float64(sudokux.Classic.Size)
+	for i, p := range puzzles {
+		slot := i % puzzlesPerPage
+		if slot == 0 {
+			startBookPage(&doc, title)
+		}
+		top := pdf.PageHeight - 90 - float64(slot)*(side+110)
+		left := (pdf.PageWidth - side) / 2
+		doc.Text(left, top+12, 14, true, 0, fmt.Sprintf("Puzzle %d", i+1))
+		label := difficultyLabel(p.Difficulty)
+		doc.Text(left+side-pdf.TextWidth(label, 12), top+12, 12, false, 0.4, label)
+		drawGrid(&doc, left, top, puzzleCell, p.Puzzle, nil)
+	}
+
+	side = solutionCell * float64(sudokux.Classic.Size)
+	gap := (pdf.PageWidth - solutionsPerRow*side) / (solutionsPerRow + 1)
+	for i, p := range puzzles {
+		slot := i % solutionsPerPage
+		if slot == 0 {
+			startBookPage(&doc, title)
+			doc.Text(gap, pdf.PageHeight-70, 16, true, 0, "Solutions")
+		}
+		left := gap + float64(slot%solutionsPerRow)*(side+gap)
+		top := pdf.PageHeight - 110 - float64(slot/solutionsPerRow)*(side+60)
+		doc.Text(left, top+8, 11, true, 0, fmt.Sprintf("Puzzle %d", i+1))
+		label := difficultyLabel(p.Difficulty)
+		doc.Text(left+side-pdf.TextWidth(label, 10), top+8, 10, false, 0.4, label)
+		drawGrid(&doc, left, top, solutionCell, p.Solution, p.Puzzle)
+	}
+	return doc.Write(w)
+}
+
+// startBookPage starts a page of a book, with the title at the top and the page number at the bottom.
+func startBookPage(doc *pdf.Document, title string) {
+	doc.NewPage()
+	if title != "" {
+		doc.CenteredText(pdf.PageWidth/2, pdf.PageHeight-40, 10, false, 0.4, title)
+	}
+	doc.CenteredText(pdf.PageWidth/2, 30, 10, false, 0.4, fmt.Sprint(doc.Pages()))
+}
+
+// difficultyLabel returns the grade as printed in a book (such as "Hard"), or "" when the puzzle isn't graded.
+func difficultyLabel(d sudokux.Difficulty) string {
+	if d == "" {
+		return ""
+	}
+	return strings.ToUpper(string(d[:1])) + string(d[1:])
+}
+
+// drawGrid draws grid with its top-left corner at (left, top) and cells of the given side. The digits of givens
+// (nil for all of them) are set in bold and the others in gray.
+func drawGrid(doc *pdf.Document, left, top, cell float64, grid, givens map[string]rune) {
+	size := sudokux.Classic.Size
+	side := cell * float64(size)
+	for i := 0; i <= size; i++ {
+		width := 0.5
+		if i%sudokux.Classic.BoxRows == 0 { // Box borders and the outline are thicker
+			width = 2
+		}
+		y := top - float64(i)*cell
+		doc.Line(left-1, y, left+side+1, y, width) // Overlap the corners so thick lines join cleanly
+		width = 0.5
+		if i%sudokux.Classic.BoxCols == 0 {
+			width = 2
+		}
+		x := left + float64(i)*cell
+		doc.Line(x, top+1, x, top-side-1, width)
+	}
+
+	fontSize := cell * 0.6
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			pos := sudokux.Classic.Pos(row, col)
+			val := grid[pos]
+			if val == '.' || val == 0 {
+				continue
+			}
+			given := givens == nil || givens[pos] == val
+			gray := 0.0
+			if !given {
+				gray = 0.45
+			}
+			x := left + (float64(col)+0.5)*cell
+			y := top - (float64(row)+0.5)*cell - fontSize*0.35 // Center the digits vertically on the cell
+			doc.CenteredText(x, y, fontSize, given, gray, string(val))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Package bot answers the puzzles posted in chat channels, for a team or a community that shares puzzles in Slack or
+Discord. It is served over HTTP like the server package, as both platforms send their events to a webhook:
+- **`POST /slack/events`**: The Events API of a Slack app (see Slack.go). Messages holding a puzzle, and mentions
+  of the bot, are answered in a thread with the solved board, or with the grade of the puzzle after "rate".
+- **`POST /discord/interactions`**: The interactions endpoint of a Discord application (see Discord.go), for its
+  `/solve` and `/rate` slash commands.
+
+A puzzle is written as its 81 cells on one line, or as its rows separated by spaces or lines, read with
+`sudokux.ParseBytes`, so that any board size with its default boxes can be posted:
+
+	@sudoku rate 53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79
+
+Puzzles in image attachments can't be read: the bot answers them by asking for the digits.
+*/
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"sudokux"
+)
+
+// Bot answers the puzzles of the chat platforms it has the credentials of.
+type Bot struct {
+	MaxTimeout time.Duration // Longest search allowed for one puzzle, or 0 for no limit
+
+	SlackSigningSecret string       // Signing secret of the Slack app, or "" to leave out /slack/events
+	SlackToken         string       // Bot token of the Slack app, to post its answers
+	SlackAPI           string       // Base URL of the Slack Web API, or "" for https://slack.com/api
+	DiscordPublicKey   []byte       // Ed25519 public key of the Discord application, or nil to leave out /discord/interactions
+	Client             *http.Client // Client of the Slack Web API, or nil for http.DefaultClient
+}
+
+// Handler returns the handler of the endpoints of the platforms the bot has the credentials of.
+func (b *Bot) Handler() http.Handler {
+	mux := http.NewServeMux()
+	if b.SlackSigningSecret != "" {
+		mux.HandleFunc("POST /slack/events", b.slackEvents)
+	}
+	if b.DiscordPublicKey != nil {
+		mux.HandleFunc("POST /discord/interactions", b.discordInteractions)
+	}
+	return mux
+}
+
+// mention matches the mentions of users in Slack and Discord messages, such as "<@U024BE7LH>" or "<@!80351110224678912>".
+var mention = regexp.MustCompile(`<@!?[A-Za-z0-9]+>`)
+
+// Answer returns the reply of the bot to a message: the solved board of the puzzle it holds, or its grade if the
+// message starts with "rate". ok is false if the message doesn't hold a puzzle at all, so that the bot stays quiet
+// in a channel where people talk about other things.
+func (b *Bot) Answer(text string) (reply string, ok bool) {
+	words := strings.Fields(mention.ReplaceAllString(text, " "))
+	rate := false
+	if len(words) > 0 {
+		switch strings.ToLower(words[0]) {
+		case "rate":
+			rate, words = true, words[1:]
+		case "solve":
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return "", false
+	}
+	grid, err := sudokux.ParseBytes([]byte(strings.Join(words, "\n")))
+	if err != nil {
+		return "", false
+	}
+	if rate {
+		return b.rate(grid), true
+	}
+	return b.solve(grid), true
+}
+
+// help is the reply to a message the bot was addressed with but can't read a puzzle from.
+const help = "Send me a puzzle as its 81 cells on one line, or as its rows separated by spaces, with a dot or a 0 " +
+	"for empty cells, and I'll solve it. Start with \"rate\" to get its grade instead."
+
+// imageReply is the reply to a puzzle sent as an image.
+const imageReply = "I can't read puzzles from images yet. " + help
+
+// solve returns the reply to a puzzle to solve: its solved board, or why it has no solution.
+func (b *Bot) solve(grid map[string]rune) string {
+	result, err := sudokux.Solve(grid, b.options()...)
+	if err != nil {
+		return "This puzzle can't be solved: " + err.Error() + "."
+	}
+	return "Solved in " + result.Stats.Elapsed.Round(time.Millisecond).String() + ":\n" + render(result.Solution, sudokux.ShapeOf(grid))
+}
+
+// rate returns the reply to a puzzle to grade, without revealing its solution.
+func (b *Bot) rate(grid map[string]rune) string {
+	if _, err := sudokux.Solve(grid, b.options()...); err != nil { // Grades only make sense for puzzles with a unique solution
+		return "This puzzle can't be rated: " + err.Error() + "."
+	}
+	rating := sudokux.RatePuzzle(grid)
+	var reply strings.Builder
+	fmt.Fprintf(&reply, "Difficulty: %s (score %.1f)\n", rating.Difficulty, rating.Score)
+	var uses []string
+	for _, use := range rating.Techniques {
+		uses = append(uses, fmt.Sprintf("%s (%d)", use.Technique, use.Count))
+	}
+	if len(uses) == 0 {
+		uses = append(uses, "none")
+	}
+	fmt.Fprintf(&reply, "Techniques: %s\n", strings.Join(uses, ", "))
+	if rating.NeedsGuessing {
+		fmt.Fprintf(&reply, "Guessing needed: yes (logic fills %d of the %d empty cells)\n", rating.LogicFilled, rating.Empty)
+	}
+	low, high := rating.SolveTime()
+	fmt.Fprintf(&reply, "Estimated time: about %d–%d minutes", int(low.Minutes()), int(high.Minutes()))
+	return reply.String()
+}
+
+// options returns the options of the searches of the bot.
+func (b *Bot) options() []sudokux.Option {
+	if b.MaxTimeout > 0 {
+		return []sudokux.Option{sudokux.WithTimeout(b.MaxTimeout)}
+	}
+	return nil
+}
+
+// render draws grid, a board of the given shape, in a code block, with lines between its boxes, such as
+// "5 3 4 | 6 7 8 | 9 1 2" for the first row of a classic board and "------+-------+------" below every band.
+func render(grid map[string]rune, shape sudokux.Shape) string {
+	rule := strings.Repeat("-", 2*shape.BoxCols-1)
+	rules := make([]string, shape.Size/shape.BoxCols)
+	for i := range rules {
+		rules[i] = rule
+	}
+	lines := []string{"```"}
+	for r := 0; r < shape.Size; r++ {
+		if r > 0 && r%shape.BoxRows == 0 {
+			lines = append(lines, strings.Join(rules, "-+-"))
+		}
+		var boxes []string
+		for c := 0; c < shape.Size; c += shape.BoxCols {
+			var digits []string
+			for d := c; d < c+shape.BoxCols; d++ {
+				digits = append(digits, string(grid[shape.Pos(r, d)]))
+			}
+			boxes = append(boxes, strings.Join(digits, " "))
+		}
+		lines = append(lines, strings.Join(boxes, " | "))
+	}
+	return strings.Join(append(lines, "```"), "\n")
+}
+
+// errUnsigned is the error of a request whose signature doesn't match the secret of its platform.
+var errUnsigned = errors.New("the signature of the request doesn't match")
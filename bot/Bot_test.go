@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	firstRow       = "5 3 4 | 6 7 8 | 9 1 2"                                                             // First row of the solution of readmePuzzle, as render draws it
+	multiplePuzzle = "................................................................87419635345286179" // The last 17 cells of the solution
+)
+
+func TestAnswer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		ok   bool
+		want string // Part of the reply
+	}{
+		{"cells", readmePuzzle, true, firstRow},
+		{"mention", "<@U024BE7LH> solve " + readmePuzzle, true, firstRow},
+		{"rows", "53..7.... 6..195... .98....6. 8...6...3 4..8.3..1 7...2...6 .6....28. ...419..5 ....8..79", true, firstRow},
+		{"rate", "rate " + readmePuzzle, true, "Difficulty: easy (score 1.2)"},
+		{"several solutions", multiplePuzzle, true, "can't be solved: the puzzle has more than one solution."},
+		{"rate without a unique solution", "rate " + multiplePuzzle, true, "can't be rated"},
+		{"conflicting clues", "55" + readmePuzzle[2:], false, ""},
+		{"chat", "has anyone tried the puzzle of the day?", false, ""},
+		{"mention alone", "<@U024BE7LH>", false, ""},
+	}
+	var b Bot
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, ok := b.Answer(tt.text)
+			if ok != tt.ok || !strings.Contains(reply, tt.want) {
+				t.Errorf("Answer(%q) = %q, %v, want %q, %v", tt.text, reply, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// signSlack returns a request to /slack/events of server with body, signed with secret at timestamp.
+func signSlack(t *testing.T, server, secret, body string, timestamp time.Time) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, server+"/slack/events", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestSlack(t *testing.T) {
+	posts := make(chan map[string]string, 10)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var post map[string]string
+		if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer xoxb-token" || json.NewDecoder(r.Body).Decode(&post) != nil {
+			t.Errorf("unexpected call of the Slack API: %s %s", r.Method, r.URL)
+		}
+		posts <- post
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer api.Close()
+	const secret = "signing-secret"
+	server := httptest.NewServer((&Bot{SlackSigningSecret: secret, SlackToken: "xoxb-token", SlackAPI: api.URL}).Handler())
+	defer server.Close()
+
+	event := func(text string) string {
+		body, _ := json.Marshal(map[string]any{"type": "event_callback", "event": map[string]string{
+			"type": "message", "text": text, "channel": "C1", "ts": "1700000000.000100"}})
+		return string(body)
+	}
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+		body   string // Body of the answer, if checked
+		post   string // Part of the message posted in the thread, or "" for none
+	}{
+		{name: "url verification", req: signSlack(t, server.URL, secret, `{"type": "url_verification", "challenge": "abc"}`, time.Now()),
+			status: http.StatusOK, body: "abc"},
+		{name: "puzzle", req: signSlack(t, server.URL, secret, event(readmePuzzle), time.Now()), status: http.StatusOK, post: firstRow},
+		{name: "chat", req: signSlack(t, server.URL, secret, event("good morning"), time.Now()), status: http.StatusOK},
+		{name: "wrong secret", req: signSlack(t, server.URL, "other", event(readmePuzzle), time.Now()), status: http.StatusUnauthorized},
+		{name: "replayed", req: signSlack(t, server.URL, secret, event(readmePuzzle), time.Now().Add(-time.Hour)), status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.DefaultClient.Do(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || tt.body != "" && string(body) != tt.body {
+				t.Fatalf("status %d %q, want %d %q", resp.StatusCode, body, tt.status, tt.body)
+			}
+			if tt.post == "" {
+				return
+			}
+			select {
+			case post := <-posts:
+				if post["channel"] != "C1" || post["thread_ts"] != "1700000000.000100" || !strings.Contains(post["text"], tt.post) {
+					t.Errorf("posted %v, want %q in the thread of the message", post, tt.post)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no answer was posted")
+			}
+		})
+	}
+	select { // The messages without a puzzle, and those refused, are left unanswered
+	case post := <-posts:
+		t.Errorf("unexpected post %v", post)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer((&Bot{DiscordPublicKey: public}).Handler())
+	defer server.Close()
+
+	command := func(name, puzzle string) string {
+		return `{"type": 2, "data": {"name": "` + name + `", "options": [{"name": "puzzle", "type": 3, "value": "` + puzzle + `"}]}}`
+	}
+	tests := []struct {
+		name   string
+		body   string
+		key    ed25519.PrivateKey
+		status int
+		want   string // Part of the answer
+	}{
+		{"ping", `{"type": 1}`, private, http.StatusOK, `"type":1`},
+		{"solve", command("solve", readmePuzzle), private, http.StatusOK, firstRow},
+		{"rate", command("rate", readmePuzzle), private, http.StatusOK, "Difficulty: easy"},
+		{"unreadable puzzle", command("solve", "53..7"), private, http.StatusOK, "I can't read this puzzle"},
+		{"unknown interaction", `{"type": 9}`, private, http.StatusBadRequest, "unknown interaction"},
+		{"wrong key", `{"type": 1}`, ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)), http.StatusUnauthorized, "signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/discord/interactions", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			req.Header.Set("X-Signature-Timestamp", timestamp)
+			req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(tt.key, []byte(timestamp+tt.body))))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+				t.Errorf("status %d %q, want %d %q", resp.StatusCode, body, tt.status, tt.want)
+			}
+		})
+	}
+}
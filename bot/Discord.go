@@ -0,0 +1,116 @@
+/*
+This file implements `POST /discord/interactions`, the interactions endpoint URL of a Discord application with two
+slash commands, registered once with the API of Discord (see the readme):
+- **`/solve puzzle:<cells>`**: Answers with the solved board.
+- **`/rate puzzle:<cells>`**: Answers with the grade of the puzzle.
+
+Both commands can take an `image` attachment instead of the cells, which is answered by asking for the digits.
+Discord signs every request with the Ed25519 key of the application, and expects the answer within three seconds,
+so the searches of the bot are cut to discordTimeout.
+*/
+
+package bot
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"sudokux"
+)
+
+// Types of the interactions, and of their answers, the bot knows.
+const (
+	discordPing             = 1 // Sent when the endpoint is set up
+	discordCommand          = 2 // A slash command
+	discordPong             = 1 // The answer to discordPing
+	discordMessage          = 4 // An answer with a message
+	discordAttachmentOption = 11
+)
+
+// discordInteraction is the body of a request of Discord, with the fields the bot uses.
+type discordInteraction struct {
+	Type int `json:"type"`
+	Data struct {
+		Name    string `json:"name"` // Name of the slash command
+		Options []struct {
+			Name  string `json:"name"`
+			Type  int    `json:"type"`
+			Value any    `json:"value"` // The text of a string option
+		} `json:"options"`
+	} `json:"data"`
+}
+
+// discordTimeout is the longest search of a slash command, which Discord waits for three seconds.
+const discordTimeout = 2 * time.Second
+
+// discordInteractions answers POST /discord/interactions.
+func (b *Bot) discordInteractions(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, sudokux.MaxInputBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !b.checkDiscordSignature(r.Header, body) {
+		http.Error(w, errUnsigned.Error(), http.StatusUnauthorized)
+		return
+	}
+	var req discordInteraction
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch req.Type {
+	case discordPing:
+		writeDiscord(w, map[string]any{"type": discordPong})
+	case discordCommand:
+		writeDiscord(w, map[string]any{"type": discordMessage, "data": map[string]any{"content": b.discordReply(req)}})
+	default:
+		http.Error(w, "unknown interaction", http.StatusBadRequest)
+	}
+}
+
+// discordReply returns the answer to the slash command of req.
+func (b *Bot) discordReply(req discordInteraction) string {
+	puzzle, image := "", false
+	for _, option := range req.Data.Options {
+		if text, ok := option.Value.(string); ok && option.Name == "puzzle" {
+			puzzle = text
+		}
+		image = image || option.Type == discordAttachmentOption
+	}
+	command := "solve"
+	if req.Data.Name == "rate" {
+		command = "rate"
+	}
+	limited := *b // The answer can't wait for the limit of the bot
+	if limited.MaxTimeout == 0 || limited.MaxTimeout > discordTimeout {
+		limited.MaxTimeout = discordTimeout
+	}
+	if reply, ok := limited.Answer(command + " " + puzzle); ok {
+		return reply
+	}
+	if image {
+		return imageReply
+	}
+	return "I can't read this puzzle. " + help
+}
+
+// checkDiscordSignature reports whether the request of header and body was signed with the key of the application.
+func (b *Bot) checkDiscordSignature(header http.Header, body []byte) bool {
+	signature, err := hex.DecodeString(header.Get("X-Signature-Ed25519"))
+	if err != nil || len(b.DiscordPublicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	message := append([]byte(header.Get("X-Signature-Timestamp")), body...)
+	return ed25519.Verify(b.DiscordPublicKey, message, signature)
+}
+
+// writeDiscord answers with v as JSON.
+func writeDiscord(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,172 @@
+/*
+This file implements `POST /slack/events`, the request URL of the Events API of a Slack app subscribed to the
+`message.channels` and `app_mention` events, with the `chat:write` scope. Slack signs every request with the
+signing secret of the app, and expects an answer within three seconds, so the events are acknowledged at once and
+answered afterwards with `chat.postMessage`, in the thread of the message.
+
+Messages holding a puzzle are answered wherever they are posted in the channels the bot was invited to. Other
+messages are left alone, unless they mention the bot, which then explains how to send a puzzle. Messages with
+image attachments are answered by asking for the digits.
+*/
+
+package bot
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sudokux"
+)
+
+// slackEvent is the body of a request of the Events API, with the fields the bot uses.
+type slackEvent struct {
+	Type      string       `json:"type"`      // "url_verification" when the URL is set up, or "event_callback"
+	Challenge string       `json:"challenge"` // To send back for "url_verification"
+	Event     slackMessage `json:"event"`
+}
+
+// slackMessage is the event of a slackEvent, with the fields the bot uses.
+type slackMessage struct {
+	Type     string `json:"type"`      // "message" or "app_mention"
+	Subtype  string `json:"subtype"`   // Set for edits, deletions, and other messages that aren't posted by people
+	BotID    string `json:"bot_id"`    // Set for the messages of bots, the bot's own included
+	Text     string `json:"text"`      // Text of the message
+	Channel  string `json:"channel"`   // Channel of the message
+	TS       string `json:"ts"`        // Timestamp of the message, which identifies it
+	ThreadTS string `json:"thread_ts"` // Timestamp of the first message of its thread, if it is in one
+	Files    []struct {
+		Mimetype string `json:"mimetype"`
+	} `json:"files"` // Attachments of the message
+}
+
+// slackMaxAge is how old a request may be, so that a request recorded by someone else can't be replayed later.
+const slackMaxAge = 5 * time.Minute
+
+// slackEvents answers POST /slack/events.
+func (b *Bot) slackEvents(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, sudokux.MaxInputBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := b.checkSlackSignature(r.Header, body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	var req slackEvent
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Type == "url_verification" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(req.Challenge))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if req.Type != "event_callback" || r.Header.Get("X-Slack-Retry-Num") != "" { // Retries of events answered already, if slowly
+		return
+	}
+	event := req.Event
+	if event.BotID != "" || event.Subtype != "" && event.Subtype != "file_share" {
+		return
+	}
+	if event.Type != "app_mention" && strings.Contains(event.Text, "<@") { // Mentions of the bot are answered on their app_mention event
+		return
+	}
+	go b.answerSlack(event) // Slack wants its acknowledgment now, whatever the time the search takes
+}
+
+// answerSlack answers message in its thread, if it holds a puzzle, an image, or a mention of the bot.
+func (b *Bot) answerSlack(message slackMessage) {
+	reply, ok := b.Answer(message.Text)
+	if !ok {
+		for _, file := range message.Files {
+			if strings.HasPrefix(file.Mimetype, "image/") {
+				reply, ok = imageReply, true
+				break
+			}
+		}
+	}
+	if !ok && message.Type == "app_mention" {
+		reply, ok = help, true
+	}
+	if !ok {
+		return
+	}
+	thread := message.ThreadTS
+	if thread == "" {
+		thread = message.TS
+	}
+	if err := b.postSlack(message.Channel, thread, reply); err != nil {
+		slog.Error("cannot answer on Slack", "channel", message.Channel, "error", err)
+	}
+}
+
+// checkSlackSignature checks that the request of header and body was signed with the signing secret of the app
+// less than slackMaxAge before now.
+func (b *Bot) checkSlackSignature(header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errUnsigned
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackMaxAge || age < -slackMaxAge {
+		return fmt.Errorf("the request is too old (%v)", age.Round(time.Second))
+	}
+	mac := hmac.New(sha256.New, []byte(b.SlackSigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errUnsigned
+	}
+	return nil
+}
+
+// postSlack posts text in the thread of the message thread of channel.
+func (b *Bot) postSlack(channel, thread, text string) error {
+	body, err := json.Marshal(map[string]string{"channel": channel, "thread_ts": thread, "text": text})
+	if err != nil {
+		return err
+	}
+	api := b.SlackAPI
+	if api == "" {
+		api = "https://slack.com/api"
+	}
+	req, err := http.NewRequest(http.MethodPost, api+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+b.SlackToken)
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var answer struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return fmt.Errorf("%s: %v", resp.Status, err)
+	}
+	if !answer.OK {
+		return fmt.Errorf("chat.postMessage failed: %s", answer.Error)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"sudokux"
+)
+
+// boltBucket is the bucket of the solutions in the database.
+var boltBucket = []byte("solutions")
+
+// Bolt is a SolutionCache stored in a bolt database file. Only one process can open the file at a time.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// NewBolt opens the database of path, creating it if needed. It fails if another process holds the file for more
+// than a second.
+func NewBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Bolt{db: db}, nil
+}
+
+// Get returns the cached solution for key, if any.
+func (c *Bolt) Get(key string) (map[string]rune, bool) {
+	var solution string
+	c.db.View(func(tx *bolt.Tx) error {
+		solution = string(tx.Bucket(boltBucket).Get([]byte(key))) // Copied, as the value is only valid in the transaction
+		return nil
+	})
+	if solution == "" {
+		return nil, false
+	}
+	grid, err := sudokux.GridFromString(solution)
+	return grid, err == nil
+}
+
+// Put stores the solution for key.
+func (c *Bolt) Put(key string, solution map[string]rune) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), []byte(sudokux.CanonicalString(solution)))
+	})
+}
+
+// Close closes the database file.
+func (c *Bolt) Close() error {
+	return c.db.Close()
+}
@@ -0,0 +1,47 @@
+/*
+Package cache holds the persistent solution caches that need more than the standard library, so that programs
+using the sudokux package don't depend on their clients unless they import this package:
+- **`Redis`**: Solutions stored in a Redis server, shared by every instance of a service.
+- **`Bolt`**: Solutions stored in a bolt database file, for a single process that keeps them across restarts.
+
+Both implement sudokux.SolutionCache, as do sudokux.MemoryCache, sudokux.LRUCache, and sudokux.FileCache. `Open`
+returns the cache described by a string, as given to the --cache flag of the serve command.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"strings"
+
+	"sudokux"
+)
+
+// Open returns the cache described by spec:
+//   - "none" or "" for no cache (a nil cache),
+//   - "memory" for an in-memory cache of up to size solutions, dropping the least recently used ones,
+//   - "redis://host:port/db" (or "rediss://" for TLS) for a Redis server (see NewRedis),
+//   - "bolt:path" for a bolt database file.
+//
+// The caches that hold connections or files implement io.Closer.
+func Open(spec string, size int) (sudokux.SolutionCache, error) {
+	switch {
+	case spec == "" || spec == "none":
+		return nil, nil
+	case spec == "memory":
+		return sudokux.NewLRUCache(size), nil
+	case strings.HasPrefix(spec, "redis://") || strings.HasPrefix(spec, "rediss://"):
+		cache, err := NewRedis(spec)
+		if err != nil {
+			return nil, err // Not a nil *Redis in a non-nil interface
+		}
+		return cache, nil
+	case strings.HasPrefix(spec, "bolt:"):
+		cache, err := NewBolt(strings.TrimPrefix(spec, "bolt:"))
+		if err != nil {
+			return nil, err
+		}
+		return cache, nil
+	}
+	return nil, fmt.Errorf("unknown cache %q (expected none, memory, redis://host:port, or bolt:path)", spec)
+}
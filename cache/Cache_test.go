@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"sudokux"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	readmeSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// checkCache checks that cache misses a puzzle until its solution is put, then returns it.
+func checkCache(t *testing.T, cache sudokux.SolutionCache) {
+	t.Helper()
+	if _, ok := cache.Get(readmePuzzle); ok {
+		t.Fatal("Get() found a solution in an empty cache")
+	}
+	solution, err := sudokux.GridFromString(readmeSolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Put(readmePuzzle, solution); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	got, ok := cache.Get(readmePuzzle)
+	if !ok || sudokux.CanonicalString(got) != readmeSolution {
+		t.Errorf("Get() = %s, %v, want %s", sudokux.CanonicalString(got), ok, readmeSolution)
+	}
+}
+
+func TestBolt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := NewBolt(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCache(t, cache)
+	cache.Close()
+
+	cache, err = NewBolt(path) // The solutions are kept in the file
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if _, ok := cache.Get(readmePuzzle); !ok {
+		t.Error("Get() missed a solution put before the database was closed")
+	}
+}
+
+// fakeRedis serves the commands the Redis cache sends (PING, GET, and SET) over the protocol of Redis, keeping the
+// values in memory. It returns the address it listens on.
+func fakeRedis(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	var mu sync.Mutex
+	values := make(map[string]string)
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			args, err := readCommand(r)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			switch strings.ToUpper(args[0]) {
+			case "PING":
+				fmt.Fprint(conn, "+PONG\r\n")
+			case "GET":
+				if value, ok := values[args[1]]; ok {
+					fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+				} else {
+					fmt.Fprint(conn, "$-1\r\n")
+				}
+			case "SET":
+				values[args[1]] = args[2]
+				fmt.Fprint(conn, "+OK\r\n")
+			case "CLIENT":
+				fmt.Fprint(conn, "+OK\r\n")
+			default: // Including HELLO, so that the client falls back to the older protocol
+				fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+			}
+			mu.Unlock()
+		}
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	args := make([]string, count)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil { // The length of the bulk string
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedis(t *testing.T) {
+	cache, err := NewRedis("redis://" + fakeRedis(t) + "/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	checkCache(t, cache)
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantNil bool
+		wantErr bool
+	}{
+		{spec: "", wantNil: true},
+		{spec: "none", wantNil: true},
+		{spec: "memory"},
+		{spec: "bolt:" + filepath.Join(t.TempDir(), "cache.db")},
+		{spec: "redis://127.0.0.1:1/0", wantErr: true}, // Nothing listens on port 1
+		{spec: "memcached://localhost", wantErr: true},
+	}
+	for _, tt := range tests {
+		cache, err := Open(tt.spec, 10)
+		if (err != nil) != tt.wantErr || (cache == nil) != (tt.wantNil || tt.wantErr) {
+			t.Errorf("Open(%q) = %v, %v", tt.spec, cache, err)
+		}
+		if closer, ok := cache.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
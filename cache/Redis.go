@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"sudokux"
+)
+
+// redisTimeout bounds every command sent to Redis, so that a slow server makes lookups miss rather than hang.
+const redisTimeout = time.Second
+
+// Redis is a SolutionCache stored in a Redis server, under keys such as "sudokux:solution:3x3:classic:53..7....". The
+// solutions never expire, as they never change; configure an eviction policy on the server to bound its memory.
+type Redis struct {
+	client *redis.Client
+}
+
+// NewRedis connects to the Redis server of url, such as "redis://localhost:6379/0", and checks that it answers.
+func NewRedis(url string) (*Redis, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("cannot reach Redis at %s: %v", opts.Addr, err)
+	}
+	return &Redis{client: client}, nil
+}
+
+// Get returns the cached solution for key, if any. Errors of the server are misses.
+func (c *Redis) Get(key string) (map[string]rune, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	solution, err := c.client.Get(ctx, "sudokux:solution:"+key).Result()
+	if err != nil {
+		return nil, false
+	}
+	grid, err := sudokux.GridFromString(solution)
+	return grid, err == nil
+}
+
+// Put stores the solution for key.
+func (c *Redis) Put(key string, solution map[string]rune) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+	return c.client.Set(ctx, "sudokux:solution:"+key, sudokux.CanonicalString(solution), 0).Err()
+}
+
+// Close closes the connections to the server.
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
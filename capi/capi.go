@@ -0,0 +1,190 @@
+/*
+Command capi builds the solver as a C shared library, so that Python, Swift, C++, and other programs can embed it
+instead of running the sudoku binary:
+
+	go build -buildmode=c-shared -o libsudoku.so ./capi
+
+The build writes libsudoku.h next to the library, with the status codes and the functions below. Puzzles are
+passed as C strings holding their rows on separate lines or every cell on one line (see sudokux.ParseBytes), and
+grids come back as their cells on one line, '.' for the empty ones (see sudokux.CanonicalString). Every function
+returns one of the SUDOKU_ status codes and sets *out to a string allocated by the library: the answer for
+SUDOKU_OK, and the error otherwise; out must not be NULL. The caller frees the string with SudokuFree.
+
+Functions:
+- **`SolveString`**: Solves a puzzle under the classic rules, giving its solution.
+- **`RateString`**: Grades a puzzle with a unique solution, giving a JSON object such as {"difficulty": "easy",
+  "score": 1.2, ...}.
+- **`GenerateString`**: Generates a puzzle of a given size and difficulty, giving its cells.
+- **`SudokuFree`**: Frees a string returned by the other functions.
+
+The names, the parameters, and the status codes are kept from one release to the next; new behavior comes as new
+functions.
+*/
+
+package main
+
+/*
+#include <stdlib.h>
+
+// Status codes of the functions of the library.
+enum {
+	SUDOKU_OK = 0,                 // *out holds the answer
+	SUDOKU_INVALID = 1,            // The puzzle can't be read, or breaks a rule
+	SUDOKU_NO_SOLUTION = 2,        // The clues can't be completed
+	SUDOKU_MULTIPLE_SOLUTIONS = 3, // The clues can be completed in several ways
+	SUDOKU_TIMEOUT = 4,            // The search gave up before finding an answer
+	SUDOKU_ERROR = 5,              // Anything else, such as an unknown difficulty
+};
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"sudokux"
+)
+
+// main is required by -buildmode=c-shared, and never runs.
+func main() {}
+
+// rating is the JSON answer of RateString.
+type rating struct {
+	Difficulty    sudokux.Difficulty `json:"difficulty"`
+	Score         float64            `json:"score"`
+	NeedsGuessing bool               `json:"needs_guessing"`
+	Techniques    map[string]int     `json:"techniques"` // Deductions made with each technique the puzzle needs
+}
+
+// SolveString solves puzzle, giving up after timeoutMs milliseconds unless it is 0, and sets *out to its solution.
+//
+//export SolveString
+func SolveString(puzzle *C.char, timeoutMs C.longlong, out **C.char) C.int {
+	text := C.GoString(puzzle)
+	return answer(out, func() (string, error) { return solve(text, int64(timeoutMs)) })
+}
+
+// RateString grades puzzle and sets *out to its rating as JSON. The puzzle must have a unique solution, found
+// within timeoutMs milliseconds unless it is 0.
+//
+//export RateString
+func RateString(puzzle *C.char, timeoutMs C.longlong, out **C.char) C.int {
+	text := C.GoString(puzzle)
+	return answer(out, func() (string, error) { return rate(text, int64(timeoutMs)) })
+}
+
+// GenerateString generates a puzzle with size rows (9 when 0) of the given difficulty ("easy", "medium", "hard",
+// or "" for any), the same one every time for a non-zero seed, and sets *out to its cells.
+//
+//export GenerateString
+func GenerateString(size C.int, difficulty *C.char, seed C.longlong, out **C.char) C.int {
+	name := C.GoString(difficulty)
+	return answer(out, func() (string, error) { return generate(int(size), name, int64(seed)) })
+}
+
+// SudokuFree frees a string set by the other functions of the library. Freeing NULL does nothing.
+//
+//export SudokuFree
+func SudokuFree(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// answer runs f and sets *out to its answer or its error, returning the status code of the error. A panic is
+// returned as SUDOKU_ERROR rather than taking down the program embedding the library.
+func answer(out **C.char, f func() (string, error)) (code C.int) {
+	defer func() {
+		if r := recover(); r != nil {
+			*out = C.CString(fmt.Sprint("internal error: ", r))
+			code = C.SUDOKU_ERROR
+		}
+	}()
+	s, err := f()
+	if err != nil {
+		*out = C.CString(err.Error())
+		return status(err)
+	}
+	*out = C.CString(s)
+	return C.SUDOKU_OK
+}
+
+// solve returns the solution of puzzle, as SolveString.
+func solve(puzzle string, timeoutMs int64) (string, error) {
+	grid, err := parse(puzzle)
+	if err != nil {
+		return "", err
+	}
+	result, err := sudokux.Solve(grid, sudokux.WithTimeout(time.Duration(timeoutMs)*time.Millisecond))
+	if err != nil {
+		return "", err
+	}
+	return sudokux.CanonicalString(result.Solution), nil
+}
+
+// rate returns the rating of puzzle as JSON, as RateString.
+func rate(puzzle string, timeoutMs int64) (string, error) {
+	grid, err := parse(puzzle)
+	if err != nil {
+		return "", err
+	}
+	if _, err := sudokux.Solve(grid, sudokux.WithTimeout(time.Duration(timeoutMs)*time.Millisecond)); err != nil {
+		return "", err // Grades only make sense for puzzles with a unique solution
+	}
+	r := sudokux.RatePuzzle(grid)
+	resp := rating{Difficulty: r.Difficulty, Score: r.Score, NeedsGuessing: r.NeedsGuessing, Techniques: make(map[string]int)}
+	for _, use := range r.Techniques {
+		resp.Techniques[use.Technique] = use.Count
+	}
+	data, err := json.Marshal(resp)
+	return string(data), err
+}
+
+// generate returns the cells of a new puzzle, as GenerateString.
+func generate(size int, difficulty string, seed int64) (string, error) {
+	opts := sudokux.GenerateOptions{Seed: seed}
+	if size != 0 {
+		shape, err := sudokux.ShapeForSize(size)
+		if err != nil {
+			return "", err
+		}
+		opts.Shape = shape
+	}
+	if difficulty != "" {
+		d, err := sudokux.ParseDifficulty(difficulty)
+		if err != nil {
+			return "", err
+		}
+		opts.Difficulty = d
+	}
+	puzzle, _, err := sudokux.Generate(opts)
+	if err != nil {
+		return "", err
+	}
+	return sudokux.CanonicalString(puzzle), nil
+}
+
+// parse reads the grid of puzzle. Its errors are all SUDOKU_INVALID.
+func parse(puzzle string) (map[string]rune, error) {
+	grid, err := sudokux.ParseBytes([]byte(puzzle))
+	if err != nil && !errors.Is(err, sudokux.ErrInvalidGrid) {
+		err = fmt.Errorf("%w: %v", sudokux.ErrInvalidGrid, err)
+	}
+	return grid, err
+}
+
+// status returns the status code of err.
+func status(err error) C.int {
+	switch {
+	case errors.Is(err, sudokux.ErrInvalidGrid):
+		return C.SUDOKU_INVALID
+	case errors.Is(err, sudokux.ErrNoSolution):
+		return C.SUDOKU_NO_SOLUTION
+	case errors.Is(err, sudokux.ErrMultipleSolutions):
+		return C.SUDOKU_MULTIPLE_SOLUTIONS
+	case errors.Is(err, sudokux.ErrTimeout):
+		return C.SUDOKU_TIMEOUT
+	}
+	return C.SUDOKU_ERROR
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sudokux"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	readmeSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+	slowPuzzle     = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.." // Past 10000 nodes, when the search checks its clock
+)
+
+// Status codes of libsudoku.h, which programs embedding the library rely on.
+const (
+	codeOK = iota
+	codeInvalid
+	codeNoSolution
+	codeMultipleSolutions
+	codeTimeout
+	codeError
+)
+
+func TestSolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		puzzle    string
+		timeoutMs int64
+		want      string
+		code      int
+	}{
+		{"cells", readmePuzzle, 0, readmeSolution, codeOK},
+		{"rows", strings.Join([]string{readmePuzzle[:9], readmePuzzle[9:18], readmePuzzle[18:27], readmePuzzle[27:36], readmePuzzle[36:45],
+			readmePuzzle[45:54], readmePuzzle[54:63], readmePuzzle[63:72], readmePuzzle[72:]}, "\n"), 0, readmeSolution, codeOK},
+		{"unreadable", "53..7", 0, "", codeInvalid},
+		{"conflicting clues", "55" + readmePuzzle[2:], 0, "", codeInvalid},
+		{"several solutions", strings.Repeat(".", 64) + readmeSolution[64:], 0, "", codeMultipleSolutions},
+		{"timeout", slowPuzzle, 1, "", codeTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := solve(tt.puzzle, tt.timeoutMs)
+			if got != tt.want || codeOf(err) != tt.code {
+				t.Errorf("solve() = %q, %v (code %d), want %q, code %d", got, err, codeOf(err), tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestRate(t *testing.T) {
+	got, err := rate(readmePuzzle, 0)
+	if err != nil {
+		t.Fatalf("rate() error: %v", err)
+	}
+	var r rating
+	if err := json.Unmarshal([]byte(got), &r); err != nil {
+		t.Fatalf("rate() = %s: %v", got, err)
+	}
+	if r.Difficulty != sudokux.DifficultyEasy || r.Score != 1.2 || r.NeedsGuessing || len(r.Techniques) == 0 {
+		t.Errorf("rate() = %s, want an easy puzzle scoring 1.2", got)
+	}
+	if _, err := rate(strings.Repeat(".", 64)+readmeSolution[64:], 0); codeOf(err) != codeMultipleSolutions {
+		t.Errorf("rate() of a puzzle with several solutions = %v, want code %d", err, codeMultipleSolutions)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	puzzle, err := generate(0, "easy", 42)
+	if err != nil {
+		t.Fatalf("generate() error: %v", err)
+	}
+	if again, _ := generate(0, "easy", 42); again != puzzle {
+		t.Errorf("generate() with the same seed = %q, then %q", puzzle, again)
+	}
+	if solution, err := solve(puzzle, 0); err != nil || len(solution) != 81 {
+		t.Errorf("solve() of the generated puzzle %q = %q, %v", puzzle, solution, err)
+	}
+	if puzzle, err := generate(4, "", 42); err != nil || len(puzzle) != 16 {
+		t.Errorf("generate(4) = %q, %v, want the 16 cells of a 4x4 puzzle", puzzle, err)
+	}
+	for _, tt := range []struct {
+		size       int
+		difficulty string
+	}{{7, ""}, {0, "fiendish"}} {
+		if _, err := generate(tt.size, tt.difficulty, 42); codeOf(err) != codeError {
+			t.Errorf("generate(%d, %q) = %v, want code %d", tt.size, tt.difficulty, err, codeError)
+		}
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("%w: clues A1 and A2 both contain 5", sudokux.ErrInvalidGrid), codeInvalid},
+		{sudokux.ErrNoSolution, codeNoSolution},
+		{sudokux.ErrMultipleSolutions, codeMultipleSolutions},
+		{fmt.Errorf("%w after 1ms", sudokux.ErrTimeout), codeTimeout},
+		{errors.New("unknown difficulty"), codeError},
+	} {
+		if got := codeOf(tt.err); got != tt.code {
+			t.Errorf("status(%v) = %d, want %d", tt.err, got, tt.code)
+		}
+	}
+}
+
+// codeOf returns the status code the library returns for err.
+func codeOf(err error) int {
+	if err == nil {
+		return codeOK
+	}
+	return int(status(err))
+}
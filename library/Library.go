@@ -0,0 +1,212 @@
+/*
+Package library keeps a personal collection of puzzles in a SQLite database file, so that puzzles can be saved,
+listed, and played over several sessions. Every puzzle is stored with its solution, its grade, the grid as the
+player left it, and when it was added, last played, and solved.
+
+Grids are stored as their canonical strings (see sudokux.CanonicalString), so the file can be read with the sqlite3
+shell too. The driver is pure Go, so that programs using this package don't need cgo.
+*/
+
+package library
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+
+	"sudokux"
+)
+
+// ErrNotFound is returned when no puzzle of the library has the given ID.
+var ErrNotFound = errors.New("no such puzzle in the library")
+
+// schema creates the tables of a new library, and does nothing to an existing one.
+const schema = `
+CREATE TABLE IF NOT EXISTS puzzles (
+	id         INTEGER PRIMARY KEY,
+	name       TEXT NOT NULL DEFAULT '',
+	puzzle     TEXT NOT NULL,
+	solution   TEXT NOT NULL,
+	progress   TEXT NOT NULL,
+	difficulty TEXT NOT NULL DEFAULT '',
+	score      REAL NOT NULL DEFAULT 0,
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	solved_at  INTEGER
+);
+CREATE INDEX IF NOT EXISTS puzzles_updated ON puzzles (updated_at);`
+
+// Entry is a puzzle of the library.
+type Entry struct {
+	ID         int64
+	Name       string             // Name given by the player, or ""
+	Puzzle     map[string]rune    // The puzzle as given
+	Solution   map[string]rune    // Its unique solution
+	Progress   map[string]rune    // The grid as the player left it, the puzzle itself until the first move
+	Difficulty sudokux.Difficulty // Grade of the puzzle, or "" for the puzzles RatePuzzle can't grade
+	Score      float64            // Score on the Sudoku Explainer scale (matching it up to 4.0), or 0 if not graded
+	Created    time.Time          // When the puzzle was added
+	Updated    time.Time          // When it was last played, or added
+	Solved     time.Time          // When it was solved, or the zero time if it isn't yet
+}
+
+// Filled returns the number of cells of the progress holding a digit, givens included.
+func (e Entry) Filled() int {
+	return sudokux.CountGivens(e.Progress)
+}
+
+// Library is a collection of puzzles stored in a SQLite database file. It is safe for concurrent use.
+type Library struct {
+	db *sql.DB
+}
+
+// Open opens the library of path, creating the file and its tables if needed.
+func Open(path string) (*Library, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // SQLite writes one transaction at a time anyway, and this avoids "database is locked"
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot open the library %s: %w", path, err)
+	}
+	return &Library{db: db}, nil
+}
+
+// Close closes the database file.
+func (l *Library) Close() error {
+	return l.db.Close()
+}
+
+// Add stores e as a new puzzle and returns its ID. Its progress is the puzzle itself when empty, and its
+// timestamps are now when zero.
+func (l *Library) Add(e Entry) (int64, error) {
+	if e.Progress == nil {
+		e.Progress = e.Puzzle
+	}
+	if e.Created.IsZero() {
+		e.Created = time.Now()
+	}
+	if e.Updated.IsZero() {
+		e.Updated = e.Created
+	}
+	res, err := l.db.Exec(`INSERT INTO puzzles (name, puzzle, solution, progress, difficulty, score, created_at, updated_at, solved_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Name, sudokux.CanonicalString(e.Puzzle), sudokux.CanonicalString(e.Solution), sudokux.CanonicalString(e.Progress),
+		string(e.Difficulty), e.Score, e.Created.Unix(), e.Updated.Unix(), unixOrNull(e.Solved))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Find returns the ID of the puzzle already in the library with the same givens as puzzle, if any.
+func (l *Library) Find(puzzle map[string]rune) (int64, bool, error) {
+	var id int64
+	err := l.db.QueryRow(`SELECT id FROM puzzles WHERE puzzle = ? ORDER BY id LIMIT 1`, sudokux.CanonicalString(puzzle)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return id, err == nil, err
+}
+
+// Get returns the puzzle of id, or ErrNotFound.
+func (l *Library) Get(id int64) (Entry, error) {
+	e, err := scanEntry(l.db.QueryRow(`SELECT `+columns+` FROM puzzles WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return e, err
+}
+
+// List returns every puzzle of the library, the most recently played first.
+func (l *Library) List() ([]Entry, error) {
+	rows, err := l.db.Query(`SELECT ` + columns + ` FROM puzzles ORDER BY updated_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// SaveProgress stores grid as the progress of the puzzle of id, and marks the puzzle solved once grid matches its
+// solution. The time it was first solved is kept if the player clears cells afterwards.
+func (l *Library) SaveProgress(id int64, grid map[string]rune) error {
+	e, err := l.Get(id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	progress := sudokux.CanonicalString(grid)
+	solved := e.Solved
+	if solved.IsZero() && progress == sudokux.CanonicalString(e.Solution) {
+		solved = now
+	}
+	_, err = l.db.Exec(`UPDATE puzzles SET progress = ?, updated_at = ?, solved_at = ? WHERE id = ?`,
+		progress, now.Unix(), unixOrNull(solved), id)
+	return err
+}
+
+// Delete removes the puzzle of id, or returns ErrNotFound.
+func (l *Library) Delete(id int64) error {
+	res, err := l.db.Exec(`DELETE FROM puzzles WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	return nil
+}
+
+// columns are the columns read by scanEntry, in order.
+const columns = `id, name, puzzle, solution, progress, difficulty, score, created_at, updated_at, solved_at`
+
+// scanEntry reads the columns of a row into an Entry.
+func scanEntry(row interface{ Scan(...any) error }) (Entry, error) {
+	var (
+		e                          Entry
+		puzzle, solution, progress string
+		difficulty                 string
+		created, updated           int64
+		solved                     sql.NullInt64
+	)
+	err := row.Scan(&e.ID, &e.Name, &puzzle, &solution, &progress, &difficulty, &e.Score, &created, &updated, &solved)
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, field := range []struct {
+		grid *map[string]rune
+		text string
+	}{{&e.Puzzle, puzzle}, {&e.Solution, solution}, {&e.Progress, progress}} {
+		if *field.grid, err = sudokux.GridFromString(field.text); err != nil {
+			return Entry{}, fmt.Errorf("puzzle %d is damaged: %w", e.ID, err)
+		}
+	}
+	e.Difficulty = sudokux.Difficulty(difficulty)
+	e.Created, e.Updated = time.Unix(created, 0), time.Unix(updated, 0)
+	if solved.Valid {
+		e.Solved = time.Unix(solved.Int64, 0)
+	}
+	return e, nil
+}
+
+// unixOrNull returns t in seconds since the epoch, or NULL for the zero time.
+func unixOrNull(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Unix()
+}
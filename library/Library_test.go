@@ -0,0 +1,122 @@
+package library
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sudokux"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	readmeSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+)
+
+// grid returns the grid of the canonical string s.
+func grid(t *testing.T, s string) map[string]rune {
+	t.Helper()
+	g, err := sudokux.GridFromString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// open opens the library of path, closed at the end of the test.
+func open(t *testing.T, path string) *Library {
+	t.Helper()
+	lib, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { lib.Close() })
+	return lib
+}
+
+func TestLibrary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "library.db")
+	lib := open(t, path)
+	puzzle, solution := grid(t, readmePuzzle), grid(t, readmeSolution)
+	added := time.Unix(1700000000, 0)
+	id, err := lib.Add(Entry{Name: "readme", Puzzle: puzzle, Solution: solution, Difficulty: sudokux.DifficultyEasy, Score: 1.2, Created: added})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	other, err := lib.Add(Entry{Puzzle: solution, Solution: solution, Created: added.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	e, err := lib.Get(id)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if e.Name != "readme" || sudokux.CanonicalString(e.Progress) != readmePuzzle || e.Difficulty != sudokux.DifficultyEasy || e.Score != 1.2 ||
+		!e.Created.Equal(added) || !e.Updated.Equal(added) || !e.Solved.IsZero() {
+		t.Errorf("Get() = %+v, want the entry added, with the puzzle as its progress", e)
+	}
+	if found, ok, err := lib.Find(puzzle); err != nil || !ok || found != id {
+		t.Errorf("Find() = %d, %v, %v, want %d, true, nil", found, ok, err, id)
+	}
+	if _, ok, err := lib.Find(grid(t, readmeSolution[:80]+".")); err != nil || ok {
+		t.Errorf("Find() of a puzzle not in the library = %v, %v, want false, nil", ok, err)
+	}
+
+	// Playing a puzzle moves it to the top of the list, and matching the solution marks it solved for good.
+	progress := grid(t, readmeSolution[:80]+".")
+	if err := lib.SaveProgress(id, progress); err != nil {
+		t.Fatalf("SaveProgress() error: %v", err)
+	}
+	entries, err := lib.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != id || entries[1].ID != other {
+		t.Fatalf("List() = %+v, want puzzles %d then %d", entries, id, other)
+	}
+	if entries[0].Filled() != 80 || !entries[0].Solved.IsZero() {
+		t.Errorf("List()[0] has %d cells filled and solved at %v, want 80 and not solved", entries[0].Filled(), entries[0].Solved)
+	}
+	if err := lib.SaveProgress(id, solution); err != nil {
+		t.Fatalf("SaveProgress() error: %v", err)
+	}
+	solvedAt := mustGet(t, lib, id).Solved
+	if solvedAt.IsZero() {
+		t.Fatal("SaveProgress() of the solution didn't mark the puzzle solved")
+	}
+	if err := lib.SaveProgress(id, progress); err != nil {
+		t.Fatalf("SaveProgress() error: %v", err)
+	}
+	if got := mustGet(t, lib, id).Solved; !got.Equal(solvedAt) {
+		t.Errorf("Solved = %v after clearing a cell, want %v", got, solvedAt)
+	}
+
+	if err := lib.Delete(other); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := lib.Delete(other); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of a deleted puzzle = %v, want ErrNotFound", err)
+	}
+	if _, err := lib.Get(other); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a deleted puzzle = %v, want ErrNotFound", err)
+	}
+
+	// The puzzles are still there once the file is opened again.
+	lib.Close()
+	again := open(t, path)
+	if got := mustGet(t, again, id); sudokux.CanonicalString(got.Progress) != readmeSolution[:80]+"." || !got.Solved.Equal(solvedAt) {
+		t.Errorf("Get() after reopening = %+v, want the progress and solving time saved", got)
+	}
+}
+
+// mustGet returns the puzzle of id in lib.
+func mustGet(t *testing.T, lib *Library, id int64) Entry {
+	t.Helper()
+	e, err := lib.Get(id)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	return e
+}
@@ -0,0 +1,103 @@
+/*
+Package pdf writes simple PDF documents, made of lines and text in the standard Helvetica fonts, which is all a
+printed puzzle needs. Every PDF viewer has the standard fonts built in, so nothing is embedded and the files stay
+small. Coordinates are in points (1/72 inch) from the bottom-left corner of the page, as in PDF itself.
+
+It has no dependency on the rest of the module, so that programs using the solver don't need it, and programs
+printing puzzles can use it on its own (see the book package for puzzle books).
+
+Types:
+- **`Document`**: Collects the pages of a document and writes them out as a PDF file.
+*/
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Size of an A4 page, in points.
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// Document is a PDF document under construction, one content stream per page. The zero value is an empty document.
+type Document struct {
+	pages []*bytes.Buffer // Drawing operators of each page
+}
+
+// NewPage starts a new page; the following drawing goes on it.
+func (d *Document) NewPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+// Line draws a black line of the given width from (x1, y1) to (x2, y2) on the current page.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.pages[len(d.pages)-1], "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Text draws s in Helvetica (bold if asked) of the given size, starting at (x, y) on the baseline, in the given
+// shade of gray (0 for black, 1 for white).
+func (d *Document) Text(x, y, size float64, bold bool, gray float64, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+	fmt.Fprintf(d.pages[len(d.pages)-1], "BT %.2f g /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", gray, font, size, x, y, escaped)
+}
+
+// CenteredText draws s centered horizontally on x (see Text).
+func (d *Document) CenteredText(x, y, size float64, bold bool, gray float64, s string) {
+	d.Text(x-TextWidth(s, size)/2, y, size, bold, gray, s)
+}
+
+// TextWidth returns the width of s in Helvetica of the given size. Digits are exactly 0.556 em wide in both
+// weights; other characters are counted at the same width, which is close enough to center short labels.
+func TextWidth(s string, size float64) float64 {
+	return float64(len(s)) * 0.556 * size
+}
+
+// Pages returns the number of pages of the document.
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Write writes the document as a PDF file to w.
+func (d *Document) Write(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int // Byte offset of each object, for the cross-reference table
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	// Objects 1 to 4 are the catalog, the page tree, and the two fonts; each page then takes two objects, the page
+	// itself and its content stream.
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", PageWidth, PageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,92 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsGroup is the queue group of the workers, among which NATS shares the jobs.
+const natsGroup = "sudokux-workers"
+
+// NATS is a Broker taking its jobs from a subject of a NATS server. Results go to the reply subject of the job, as
+// for nats.Conn.Request, or else to the subject of the results. NATS doesn't keep the messages it delivers, so Ack
+// and Reject do nothing.
+type NATS struct {
+	conn    *nats.Conn
+	jobs    string // Subject of the jobs
+	results string // Subject of the results
+}
+
+// NewNATS connects to the NATS server of url, such as "nats://localhost:4222".
+func NewNATS(url, jobs, results string) (*NATS, error) {
+	conn, err := nats.Connect(url, nats.Name("sudokux worker"))
+	if err != nil {
+		return nil, err
+	}
+	return &NATS{conn: conn, jobs: jobs, results: results}, nil
+}
+
+// Receive subscribes to the subject of the jobs, in the queue group of the workers.
+func (b *NATS) Receive(ctx context.Context) (<-chan Message, error) {
+	incoming := make(chan *nats.Msg, 64)
+	sub, err := b.conn.ChanQueueSubscribe(b.jobs, natsGroup, incoming)
+	if err != nil {
+		return nil, err
+	}
+	closed := make(chan struct{})
+	b.conn.SetClosedHandler(func(*nats.Conn) { close(closed) })
+	messages := make(chan Message)
+	go func() {
+		defer close(messages)
+		for {
+			select {
+			case msg := <-incoming:
+				messages <- natsMessage{broker: b, msg: msg}
+			case <-ctx.Done():
+				sub.Unsubscribe() // The jobs already buffered are lost, as NATS wouldn't deliver them again anyway
+				return
+			case <-closed:
+				return
+			}
+		}
+	}()
+	return messages, nil
+}
+
+// Close flushes the results published and closes the connection.
+func (b *NATS) Close() error {
+	err := b.conn.Flush()
+	b.conn.Close()
+	return err
+}
+
+// natsMessage is a job delivered by NATS.
+type natsMessage struct {
+	broker *NATS
+	msg    *nats.Msg
+}
+
+// Body returns the data of the message.
+func (m natsMessage) Body() []byte {
+	return m.msg.Data
+}
+
+// Reply publishes result to the reply subject of the message, or to the subject of the results.
+func (m natsMessage) Reply(ctx context.Context, result []byte) error {
+	subject := m.msg.Reply
+	if subject == "" {
+		subject = m.broker.results
+	}
+	return m.broker.conn.Publish(subject, result)
+}
+
+// Ack does nothing, as NATS doesn't deliver messages again.
+func (m natsMessage) Ack() error {
+	return nil
+}
+
+// Reject does nothing, as NATS doesn't deliver messages again.
+func (m natsMessage) Reject() error {
+	return nil
+}
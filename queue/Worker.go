@@ -0,0 +1,171 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"sudokux"
+)
+
+// Actions of a job.
+const (
+	ActionSolve = "solve" // Solve the puzzle (the default)
+	ActionRate  = "rate"  // Grade the puzzle, without its solution
+)
+
+// Job is a puzzle to solve or rate.
+type Job struct {
+	ID        string   `json:"id,omitempty"`         // Copied to the result, to match them up
+	Action    string   `json:"action,omitempty"`     // ActionSolve or ActionRate
+	Puzzle    []string `json:"puzzle"`               // Rows of the puzzle, or its 81 cells in a single string
+	TimeoutMs int64    `json:"timeout_ms,omitempty"` // Time limit in milliseconds, within the limit of the worker
+}
+
+// Result is the outcome of a Job.
+type Result struct {
+	ID                string  `json:"id,omitempty"` // ID of the job
+	Action            string  `json:"action"`       // Action of the job
+	Rating            *Rating `json:"rating,omitempty"`
+	Error             string  `json:"error,omitempty"` // Why the puzzle has no solution or grade, or why the job can't be read
+	*sudokux.Document         // The puzzle and its solution, unless the job can't be read
+}
+
+// Rating is the grade of a puzzle, in the Result of ActionRate.
+type Rating struct {
+	Difficulty    sudokux.Difficulty `json:"difficulty"`     // easy, medium, or hard
+	Score         float64            `json:"score"`          // Score on the Sudoku Explainer scale, matching it up to 4.0
+	NeedsGuessing bool               `json:"needs_guessing"` // Whether logic gets stuck before the grid is full
+}
+
+// Worker handles the jobs of a Broker until it is stopped.
+type Worker struct {
+	Broker      Broker
+	Concurrency int           // Jobs handled at once, or 0 for one per core
+	Retries     int           // Times publishing a result is tried again before its job is rejected
+	MaxTimeout  time.Duration // Longest search allowed for one puzzle, or 0 for no limit
+}
+
+// retryDelay is the wait before the first retry of a result, doubled at every retry.
+const retryDelay = 100 * time.Millisecond
+
+// Run handles jobs until ctx is done or the broker stops delivering them, such as when the connection is lost, then
+// waits for the jobs in progress. It returns the error of ctx, or why the jobs stopped.
+func (w *Worker) Run(ctx context.Context) error {
+	messages, err := w.Broker.Receive(ctx)
+	if err != nil {
+		return err
+	}
+	workers := w.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range messages {
+				w.handle(msg)
+			}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() == nil {
+		return errors.New("the broker stopped delivering jobs")
+	}
+	return ctx.Err()
+}
+
+// handle publishes the result of msg, trying again w.Retries times, then acknowledges it, or rejects it if the
+// result can't be published. Jobs in progress are finished once the worker is stopped, so the publishing doesn't
+// depend on the context of Run.
+func (w *Worker) handle(msg Message) {
+	result, err := json.Marshal(w.Process(msg.Body()))
+	if err != nil {
+		slog.Error("cannot encode the result", "error", err)
+		msg.Reject()
+		return
+	}
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = msg.Reply(ctx, result)
+		cancel()
+		if err == nil || attempt >= w.Retries {
+			break
+		}
+		slog.Debug("retrying a result", "attempt", attempt+1, "error", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+	if err != nil {
+		slog.Error("cannot publish a result", "retries", w.Retries, "error", err)
+		msg.Reject()
+		return
+	}
+	if err := msg.Ack(); err != nil {
+		slog.Error("cannot acknowledge a job", "error", err)
+	}
+}
+
+// Process returns the result of the job of body: a Job as JSON, or a puzzle as its 81 cells.
+func (w *Worker) Process(body []byte) Result {
+	var job Job
+	if trimmed := bytes.TrimSpace(body); bytes.HasPrefix(trimmed, []byte("{")) {
+		if err := json.Unmarshal(trimmed, &job); err != nil {
+			return Result{Action: ActionSolve, Error: fmt.Sprintf("the job can't be read: %v", err)}
+		}
+	} else {
+		job.Puzzle = []string{string(trimmed)}
+	}
+	if job.Action == "" {
+		job.Action = ActionSolve
+	}
+	result := Result{ID: job.ID, Action: job.Action}
+	if job.Action != ActionSolve && job.Action != ActionRate {
+		result.Error = fmt.Sprintf("unknown action %q (expected %s or %s)", job.Action, ActionSolve, ActionRate)
+		return result
+	}
+	grid, err := sudokux.ParseBytes([]byte(strings.Join(job.Puzzle, "\n")))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	var opts []sudokux.Option
+	if timeout := w.timeout(job.TimeoutMs); timeout > 0 {
+		opts = append(opts, sudokux.WithTimeout(timeout))
+	}
+	solved, err := sudokux.Solve(grid, opts...)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if job.Action == ActionRate {
+		doc := sudokux.NewDocument(sudokux.ShapeOf(grid), grid, &sudokux.Result{Status: solved.Status, SolutionCount: solved.SolutionCount})
+		result.Document = &doc
+		if err == nil { // Grades only make sense for puzzles with a unique solution
+			rating := sudokux.RatePuzzle(grid)
+			result.Rating = &Rating{Difficulty: rating.Difficulty, Score: rating.Score, NeedsGuessing: rating.NeedsGuessing}
+		}
+		return result
+	}
+	doc := sudokux.NewDocument(sudokux.ShapeOf(grid), grid, &solved)
+	result.Document = &doc
+	return result
+}
+
+// timeout returns the shorter of the worker's limit and the job's limit, or 0 if neither is set.
+func (w *Worker) timeout(requestedMs int64) time.Duration {
+	timeout, requested := w.MaxTimeout, time.Duration(requestedMs)*time.Millisecond
+	if requested > 0 && (timeout == 0 || requested < timeout) {
+		timeout = requested
+	}
+	return timeout
+}
@@ -0,0 +1,178 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	readmeSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+	slowPuzzle     = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.." // Past 10000 nodes, when the search checks its clock
+)
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		name       string
+		worker     Worker
+		body       string
+		id, action string
+		status     string // Status of the document, or "" for a result without one
+		solved     bool   // Whether the result holds the solution
+		rated      bool   // Whether the result holds a grade
+		error      string // Part of the error of the result, or "" for none
+	}{
+		{name: "cells", body: readmePuzzle + "\n", action: ActionSolve, status: "solved", solved: true},
+		{name: "job", body: `{"id": "7", "puzzle": ["` + readmePuzzle + `"]}`, id: "7", action: ActionSolve, status: "solved", solved: true},
+		{name: "rows", body: `{"puzzle": ["53..7....", "6..195...", ".98....6.", "8...6...3", "4..8.3..1", "7...2...6", ".6....28.", "...419..5", "....8..79"]}`,
+			action: ActionSolve, status: "solved", solved: true},
+		{name: "rate", body: `{"id": "r", "action": "rate", "puzzle": ["` + readmePuzzle + `"]}`, id: "r", action: ActionRate, status: "solved", rated: true},
+		{name: "rate without a unique solution", body: `{"action": "rate", "puzzle": ["` + strings.Repeat(".", 64) + readmeSolution[64:] + `"]}`,
+			action: ActionRate, status: "multiple", error: "more than one solution"},
+		{name: "multiple", body: strings.Repeat(".", 64) + readmeSolution[64:], action: ActionSolve, status: "multiple", error: "more than one solution"},
+		{name: "conflicting clues", body: "55" + readmePuzzle[2:], action: ActionSolve, error: "invalid grid"},
+		{name: "unreadable puzzle", body: "53..7", action: ActionSolve, error: "81"},
+		{name: "unreadable job", body: `{"puzzle": 5}`, action: ActionSolve, error: "the job can't be read"},
+		{name: "unknown action", body: `{"id": "u", "action": "print", "puzzle": ["` + readmePuzzle + `"]}`, id: "u", action: "print", error: "unknown action"},
+		{name: "timeout of the worker", worker: Worker{MaxTimeout: time.Nanosecond}, body: slowPuzzle, action: ActionSolve, status: "timeout", error: "timed out"},
+		{name: "timeout of the job", worker: Worker{MaxTimeout: time.Minute}, body: `{"timeout_ms": 1, "puzzle": ["` + slowPuzzle + `"]}`,
+			action: ActionSolve, status: "timeout", error: "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.worker.Process([]byte(tt.body))
+			if result.ID != tt.id || result.Action != tt.action {
+				t.Errorf("ID, Action = %q, %q, want %q, %q", result.ID, result.Action, tt.id, tt.action)
+			}
+			status := ""
+			if result.Document != nil && result.Document.Status != nil {
+				status = result.Document.Status.String()
+			}
+			if status != tt.status {
+				t.Errorf("status = %q, want %q", status, tt.status)
+			}
+			if solved := result.Document != nil && result.Document.Solution != nil; solved != tt.solved {
+				t.Errorf("document = %+v, want a solution: %v", result.Document, tt.solved)
+			}
+			if rated := result.Rating != nil; rated != tt.rated {
+				t.Errorf("rating = %+v, want one: %v", result.Rating, tt.rated)
+			}
+			if tt.error == "" && result.Error != "" || !strings.Contains(result.Error, tt.error) {
+				t.Errorf("error = %q, want %q", result.Error, tt.error)
+			}
+		})
+	}
+}
+
+// fakeBroker is a Broker delivering its messages once, then stopping like a lost connection.
+type fakeBroker struct {
+	messages []*fakeMessage
+}
+
+func (b *fakeBroker) Receive(ctx context.Context) (<-chan Message, error) {
+	ch := make(chan Message)
+	go func() {
+		defer close(ch)
+		for _, msg := range b.messages {
+			ch <- msg
+		}
+	}()
+	return ch, nil
+}
+
+func (b *fakeBroker) Close() error {
+	return nil
+}
+
+// fakeMessage is a message of fakeBroker, whose replies fail a given number of times.
+type fakeMessage struct {
+	body  string
+	fails int // Replies failing before one succeeds
+
+	mu            sync.Mutex
+	replies       int    // Replies attempted
+	result        []byte // Result of the reply that succeeded
+	acked, reject bool
+}
+
+func (m *fakeMessage) Body() []byte {
+	return []byte(m.body)
+}
+
+func (m *fakeMessage) Reply(ctx context.Context, result []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replies++
+	if m.replies <= m.fails {
+		return errors.New("broker unavailable")
+	}
+	m.result = result
+	return nil
+}
+
+func (m *fakeMessage) Ack() error {
+	m.mu.Lock()
+	m.acked = true
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *fakeMessage) Reject() error {
+	m.mu.Lock()
+	m.reject = true
+	m.mu.Unlock()
+	return nil
+}
+
+// TestWorker checks that a Worker answers every job, acknowledging those whose result it could publish within its
+// retries and rejecting the others.
+func TestWorker(t *testing.T) {
+	ok := &fakeMessage{body: `{"id": "ok", "puzzle": ["` + readmePuzzle + `"]}`}
+	retried := &fakeMessage{body: readmePuzzle, fails: 2}
+	lost := &fakeMessage{body: readmePuzzle, fails: 3}
+	worker := Worker{Broker: &fakeBroker{messages: []*fakeMessage{ok, retried, lost}}, Concurrency: 2, Retries: 2}
+	if err := worker.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "stopped delivering") {
+		t.Errorf("Run() = %v, want an error once the broker stops", err)
+	}
+	for _, tt := range []struct {
+		name          string
+		msg           *fakeMessage
+		replies       int
+		acked, reject bool
+	}{
+		{"ok", ok, 1, true, false},
+		{"retried", retried, 3, true, false},
+		{"lost", lost, 3, false, true},
+	} {
+		if tt.msg.replies != tt.replies || tt.msg.acked != tt.acked || tt.msg.reject != tt.reject {
+			t.Errorf("%s: %d replies, acked %v, rejected %v, want %d, %v, %v", tt.name, tt.msg.replies, tt.msg.acked, tt.msg.reject,
+				tt.replies, tt.acked, tt.reject)
+		}
+	}
+	var result struct {
+		ID       string   `json:"id"`
+		Status   string   `json:"status"`
+		Solution []string `json:"solution"`
+	}
+	if err := json.Unmarshal(ok.result, &result); err != nil {
+		t.Fatalf("result %s: %v", ok.result, err)
+	}
+	if result.ID != "ok" || result.Status != "solved" || strings.Join(result.Solution, "") != readmeSolution {
+		t.Errorf("result = %s, want the solution of job ok", ok.result)
+	}
+}
+
+// TestRunCanceled checks that Run returns the error of its context once it is done.
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	worker := Worker{Broker: &fakeBroker{}}
+	if err := worker.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+}
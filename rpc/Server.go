@@ -0,0 +1,207 @@
+/*
+Package rpc serves the solver over gRPC, for backend services written in other languages. The interface is
+defined in sudoku.proto; sudoku.pb.go and sudoku_grpc.pb.go are generated from it by protoc-gen-go and
+protoc-gen-go-grpc, and this file implements it on top of the sudokux package:
+- **`SolvePuzzle`**: Solves a puzzle with the engine asked for, within the time limit of the request or the call.
+- **`GeneratePuzzle`**: Generates a puzzle, as the generate command does, within the time limit of the call.
+- **`RatePuzzle`**: Grades a puzzle, as the rate command does.
+- **`BatchSolve`**: Solves a stream of puzzles, answering each one in turn.
+
+Puzzles are read with `sudokux.ParseBytes`, so they can be sent as rows or, for a classic puzzle, on one line.
+Register a `Server` on a `grpc.Server` with `RegisterSudokuServer`, as the grpc command of the program does.
+*/
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sudokux"
+)
+
+// Server implements the Sudoku service.
+type Server struct {
+	UnimplementedSudokuServer
+
+	MaxTimeout time.Duration // Longest search allowed for one puzzle, or 0 for no limit but the deadline of the call
+}
+
+// SolvePuzzle solves the puzzle of the request. Invalid puzzles fail with codes.InvalidArgument; puzzles without a
+// unique solution get a response whose status says why.
+func (s *Server) SolvePuzzle(ctx context.Context, req *SolveRequest) (*SolveResponse, error) {
+	resp := s.solve(ctx, req)
+	if resp.Status == Status_STATUS_INVALID {
+		return nil, status.Error(codes.InvalidArgument, resp.Error)
+	}
+	return resp, nil
+}
+
+// BatchSolve solves the puzzles of the stream one after the other, sending the response of each before reading the
+// next request, until the client closes its side of the stream.
+func (s *Server) BatchSolve(stream Sudoku_BatchSolveServer) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := stream.Send(s.solve(stream.Context(), req)); err != nil {
+			return err
+		}
+	}
+}
+
+// solve solves the puzzle of req within the time limits of the server, the request, and ctx, whichever is
+// shortest.
+func (s *Server) solve(ctx context.Context, req *SolveRequest) *SolveResponse {
+	resp := &SolveResponse{Id: req.Id}
+	grid, err := parse(req.Rows)
+	if err != nil {
+		resp.Status, resp.Error = Status_STATUS_INVALID, err.Error()
+		return resp
+	}
+	var opts []sudokux.Option
+	if req.Engine != "" {
+		engine, err := sudokux.EngineByName(req.Engine)
+		if err != nil {
+			resp.Status, resp.Error = Status_STATUS_INVALID, err.Error()
+			return resp
+		}
+		opts = append(opts, sudokux.WithEngine(engine))
+	}
+	if timeout := s.timeout(ctx, time.Duration(req.TimeoutMs)*time.Millisecond); timeout > 0 {
+		opts = append(opts, sudokux.WithTimeout(timeout))
+	}
+	result, err := sudokux.Solve(grid, opts...)
+	resp.Status = statuses[result.Status]
+	resp.Nodes, resp.ElapsedUs, resp.Engine = int64(result.Stats.Nodes), result.Stats.Elapsed.Microseconds(), result.Stats.Engine
+	if err != nil {
+		resp.Error = err.Error()
+		return resp
+	}
+	resp.Solution = rows(result.Solution)
+	return resp
+}
+
+// timeout returns the shortest of the server's limit, the request's limit, and the time left before the deadline of
+// ctx, or 0 if none of them is set.
+func (s *Server) timeout(ctx context.Context, requested time.Duration) time.Duration {
+	timeout := s.MaxTimeout
+	if requested > 0 && (timeout == 0 || requested < timeout) {
+		timeout = requested
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline); timeout == 0 || left < timeout {
+			timeout = max(left, time.Millisecond) // A deadline already past still gets an answer, a timeout
+		}
+	}
+	return timeout
+}
+
+// statuses maps the outcomes of sudokux.Solve to the statuses of the service.
+var statuses = map[sudokux.Status]Status{
+	sudokux.StatusSolved:            Status_STATUS_SOLVED,
+	sudokux.StatusNoSolution:        Status_STATUS_NO_SOLUTION,
+	sudokux.StatusMultipleSolutions: Status_STATUS_MULTIPLE_SOLUTIONS,
+	sudokux.StatusInvalid:           Status_STATUS_INVALID,
+	sudokux.StatusTimeout:           Status_STATUS_TIMEOUT,
+}
+
+// GeneratePuzzle generates a puzzle as configured by the request. Options that can't be read fail with
+// codes.InvalidArgument, and options no puzzle was found for with codes.FailedPrecondition. Within the time limit of
+// the server or the deadline of the call, it keeps generating until then and answers with the matching puzzle with
+// the fewest givens, or fails with codes.DeadlineExceeded if none matched in time.
+func (s *Server) GeneratePuzzle(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	opts := sudokux.GenerateOptions{Seed: req.Seed, Givens: int(req.Givens), NoGuessing: req.NoGuessing, TimeLimit: s.timeout(ctx, 0)}
+	if req.Size != 0 {
+		shape, err := sudokux.ShapeForSize(int(req.Size))
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		opts.Shape = shape
+	}
+	if req.Difficulty != "" && req.Difficulty != "any" {
+		difficulty, err := sudokux.ParseDifficulty(req.Difficulty)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		opts.Difficulty = difficulty
+	}
+	if req.Symmetry != "" {
+		symmetry, err := sudokux.ParseSymmetry(req.Symmetry)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		opts.Symmetry = symmetry
+	}
+	start := time.Now()
+	puzzle, solution, err := sudokux.Generate(opts)
+	if err != nil && opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return &GenerateResponse{
+		Puzzle:     rows(puzzle),
+		Solution:   rows(solution),
+		Difficulty: string(sudokux.Rate(puzzle)),
+		Givens:     int32(sudokux.CountGivens(puzzle)),
+	}, nil
+}
+
+// RatePuzzle grades the puzzle of the request. Invalid puzzles, and puzzles without a unique solution, fail with
+// codes.InvalidArgument.
+func (s *Server) RatePuzzle(ctx context.Context, req *RateRequest) (*RateResponse, error) {
+	grid, err := parse(req.Rows)
+	if err == nil {
+		var opts []sudokux.Option
+		if timeout := s.timeout(ctx, 0); timeout > 0 {
+			opts = append(opts, sudokux.WithTimeout(timeout))
+		}
+		_, err = sudokux.Solve(grid, opts...) // Grades only make sense for puzzles with a unique solution
+	}
+	if errors.Is(err, sudokux.ErrTimeout) {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	rating := sudokux.RatePuzzle(grid)
+	low, high := rating.SolveTime()
+	resp := &RateResponse{
+		Difficulty:      string(rating.Difficulty),
+		Score:           rating.Score,
+		NeedsGuessing:   rating.NeedsGuessing,
+		MinSolveSeconds: int64(low.Seconds()),
+		MaxSolveSeconds: int64(high.Seconds()),
+	}
+	for _, use := range rating.Techniques {
+		resp.Techniques = append(resp.Techniques, &TechniqueUse{Technique: use.Technique, Count: int32(use.Count)})
+	}
+	return resp, nil
+}
+
+// parse reads a puzzle sent as rows, or as a single string of cells.
+func parse(lines []string) (map[string]rune, error) {
+	return sudokux.ParseBytes([]byte(strings.Join(lines, "\n")))
+}
+
+// rows returns the rows of grid.
+func rows(grid map[string]rune) []string {
+	line, size := sudokux.Grid(grid).String(), sudokux.ShapeOf(grid).Size // Every digit is a single byte
+	rows := make([]string, size)
+	for i := range rows {
+		rows[i] = line[i*size : (i+1)*size]
+	}
+	return rows
+}
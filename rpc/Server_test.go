@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	readmePuzzle   = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+	readmeSolution = "534678912672195348198342567859761423426853791713924856961537284287419635345286179"
+	hardPuzzle     = "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"
+	slowPuzzle     = "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.." // Past 10000 nodes, when the search checks its clock
+)
+
+// dial serves server over an in-memory connection and returns a client of it.
+func dial(t *testing.T, server *Server) SudokuClient {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	RegisterSudokuServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewSudokuClient(conn)
+}
+
+func TestSolvePuzzle(t *testing.T) {
+	client := dial(t, &Server{})
+	tests := []struct {
+		name       string
+		req        *SolveRequest
+		wantCode   codes.Code
+		wantStatus Status
+	}{
+		{"solved", &SolveRequest{Rows: []string{readmePuzzle}}, codes.OK, Status_STATUS_SOLVED},
+		{"solved by sat", &SolveRequest{Rows: []string{readmePuzzle}, Engine: "sat"}, codes.OK, Status_STATUS_SOLVED},
+		{"multiple", &SolveRequest{Rows: []string{"1...", ".2..", "..3.", "...4"}}, codes.OK, Status_STATUS_MULTIPLE_SOLUTIONS},
+		{"no solution", &SolveRequest{Rows: []string{"1..4", ".4..", "..2.", "3..."}}, codes.OK, Status_STATUS_NO_SOLUTION},
+		{"timeout", &SolveRequest{Rows: []string{hardPuzzle}, Engine: "backtrack-first", TimeoutMs: 1}, codes.OK, Status_STATUS_TIMEOUT},
+		{"bad cell", &SolveRequest{Rows: []string{"x" + readmePuzzle[1:]}}, codes.InvalidArgument, 0},
+		{"unknown engine", &SolveRequest{Rows: []string{readmePuzzle}, Engine: "quantum"}, codes.InvalidArgument, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.SolvePuzzle(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("SolvePuzzle() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("SolvePuzzle() status = %v (%s), want %v", resp.Status, resp.Error, tt.wantStatus)
+			}
+			if resp.Status == Status_STATUS_SOLVED && strings.Join(resp.Solution, "") != readmeSolution {
+				t.Errorf("SolvePuzzle() solution = %v, want %s", resp.Solution, readmeSolution)
+			}
+		})
+	}
+}
+
+func TestBatchSolve(t *testing.T) {
+	client := dial(t, &Server{})
+	stream, err := client.BatchSolve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{"a", "b", "c"}
+	puzzles := []string{readmePuzzle, hardPuzzle, "x"}
+	for i, id := range ids {
+		if err := stream.Send(&SolveRequest{Id: id, Rows: []string{puzzles[i]}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+	want := []Status{Status_STATUS_SOLVED, Status_STATUS_SOLVED, Status_STATUS_INVALID}
+	for i := 0; ; i++ {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			if i != len(ids) {
+				t.Errorf("BatchSolve answered %d requests, want %d", i, len(ids))
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(ids) || resp.Id != ids[i] || resp.Status != want[i] {
+			t.Errorf("response %d = %s %v, want %s %v", i, resp.Id, resp.Status, ids[min(i, len(ids)-1)], want[min(i, len(ids)-1)])
+		}
+	}
+}
+
+func TestGeneratePuzzle(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *Server
+		req      *GenerateRequest
+		wantCode codes.Code
+	}{
+		{"seeded", &Server{}, &GenerateRequest{Seed: 7}, codes.OK},
+		{"easy", &Server{MaxTimeout: time.Second}, &GenerateRequest{Seed: 7, Difficulty: "easy"}, codes.OK},
+		{"bad size", &Server{}, &GenerateRequest{Size: 7}, codes.InvalidArgument},
+		{"bad difficulty", &Server{}, &GenerateRequest{Difficulty: "fiendish"}, codes.InvalidArgument},
+		{"hard without guessing", &Server{}, &GenerateRequest{Difficulty: "hard", NoGuessing: true}, codes.FailedPrecondition},
+		{"out of time", &Server{MaxTimeout: 200 * time.Millisecond}, &GenerateRequest{Size: 25, Difficulty: "medium"}, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			resp, err := dial(t, tt.server).GeneratePuzzle(context.Background(), tt.req)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("GeneratePuzzle() error = %v, want code %v", err, tt.wantCode)
+			}
+			if tt.server.MaxTimeout > 0 && time.Since(start) > tt.server.MaxTimeout+time.Second {
+				t.Errorf("GeneratePuzzle() took %v, past the limit of %v", time.Since(start), tt.server.MaxTimeout)
+			}
+			if err == nil && (len(resp.Puzzle) != 9 || tt.req.Difficulty != "" && resp.Difficulty != tt.req.Difficulty) {
+				t.Errorf("GeneratePuzzle() = %v %s", resp.Puzzle, resp.Difficulty)
+			}
+		})
+	}
+}
+
+func TestRatePuzzle(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *Server
+		rows     []string
+		wantCode codes.Code
+	}{
+		{"easy", &Server{}, []string{readmePuzzle}, codes.OK},
+		{"multiple", &Server{}, []string{"1...", ".2..", "..3.", "...4"}, codes.InvalidArgument},
+		{"bad cell", &Server{}, []string{"x" + readmePuzzle[1:]}, codes.InvalidArgument},
+		{"out of time", &Server{MaxTimeout: time.Nanosecond}, []string{slowPuzzle}, codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := dial(t, tt.server).RatePuzzle(context.Background(), &RateRequest{Rows: tt.rows})
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("RatePuzzle() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && resp.Difficulty != "easy" {
+				t.Errorf("RatePuzzle() difficulty = %s, want easy", resp.Difficulty)
+			}
+		})
+	}
+}
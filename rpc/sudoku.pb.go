@@ -0,0 +1,850 @@
+// The gRPC interface of the solver, for backend services written in other languages. Grids are sent as their
+// rows, one string per row with '.' (or '0') for empty cells, as on the command line; a classic puzzle can also
+// be sent as a single string of its 81 cells. The Go server is in Server.go, and `sudoku grpc` runs it.
+//
+// Messages only ever get new fields, so clients built against an older version of this file keep working.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: sudoku.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Status is the outcome of solving a puzzle.
+type Status int32
+
+const (
+	Status_STATUS_UNSPECIFIED        Status = 0
+	Status_STATUS_SOLVED             Status = 1 // The puzzle has exactly one solution
+	Status_STATUS_NO_SOLUTION        Status = 2 // The clues can't be completed
+	Status_STATUS_MULTIPLE_SOLUTIONS Status = 3 // The clues can be completed in several ways
+	Status_STATUS_INVALID            Status = 4 // The puzzle can't be read, or its clues break a rule
+	Status_STATUS_TIMEOUT            Status = 5 // The search gave up before finding an answer
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_SOLVED",
+		2: "STATUS_NO_SOLUTION",
+		3: "STATUS_MULTIPLE_SOLUTIONS",
+		4: "STATUS_INVALID",
+		5: "STATUS_TIMEOUT",
+	}
+	Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED":        0,
+		"STATUS_SOLVED":             1,
+		"STATUS_NO_SOLUTION":        2,
+		"STATUS_MULTIPLE_SOLUTIONS": 3,
+		"STATUS_INVALID":            4,
+		"STATUS_TIMEOUT":            5,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_sudoku_proto_enumTypes[0].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_sudoku_proto_enumTypes[0]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{0}
+}
+
+type SolveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rows      []string `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`                             // Rows of the puzzle (4 to 25 of them), or its 81 cells in a single string
+	Engine    string   `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`                         // Solving engine, such as "dlx" or "sat" (the backtracking search when empty)
+	TimeoutMs int64    `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"` // Time limit in milliseconds (0 for the deadline of the call alone)
+	Id        string   `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`                                 // Copied into the response, to match the responses of BatchSolve with their requests
+}
+
+func (x *SolveRequest) Reset() {
+	*x = SolveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SolveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveRequest) ProtoMessage() {}
+
+func (x *SolveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveRequest.ProtoReflect.Descriptor instead.
+func (*SolveRequest) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SolveRequest) GetRows() []string {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *SolveRequest) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *SolveRequest) GetTimeoutMs() int64 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *SolveRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SolveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                 // Id of the request
+	Status    Status   `protobuf:"varint,2,opt,name=status,proto3,enum=sudokux.v1.Status" json:"status,omitempty"` // Outcome of the search
+	Solution  []string `protobuf:"bytes,3,rep,name=solution,proto3" json:"solution,omitempty"`                     // Rows of the solution, if the status is STATUS_SOLVED
+	Error     string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                           // Why there is no solution, unless the status is STATUS_SOLVED
+	Nodes     int64    `protobuf:"varint,5,opt,name=nodes,proto3" json:"nodes,omitempty"`                          // Number of search nodes visited
+	ElapsedUs int64    `protobuf:"varint,6,opt,name=elapsed_us,json=elapsedUs,proto3" json:"elapsed_us,omitempty"` // Time spent searching, in microseconds
+	Engine    string   `protobuf:"bytes,7,opt,name=engine,proto3" json:"engine,omitempty"`                         // Name of the engine that searched
+}
+
+func (x *SolveResponse) Reset() {
+	*x = SolveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SolveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveResponse) ProtoMessage() {}
+
+func (x *SolveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveResponse.ProtoReflect.Descriptor instead.
+func (*SolveResponse) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SolveResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SolveResponse) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_STATUS_UNSPECIFIED
+}
+
+func (x *SolveResponse) GetSolution() []string {
+	if x != nil {
+		return x.Solution
+	}
+	return nil
+}
+
+func (x *SolveResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SolveResponse) GetNodes() int64 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+func (x *SolveResponse) GetElapsedUs() int64 {
+	if x != nil {
+		return x.ElapsedUs
+	}
+	return 0
+}
+
+func (x *SolveResponse) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+type GenerateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Difficulty string `protobuf:"bytes,1,opt,name=difficulty,proto3" json:"difficulty,omitempty"`                    // easy, medium, or hard (any grade when empty)
+	Seed       int64  `protobuf:"varint,2,opt,name=seed,proto3" json:"seed,omitempty"`                               // Non-zero to generate the same puzzle every time
+	Symmetry   string `protobuf:"bytes,3,opt,name=symmetry,proto3" json:"symmetry,omitempty"`                        // Symmetry of the clues, such as "rotational" (none when empty)
+	Size       int32  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`                               // Number of rows of the board (9 when 0)
+	Givens     int32  `protobuf:"varint,5,opt,name=givens,proto3" json:"givens,omitempty"`                           // Exact number of givens (as few as possible when 0)
+	NoGuessing bool   `protobuf:"varint,6,opt,name=no_guessing,json=noGuessing,proto3" json:"no_guessing,omitempty"` // Whether the puzzle must be solvable without guessing
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateRequest) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetSymmetry() string {
+	if x != nil {
+		return x.Symmetry
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetGivens() int32 {
+	if x != nil {
+		return x.Givens
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetNoGuessing() bool {
+	if x != nil {
+		return x.NoGuessing
+	}
+	return false
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Puzzle     []string `protobuf:"bytes,1,rep,name=puzzle,proto3" json:"puzzle,omitempty"`         // Rows of the puzzle, with '.' for empty cells
+	Solution   []string `protobuf:"bytes,2,rep,name=solution,proto3" json:"solution,omitempty"`     // Rows of its solution
+	Difficulty string   `protobuf:"bytes,3,opt,name=difficulty,proto3" json:"difficulty,omitempty"` // Grade of the puzzle
+	Givens     int32    `protobuf:"varint,4,opt,name=givens,proto3" json:"givens,omitempty"`        // Number of givens
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateResponse) GetPuzzle() []string {
+	if x != nil {
+		return x.Puzzle
+	}
+	return nil
+}
+
+func (x *GenerateResponse) GetSolution() []string {
+	if x != nil {
+		return x.Solution
+	}
+	return nil
+}
+
+func (x *GenerateResponse) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *GenerateResponse) GetGivens() int32 {
+	if x != nil {
+		return x.Givens
+	}
+	return 0
+}
+
+type RateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rows []string `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"` // Rows of the puzzle, or its 81 cells in a single string
+}
+
+func (x *RateRequest) Reset() {
+	*x = RateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateRequest) ProtoMessage() {}
+
+func (x *RateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateRequest.ProtoReflect.Descriptor instead.
+func (*RateRequest) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RateRequest) GetRows() []string {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type TechniqueUse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Technique string `protobuf:"bytes,1,opt,name=technique,proto3" json:"technique,omitempty"` // Name of the technique, such as "hidden single"
+	Count     int32  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`        // Number of deductions made with it
+}
+
+func (x *TechniqueUse) Reset() {
+	*x = TechniqueUse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TechniqueUse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TechniqueUse) ProtoMessage() {}
+
+func (x *TechniqueUse) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TechniqueUse.ProtoReflect.Descriptor instead.
+func (*TechniqueUse) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TechniqueUse) GetTechnique() string {
+	if x != nil {
+		return x.Technique
+	}
+	return ""
+}
+
+func (x *TechniqueUse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type RateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Difficulty      string          `protobuf:"bytes,1,opt,name=difficulty,proto3" json:"difficulty,omitempty"`                                     // easy, medium, or hard
+	Score           float64         `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`                                             // Score on the Sudoku Explainer scale, matching it up to 4.0
+	Techniques      []*TechniqueUse `protobuf:"bytes,3,rep,name=techniques,proto3" json:"techniques,omitempty"`                                     // Techniques needed, from the simplest to the hardest
+	NeedsGuessing   bool            `protobuf:"varint,4,opt,name=needs_guessing,json=needsGuessing,proto3" json:"needs_guessing,omitempty"`         // Whether logic gets stuck before the grid is full
+	MinSolveSeconds int64           `protobuf:"varint,5,opt,name=min_solve_seconds,json=minSolveSeconds,proto3" json:"min_solve_seconds,omitempty"` // Estimated solving time of a person, from
+	MaxSolveSeconds int64           `protobuf:"varint,6,opt,name=max_solve_seconds,json=maxSolveSeconds,proto3" json:"max_solve_seconds,omitempty"` // to
+}
+
+func (x *RateResponse) Reset() {
+	*x = RateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_sudoku_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateResponse) ProtoMessage() {}
+
+func (x *RateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_sudoku_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateResponse.ProtoReflect.Descriptor instead.
+func (*RateResponse) Descriptor() ([]byte, []int) {
+	return file_sudoku_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RateResponse) GetDifficulty() string {
+	if x != nil {
+		return x.Difficulty
+	}
+	return ""
+}
+
+func (x *RateResponse) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RateResponse) GetTechniques() []*TechniqueUse {
+	if x != nil {
+		return x.Techniques
+	}
+	return nil
+}
+
+func (x *RateResponse) GetNeedsGuessing() bool {
+	if x != nil {
+		return x.NeedsGuessing
+	}
+	return false
+}
+
+func (x *RateResponse) GetMinSolveSeconds() int64 {
+	if x != nil {
+		return x.MinSolveSeconds
+	}
+	return 0
+}
+
+func (x *RateResponse) GetMaxSolveSeconds() int64 {
+	if x != nil {
+		return x.MaxSolveSeconds
+	}
+	return 0
+}
+
+var File_sudoku_proto protoreflect.FileDescriptor
+
+var file_sudoku_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
+	0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x22, 0x69, 0x0a, 0x0c, 0x53, 0x6f,
+	0x6c, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f,
+	0x77, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x65, 0x6e, 0x67, 0x69, 0x6e, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75,
+	0x74, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x4d, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xca, 0x01, 0x0a, 0x0d, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65,
+	0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x5f, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x65, 0x6c, 0x61, 0x70, 0x73, 0x65, 0x64, 0x55, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x6e,
+	0x67, 0x69, 0x6e, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x6e, 0x67, 0x69,
+	0x6e, 0x65, 0x22, 0xae, 0x01, 0x0a, 0x0f, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63,
+	0x75, 0x6c, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x69, 0x66, 0x66,
+	0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x79,
+	0x6d, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x79,
+	0x6d, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x69,
+	0x76, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x67, 0x69, 0x76, 0x65,
+	0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x73, 0x69, 0x6e,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6e, 0x6f, 0x47, 0x75, 0x65, 0x73, 0x73,
+	0x69, 0x6e, 0x67, 0x22, 0x7e, 0x0a, 0x10, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x08, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x67,
+	0x69, 0x76, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x67, 0x69, 0x76,
+	0x65, 0x6e, 0x73, 0x22, 0x21, 0x0a, 0x0b, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x22, 0x42, 0x0a, 0x0c, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x69,
+	0x71, 0x75, 0x65, 0x55, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x69,
+	0x71, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x65, 0x63, 0x68, 0x6e,
+	0x69, 0x71, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xfd, 0x01, 0x0a, 0x0c, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x64, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x12, 0x38, 0x0a, 0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x65, 0x63, 0x68, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x55, 0x73, 0x65, 0x52,
+	0x0a, 0x74, 0x65, 0x63, 0x68, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x6e,
+	0x65, 0x65, 0x64, 0x73, 0x5f, 0x67, 0x75, 0x65, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x6e, 0x65, 0x65, 0x64, 0x73, 0x47, 0x75, 0x65, 0x73, 0x73, 0x69,
+	0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x11, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f,
+	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x6d,
+	0x69, 0x6e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2a,
+	0x0a, 0x11, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x6d, 0x61, 0x78, 0x53, 0x6f,
+	0x6c, 0x76, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x2a, 0x92, 0x01, 0x0a, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a,
+	0x0d, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4e, 0x4f, 0x5f, 0x53, 0x4f,
+	0x4c, 0x55, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x1d, 0x0a, 0x19, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x50, 0x4c, 0x45, 0x5f, 0x53, 0x4f, 0x4c, 0x55,
+	0x54, 0x49, 0x4f, 0x4e, 0x53, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x05, 0x32,
+	0xa1, 0x02, 0x0a, 0x06, 0x53, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x12, 0x42, 0x0a, 0x0b, 0x53, 0x6f,
+	0x6c, 0x76, 0x65, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x18, 0x2e, 0x73, 0x75, 0x64, 0x6f,
+	0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b,
+	0x0a, 0x0e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65,
+	0x12, 0x1b, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x52,
+	0x61, 0x74, 0x65, 0x50, 0x75, 0x7a, 0x7a, 0x6c, 0x65, 0x12, 0x17, 0x2e, 0x73, 0x75, 0x64, 0x6f,
+	0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x18, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0a,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x12, 0x18, 0x2e, 0x73, 0x75, 0x64,
+	0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x6f, 0x6c, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28,
+	0x01, 0x30, 0x01, 0x42, 0x0d, 0x5a, 0x0b, 0x73, 0x75, 0x64, 0x6f, 0x6b, 0x75, 0x78, 0x2f, 0x72,
+	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_sudoku_proto_rawDescOnce sync.Once
+	file_sudoku_proto_rawDescData = file_sudoku_proto_rawDesc
+)
+
+func file_sudoku_proto_rawDescGZIP() []byte {
+	file_sudoku_proto_rawDescOnce.Do(func() {
+		file_sudoku_proto_rawDescData = protoimpl.X.CompressGZIP(file_sudoku_proto_rawDescData)
+	})
+	return file_sudoku_proto_rawDescData
+}
+
+var file_sudoku_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_sudoku_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_sudoku_proto_goTypes = []any{
+	(Status)(0),              // 0: sudokux.v1.Status
+	(*SolveRequest)(nil),     // 1: sudokux.v1.SolveRequest
+	(*SolveResponse)(nil),    // 2: sudokux.v1.SolveResponse
+	(*GenerateRequest)(nil),  // 3: sudokux.v1.GenerateRequest
+	(*GenerateResponse)(nil), // 4: sudokux.v1.GenerateResponse
+	(*RateRequest)(nil),      // 5: sudokux.v1.RateRequest
+	(*TechniqueUse)(nil),     // 6: sudokux.v1.TechniqueUse
+	(*RateResponse)(nil),     // 7: sudokux.v1.RateResponse
+}
+var file_sudoku_proto_depIdxs = []int32{
+	0, // 0: sudokux.v1.SolveResponse.status:type_name -> sudokux.v1.Status
+	6, // 1: sudokux.v1.RateResponse.techniques:type_name -> sudokux.v1.TechniqueUse
+	1, // 2: sudokux.v1.Sudoku.SolvePuzzle:input_type -> sudokux.v1.SolveRequest
+	3, // 3: sudokux.v1.Sudoku.GeneratePuzzle:input_type -> sudokux.v1.GenerateRequest
+	5, // 4: sudokux.v1.Sudoku.RatePuzzle:input_type -> sudokux.v1.RateRequest
+	1, // 5: sudokux.v1.Sudoku.BatchSolve:input_type -> sudokux.v1.SolveRequest
+	2, // 6: sudokux.v1.Sudoku.SolvePuzzle:output_type -> sudokux.v1.SolveResponse
+	4, // 7: sudokux.v1.Sudoku.GeneratePuzzle:output_type -> sudokux.v1.GenerateResponse
+	7, // 8: sudokux.v1.Sudoku.RatePuzzle:output_type -> sudokux.v1.RateResponse
+	2, // 9: sudokux.v1.Sudoku.BatchSolve:output_type -> sudokux.v1.SolveResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_sudoku_proto_init() }
+func file_sudoku_proto_init() {
+	if File_sudoku_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_sudoku_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SolveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SolveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GenerateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*RateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TechniqueUse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_sudoku_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*RateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_sudoku_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_sudoku_proto_goTypes,
+		DependencyIndexes: file_sudoku_proto_depIdxs,
+		EnumInfos:         file_sudoku_proto_enumTypes,
+		MessageInfos:      file_sudoku_proto_msgTypes,
+	}.Build()
+	File_sudoku_proto = out.File
+	file_sudoku_proto_rawDesc = nil
+	file_sudoku_proto_goTypes = nil
+	file_sudoku_proto_depIdxs = nil
+}
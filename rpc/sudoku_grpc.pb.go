@@ -0,0 +1,279 @@
+// The gRPC interface of the solver, for backend services written in other languages. Grids are sent as their
+// rows, one string per row with '.' (or '0') for empty cells, as on the command line; a classic puzzle can also
+// be sent as a single string of its 81 cells. The Go server is in Server.go, and `sudoku grpc` runs it.
+//
+// Messages only ever get new fields, so clients built against an older version of this file keep working.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: sudoku.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Sudoku_SolvePuzzle_FullMethodName    = "/sudokux.v1.Sudoku/SolvePuzzle"
+	Sudoku_GeneratePuzzle_FullMethodName = "/sudokux.v1.Sudoku/GeneratePuzzle"
+	Sudoku_RatePuzzle_FullMethodName     = "/sudokux.v1.Sudoku/RatePuzzle"
+	Sudoku_BatchSolve_FullMethodName     = "/sudokux.v1.Sudoku/BatchSolve"
+)
+
+// SudokuClient is the client API for Sudoku service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Sudoku solves, generates, and grades puzzles.
+type SudokuClient interface {
+	// SolvePuzzle solves a puzzle under the classic rules. A puzzle without a unique solution is not an error: its
+	// status says why there is no solution to return. Invalid puzzles fail with INVALID_ARGUMENT.
+	SolvePuzzle(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error)
+	// GeneratePuzzle generates a puzzle with a unique solution.
+	GeneratePuzzle(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// RatePuzzle grades a puzzle without revealing its solution.
+	RatePuzzle(ctx context.Context, in *RateRequest, opts ...grpc.CallOption) (*RateResponse, error)
+	// BatchSolve solves every puzzle of the request stream, sending a response for each one as soon as it is solved,
+	// in the order of the requests. Invalid puzzles get a response with STATUS_INVALID rather than ending the stream.
+	BatchSolve(ctx context.Context, opts ...grpc.CallOption) (Sudoku_BatchSolveClient, error)
+}
+
+type sudokuClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSudokuClient(cc grpc.ClientConnInterface) SudokuClient {
+	return &sudokuClient{cc}
+}
+
+func (c *sudokuClient) SolvePuzzle(ctx context.Context, in *SolveRequest, opts ...grpc.CallOption) (*SolveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SolveResponse)
+	err := c.cc.Invoke(ctx, Sudoku_SolvePuzzle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) GeneratePuzzle(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, Sudoku_GeneratePuzzle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) RatePuzzle(ctx context.Context, in *RateRequest, opts ...grpc.CallOption) (*RateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateResponse)
+	err := c.cc.Invoke(ctx, Sudoku_RatePuzzle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sudokuClient) BatchSolve(ctx context.Context, opts ...grpc.CallOption) (Sudoku_BatchSolveClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Sudoku_ServiceDesc.Streams[0], Sudoku_BatchSolve_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sudokuBatchSolveClient{ClientStream: stream}
+	return x, nil
+}
+
+type Sudoku_BatchSolveClient interface {
+	Send(*SolveRequest) error
+	Recv() (*SolveResponse, error)
+	grpc.ClientStream
+}
+
+type sudokuBatchSolveClient struct {
+	grpc.ClientStream
+}
+
+func (x *sudokuBatchSolveClient) Send(m *SolveRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sudokuBatchSolveClient) Recv() (*SolveResponse, error) {
+	m := new(SolveResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SudokuServer is the server API for Sudoku service.
+// All implementations must embed UnimplementedSudokuServer
+// for forward compatibility
+//
+// Sudoku solves, generates, and grades puzzles.
+type SudokuServer interface {
+	// SolvePuzzle solves a puzzle under the classic rules. A puzzle without a unique solution is not an error: its
+	// status says why there is no solution to return. Invalid puzzles fail with INVALID_ARGUMENT.
+	SolvePuzzle(context.Context, *SolveRequest) (*SolveResponse, error)
+	// GeneratePuzzle generates a puzzle with a unique solution.
+	GeneratePuzzle(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// RatePuzzle grades a puzzle without revealing its solution.
+	RatePuzzle(context.Context, *RateRequest) (*RateResponse, error)
+	// BatchSolve solves every puzzle of the request stream, sending a response for each one as soon as it is solved,
+	// in the order of the requests. Invalid puzzles get a response with STATUS_INVALID rather than ending the stream.
+	BatchSolve(Sudoku_BatchSolveServer) error
+	mustEmbedUnimplementedSudokuServer()
+}
+
+// UnimplementedSudokuServer must be embedded to have forward compatible implementations.
+type UnimplementedSudokuServer struct {
+}
+
+func (UnimplementedSudokuServer) SolvePuzzle(context.Context, *SolveRequest) (*SolveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SolvePuzzle not implemented")
+}
+func (UnimplementedSudokuServer) GeneratePuzzle(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeneratePuzzle not implemented")
+}
+func (UnimplementedSudokuServer) RatePuzzle(context.Context, *RateRequest) (*RateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RatePuzzle not implemented")
+}
+func (UnimplementedSudokuServer) BatchSolve(Sudoku_BatchSolveServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchSolve not implemented")
+}
+func (UnimplementedSudokuServer) mustEmbedUnimplementedSudokuServer() {}
+
+// UnsafeSudokuServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SudokuServer will
+// result in compilation errors.
+type UnsafeSudokuServer interface {
+	mustEmbedUnimplementedSudokuServer()
+}
+
+func RegisterSudokuServer(s grpc.ServiceRegistrar, srv SudokuServer) {
+	s.RegisterService(&Sudoku_ServiceDesc, srv)
+}
+
+func _Sudoku_SolvePuzzle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).SolvePuzzle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_SolvePuzzle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).SolvePuzzle(ctx, req.(*SolveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_GeneratePuzzle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).GeneratePuzzle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_GeneratePuzzle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).GeneratePuzzle(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_RatePuzzle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SudokuServer).RatePuzzle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Sudoku_RatePuzzle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SudokuServer).RatePuzzle(ctx, req.(*RateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Sudoku_BatchSolve_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SudokuServer).BatchSolve(&sudokuBatchSolveServer{ServerStream: stream})
+}
+
+type Sudoku_BatchSolveServer interface {
+	Send(*SolveResponse) error
+	Recv() (*SolveRequest, error)
+	grpc.ServerStream
+}
+
+type sudokuBatchSolveServer struct {
+	grpc.ServerStream
+}
+
+func (x *sudokuBatchSolveServer) Send(m *SolveResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sudokuBatchSolveServer) Recv() (*SolveRequest, error) {
+	m := new(SolveRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Sudoku_ServiceDesc is the grpc.ServiceDesc for Sudoku service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Sudoku_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sudokux.v1.Sudoku",
+	HandlerType: (*SudokuServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SolvePuzzle",
+			Handler:    _Sudoku_SolvePuzzle_Handler,
+		},
+		{
+			MethodName: "GeneratePuzzle",
+			Handler:    _Sudoku_GeneratePuzzle_Handler,
+		},
+		{
+			MethodName: "RatePuzzle",
+			Handler:    _Sudoku_RatePuzzle_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchSolve",
+			Handler:       _Sudoku_BatchSolve_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "sudoku.proto",
+}
@@ -0,0 +1,70 @@
+/*
+This file lets the server run behind Kubernetes and load balancers:
+- **`GET /healthz`**: Answers 200 as long as the process serves requests at all (the liveness probe).
+- **`GET /readyz`**: Answers 200 while the server takes new solves, and 503 once it is shutting down (the
+  readiness probe), so that traffic goes to other instances.
+- **`Shutdown`**: Stops taking new solves, answering them with 503, and waits for the ones in progress to finish.
+
+The solves of /solve/stream run on hijacked connections, which http.Server.Shutdown doesn't wait for, so the
+server counts the solves itself: shut the http.Server down first, then the Server.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// healthz answers GET /healthz.
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
+// readyz answers GET /readyz.
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	draining := s.draining
+	s.mu.Unlock()
+	if draining {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
+
+// track counts the requests of handler as solves in progress, and answers 503 once the server is shutting down.
+func (s *Server) track(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		if s.draining {
+			s.mu.Unlock()
+			writeError(w, http.StatusServiceUnavailable, errors.New("the server is shutting down"))
+			return
+		}
+		s.solving.Add(1) // Under the lock, so that Shutdown never waits while a solve is being added
+		s.mu.Unlock()
+		defer s.solving.Done()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Shutdown makes the server refuse new solves and fail its readiness probe, then waits for the solves in progress
+// to finish. It returns the error of ctx if ctx is done first.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		s.solving.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+/*
+This file keeps a public server from being overwhelmed by a flood of requests or of pathological puzzles. The limits
+are fields of Server, and each is off when zero:
+- **`MaxConcurrent`**: How many solves run at once. Further solves are answered with 503 right away rather than
+  queued, so that a flood doesn't pile up behind them.
+- **`RatePerSecond`** / **`Burst`**: How many solves each client, told apart by its IP address, may start per
+  second on average, and at once. Further solves are answered with 429.
+- **`MaxBodyBytes`**: The largest body of a request, or message of /solve/stream (sudokux.MaxInputBytes when zero).
+- **`MaxNodes`**: The node budget of every search (see sudokux.WithMaxNodes), on top of MaxTimeout.
+
+Both answers tell the client when to try again in a Retry-After header. Behind a proxy, every request comes from
+the address of the proxy, so the proxy should limit the rate of its clients itself.
+*/
+
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// limit answers the requests of handler with 429 once their client exceeds its rate, and with 503 while the server
+// runs as many solves as it allows.
+func (s *Server) limit(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.RatePerSecond > 0 {
+			if wait := s.clients.take(clientIP(r), s.RatePerSecond, s.Burst, time.Now()); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				writeError(w, http.StatusTooManyRequests, errors.New("too many requests, slow down"))
+				return
+			}
+		}
+		if s.MaxConcurrent > 0 {
+			if !s.acquire() {
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusServiceUnavailable, errors.New("too many solves in progress, try again later"))
+				return
+			}
+			defer s.release()
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// acquire counts one more solve in progress, unless MaxConcurrent are already running.
+func (s *Server) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running >= s.MaxConcurrent {
+		return false
+	}
+	s.running++
+	return true
+}
+
+// release counts one solve less in progress.
+func (s *Server) release() {
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+}
+
+// clientIP returns the IP address of the client of r.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// bucket is the token bucket of one client: it holds up to Burst tokens, refilled at RatePerSecond, and every
+// request takes one.
+type bucket struct {
+	tokens  float64   // Tokens left at updated
+	updated time.Time // When tokens was computed
+}
+
+// buckets holds the token buckets of the clients seen recently.
+type buckets struct {
+	mu    sync.Mutex         // Guards byIP and swept
+	byIP  map[string]*bucket // Client IP address -> its bucket
+	swept time.Time          // When the full buckets were last dropped
+}
+
+// take takes a token from the bucket of ip at now, and returns 0, or how long until the bucket has one if it is
+// empty. The buckets that have refilled are dropped every minute, so that the map only holds the active clients.
+func (b *buckets) take(ip string, rate float64, burst int, now time.Time) time.Duration {
+	capacity := float64(max(burst, 1))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.byIP == nil {
+		b.byIP = make(map[string]*bucket)
+	}
+	if now.Sub(b.swept) > time.Minute {
+		for other, client := range b.byIP {
+			if client.tokens+now.Sub(client.updated).Seconds()*rate >= capacity {
+				delete(b.byIP, other)
+			}
+		}
+		b.swept = now
+	}
+	client, ok := b.byIP[ip]
+	if !ok {
+		client = &bucket{tokens: capacity, updated: now}
+		b.byIP[ip] = client
+	}
+	client.tokens = min(capacity, client.tokens+now.Sub(client.updated).Seconds()*rate)
+	client.updated = now
+	if client.tokens < 1 {
+		return time.Duration((1 - client.tokens) / rate * float64(time.Second))
+	}
+	client.tokens--
+	return 0
+}
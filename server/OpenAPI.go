@@ -0,0 +1,232 @@
+/*
+This file describes the endpoints of the server as an OpenAPI 3.1 document, served at `GET /openapi.json` so that
+client SDKs can be generated from it. The document is built when the server starts, from the schemas of the bodies
+of the requests and answers (see schemas) and the list of the solving engines, so it always matches the server.
+
+The schemas of the requests are also what the server checks their bodies against before reading them (see
+Validate.go): the document is the contract, not a description of it written on the side.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"sudokux"
+)
+
+// schema is a JSON Schema, with the keywords the document and Validate use.
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"` // Another schema of the document, such as "#/components/schemas/Error"
+	Type                 string             `json:"type,omitempty"` // object, array, string, integer, number, or boolean
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"` // False to reject the properties not listed
+	Items                *schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+}
+
+// limit returns a pointer to n, for the bounds of a schema.
+func limit(n int) *int {
+	return &n
+}
+
+// ref returns a schema pointing to the schema of the document with the given name.
+func ref(name string) *schema {
+	return &schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemas returns the schemas of the bodies of the requests and answers, by name.
+func schemas() map[string]*schema {
+	closed := false
+	var engines []string
+	for _, engine := range sudokux.Engines() {
+		engines = append(engines, engine.Name())
+	}
+	puzzle := &schema{
+		Type:        "array",
+		Description: "Rows of the puzzle, with '.' or '0' for empty cells, or a classic puzzle as a single string of its 81 cells",
+		Items:       &schema{Type: "string", MinLength: limit(1), MaxLength: limit(25 * 25)},
+		MinItems:    limit(1),
+		MaxItems:    limit(25),
+	}
+	timeout := &schema{Type: "integer", Minimum: limit(0), Description: "Time limit in milliseconds, within the limit of the server (0 for the limit of the server)"}
+	rows := &schema{Type: "array", Items: &schema{Type: "string"}}
+	return map[string]*schema{
+		"SolveRequest": {
+			Type: "object",
+			Properties: map[string]*schema{
+				"puzzle":     puzzle,
+				"engine":     {Type: "string", Enum: engines, Description: "Solving engine (the backtracking search when left out)"},
+				"timeout_ms": timeout,
+			},
+			Required:             []string{"puzzle"},
+			AdditionalProperties: &closed,
+		},
+		"RateRequest": {
+			Type:                 "object",
+			Properties:           map[string]*schema{"puzzle": puzzle, "timeout_ms": timeout},
+			Required:             []string{"puzzle"},
+			AdditionalProperties: &closed,
+		},
+		"Shape": {
+			Type: "object",
+			Properties: map[string]*schema{
+				"size":     {Type: "integer", Description: "Number of rows, columns, and digits"},
+				"box_rows": {Type: "integer", Description: "Height of a box"},
+				"box_cols": {Type: "integer", Description: "Width of a box"},
+			},
+		},
+		"SolveResponse": {
+			Type:        "object",
+			Description: "The document of the puzzle (see Schema.go), and why it has no solution if it has none",
+			Properties: map[string]*schema{
+				"schema_version": {Type: "integer"},
+				"shape":          ref("Shape"),
+				"puzzle":         rows,
+				"solution":       {Type: "array", Items: &schema{Type: "string"}, Description: "Rows of the solution, if the puzzle has a unique one"},
+				"status":         {Type: "string", Enum: []string{"solved", "no-solution", "multiple", "invalid", "timeout"}},
+				"solution_count": {Type: "integer"},
+				"stats": {Type: "object", Properties: map[string]*schema{
+					"engine":    {Type: "string"},
+					"nodes":     {Type: "integer"},
+					"solutions": {Type: "integer"},
+					"elapsed":   {Type: "integer", Description: "Time spent searching, in nanoseconds"},
+					"timed_out": {Type: "boolean"},
+				}},
+				"error": {Type: "string"},
+			},
+			Required: []string{"schema_version", "shape"},
+		},
+		"RateResponse": {
+			Type: "object",
+			Properties: map[string]*schema{
+				"difficulty": {Type: "string", Enum: []string{"easy", "medium", "hard"}},
+				"score":      {Type: "number", Description: "Score on the Sudoku Explainer scale, matching it up to 4.0"},
+				"techniques": {Type: "array", Items: &schema{Type: "object", Properties: map[string]*schema{
+					"technique": {Type: "string"},
+					"count":     {Type: "integer"},
+				}}},
+				"needs_guessing":    {Type: "boolean"},
+				"min_solve_seconds": {Type: "integer"},
+				"max_solve_seconds": {Type: "integer"},
+			},
+		},
+		"Error": {
+			Type: "object",
+			Properties: map[string]*schema{
+				"error": {Type: "string"},
+				"problems": {Type: "array", Description: "What is wrong with the body of the request, if it doesn't match its schema", Items: &schema{
+					Type: "object",
+					Properties: map[string]*schema{
+						"path":    {Type: "string", Description: "JSON Pointer to the value at fault, such as /puzzle/2"},
+						"message": {Type: "string"},
+					},
+				}},
+			},
+			Required: []string{"error"},
+		},
+	}
+}
+
+// openAPI returns the OpenAPI document of the endpoints, with the given schemas.
+func openAPI(schemas map[string]*schema) map[string]any {
+	body := func(name string) map[string]any {
+		return map[string]any{"content": map[string]any{"application/json": map[string]any{"schema": ref(name)}}}
+	}
+	answer := func(description, name string) map[string]any {
+		answer := body(name)
+		answer["description"] = description
+		return answer
+	}
+	invalid := answer("The body of the request doesn't match its schema, or its puzzle can't be read", "Error")
+	draining := answer("The server is shutting down, or runs as many solves as it allows (see Retry-After)", "Error")
+	limited := answer("The client started more solves than the server allows (see Retry-After)", "Error")
+	cached := func(answer map[string]any) map[string]any {
+		answer["headers"] = map[string]any{"X-Cache": map[string]any{
+			"description": "Whether the solution of the puzzle was in the cache of the server, if it has one",
+			"schema":      &schema{Type: "string", Enum: []string{"hit", "miss"}},
+		}}
+		return answer
+	}
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info":    map[string]any{"title": "Sudoku solver", "version": "1"},
+		"paths": map[string]any{
+			"/solve": map[string]any{"post": map[string]any{
+				"operationId": "solvePuzzle",
+				"summary":     "Solves a puzzle",
+				"requestBody": body("SolveRequest"),
+				"responses": map[string]any{
+					"200": cached(answer("The document of the puzzle, whether it has a unique solution or not", "SolveResponse")),
+					"400": invalid,
+					"429": limited,
+					"503": draining,
+				},
+			}},
+			"/rate": map[string]any{"post": map[string]any{
+				"operationId": "ratePuzzle",
+				"summary":     "Grades a puzzle without revealing its solution",
+				"requestBody": body("RateRequest"),
+				"responses": map[string]any{
+					"200": cached(answer("The grade of the puzzle", "RateResponse")),
+					"400": invalid,
+					"422": answer("The puzzle doesn't have a unique solution", "Error"),
+					"429": limited,
+					"503": draining,
+					"504": answer("The search ran out of time, or of nodes", "Error"),
+				},
+			}},
+			"/solve/stream": map[string]any{"get": map[string]any{
+				"operationId": "streamSolve",
+				"summary":     "Solves a puzzle over a WebSocket, sending every step as it happens",
+				"description": "The client sends a SolveRequest, without an engine, and receives one JSON message per step: " +
+					`"technique" messages, then "place" and "backtrack" messages, and a last "result" message with the ` +
+					`fields of a SolveResponse (or an "error" message).`,
+				"responses": map[string]any{
+					"101": map[string]any{"description": "Switching to the WebSocket protocol"},
+					"429": limited,
+					"503": draining,
+				},
+			}},
+			"/openapi.json": map[string]any{"get": map[string]any{
+				"operationId": "openAPI",
+				"summary":     "Returns this document",
+				"responses":   map[string]any{"200": map[string]any{"description": "The OpenAPI document of the server"}},
+			}},
+			"/healthz": map[string]any{"get": map[string]any{
+				"operationId": "healthz",
+				"summary":     "Liveness probe",
+				"responses":   map[string]any{"200": map[string]any{"description": "The server is running"}},
+			}},
+			"/readyz": map[string]any{"get": map[string]any{
+				"operationId": "readyz",
+				"summary":     "Readiness probe",
+				"responses": map[string]any{
+					"200": map[string]any{"description": "The server takes new solves"},
+					"503": map[string]any{"description": "The server is shutting down"},
+				},
+			}},
+		},
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+// openAPIHandler returns the handler of GET /openapi.json, which serves document.
+func openAPIHandler(document map[string]any) http.HandlerFunc {
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		panic(err) // The document is made of maps, strings, and schemas, which always marshal
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}
+}
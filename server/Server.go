@@ -0,0 +1,278 @@
+/*
+Package server serves the solver over HTTP, for web pages and services that speak JSON rather than gRPC (see the
+rpc package for those). Its endpoints:
+- **`POST /solve`**: Solves a puzzle, answering with its document (see sudokux.Document).
+- **`POST /rate`**: Grades a puzzle, without revealing its solution.
+- **`GET /solve/stream`**: Solves a puzzle over a WebSocket, sending every step as it happens (see Stream.go).
+- **`GET /openapi.json`**: Describes the endpoints as an OpenAPI document (see OpenAPI.go).
+- **`GET /healthz`** / **`GET /readyz`**: Answer the probes of Kubernetes and load balancers (see Health.go).
+
+Requests are JSON objects whose "puzzle" holds the rows of the puzzle, or a classic puzzle as a single string of
+its 81 cells, read with `sudokux.ParseBytes`:
+
+	{"puzzle": ["53..7....", "6..195...", ...], "engine": "dlx", "timeout_ms": 1000}
+
+With a Cache, /solve and /rate look the puzzle up by its canonical string before searching, and remember the
+puzzles found with a unique solution; their answers say whether the cache had it in an X-Cache header, "hit" or
+"miss". Popular puzzles, such as those of the day, are then only searched once.
+
+Public deployments can limit the solves running at once, the rate of the solves of each client, the size of the
+bodies, and the nodes of every search (see Limits.go).
+
+Errors are answered with their HTTP status and a `{"error": "..."}` body, which lists the problems of the body of
+the request if it doesn't match its schema in the OpenAPI document (see Validate.go). Puzzles without a unique solution are
+not errors for /solve: the status of the document says why there is no solution.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"sudokux"
+)
+
+// Server serves the endpoints of the package.
+type Server struct {
+	MaxTimeout    time.Duration         // Longest search allowed for one puzzle, or 0 for no limit
+	Cache         sudokux.SolutionCache // Solutions of the puzzles solved before, or nil to always search
+	MaxConcurrent int                   // Most solves running at once, or 0 for no limit (see Limits.go)
+	RatePerSecond float64               // Solves each client may start per second, or 0 for no limit
+	Burst         int                   // Solves each client may start at once, within RatePerSecond
+	MaxBodyBytes  int64                 // Largest body of a request, or 0 for sudokux.MaxInputBytes
+	MaxNodes      int                   // Node budget of every search, or 0 for no limit
+
+	schemas  map[string]*schema // Schemas of the bodies of the requests and answers, by name
+	mu       sync.Mutex         // Guards draining and running
+	draining bool               // Whether Shutdown was called
+	running  int                // Solves in progress, counted against MaxConcurrent
+	solving  sync.WaitGroup     // Solves in progress
+	clients  buckets            // Token buckets of the clients, for RatePerSecond
+}
+
+// Handler returns the handler of the endpoints.
+func (s *Server) Handler() http.Handler {
+	s.schemas = schemas()
+	mux := http.NewServeMux()
+	mux.Handle("POST /solve", s.track(s.limit(http.HandlerFunc(s.solve))))
+	mux.Handle("POST /rate", s.track(s.limit(http.HandlerFunc(s.rate))))
+	mux.Handle("GET /solve/stream", s.track(s.limit(s.stream())))
+	mux.Handle("GET /openapi.json", openAPIHandler(openAPI(s.schemas)))
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /readyz", s.readyz)
+	return mux
+}
+
+// SolveRequest is the body of the requests of /solve and /solve/stream.
+type SolveRequest struct {
+	Puzzle    []string `json:"puzzle"`               // Rows of the puzzle, or its 81 cells in a single string
+	Engine    string   `json:"engine,omitempty"`     // Solving engine, such as "dlx" (the backtracking search when empty)
+	TimeoutMs int64    `json:"timeout_ms,omitempty"` // Time limit in milliseconds, within the limit of the server
+}
+
+// RateRequest is the body of the requests of /rate.
+type RateRequest struct {
+	Puzzle    []string `json:"puzzle"`               // Rows of the puzzle, or its 81 cells in a single string
+	TimeoutMs int64    `json:"timeout_ms,omitempty"` // Time limit in milliseconds, within the limit of the server
+}
+
+// SolveResponse is the body of the answers of /solve: the document of the puzzle, and why it has no solution if
+// it has none.
+type SolveResponse struct {
+	sudokux.Document
+	Error string `json:"error,omitempty"`
+}
+
+// RateResponse is the body of the answers of /rate.
+type RateResponse struct {
+	Difficulty      sudokux.Difficulty `json:"difficulty"`        // easy, medium, or hard
+	Score           float64            `json:"score"`             // Score on the Sudoku Explainer scale, matching it up to 4.0
+	Techniques      []TechniqueUse     `json:"techniques"`        // Techniques needed, from the simplest to the hardest
+	NeedsGuessing   bool               `json:"needs_guessing"`    // Whether logic gets stuck before the grid is full
+	MinSolveSeconds int64              `json:"min_solve_seconds"` // Estimated solving time of a person, from
+	MaxSolveSeconds int64              `json:"max_solve_seconds"` // to
+}
+
+// TechniqueUse is a technique a puzzle needs, and how many deductions are made with it.
+type TechniqueUse struct {
+	Technique string `json:"technique"`
+	Count     int    `json:"count"`
+}
+
+// solve answers POST /solve.
+func (s *Server) solve(w http.ResponseWriter, r *http.Request) {
+	var req SolveRequest
+	if err := s.decode(w, r, "SolveRequest", &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	grid, err := parse(req.Puzzle)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, puzzleError(err))
+		return
+	}
+	if solution, ok := s.cached(w, grid); ok {
+		result := sudokux.Result{Status: sudokux.StatusSolved, Solution: solution, SolutionCount: 1}
+		writeJSON(w, http.StatusOK, SolveResponse{Document: sudokux.NewDocument(sudokux.ShapeOf(grid), grid, &result)})
+		return
+	}
+	var opts []sudokux.Option
+	if req.Engine != "" {
+		engine, err := sudokux.EngineByName(req.Engine)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		opts = append(opts, sudokux.WithEngine(engine))
+	}
+	result, err := sudokux.Solve(grid, append(opts, s.limits(req.TimeoutMs)...)...)
+	if errors.Is(err, sudokux.ErrInvalidGrid) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	resp := SolveResponse{Document: sudokux.NewDocument(sudokux.ShapeOf(grid), grid, &result)}
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		s.remember(grid, result.Solution)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// rate answers POST /rate. Puzzles without a unique solution can't be graded, and are answered with 422.
+func (s *Server) rate(w http.ResponseWriter, r *http.Request) {
+	var req RateRequest
+	if err := s.decode(w, r, "RateRequest", &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	grid, err := parse(req.Puzzle)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, puzzleError(err))
+		return
+	}
+	if _, ok := s.cached(w, grid); !ok { // Grades only make sense for puzzles with a unique solution
+		result, err := sudokux.Solve(grid, s.limits(req.TimeoutMs)...)
+		switch {
+		case errors.Is(err, sudokux.ErrTimeout):
+			writeError(w, http.StatusGatewayTimeout, err)
+			return
+		case errors.Is(err, sudokux.ErrInvalidGrid):
+			writeError(w, http.StatusBadRequest, err)
+			return
+		case err != nil:
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		s.remember(grid, result.Solution)
+	}
+	rating := sudokux.RatePuzzle(grid)
+	low, high := rating.SolveTime()
+	resp := RateResponse{
+		Difficulty:      rating.Difficulty,
+		Score:           rating.Score,
+		Techniques:      []TechniqueUse{},
+		NeedsGuessing:   rating.NeedsGuessing,
+		MinSolveSeconds: int64(low.Seconds()),
+		MaxSolveSeconds: int64(high.Seconds()),
+	}
+	for _, use := range rating.Techniques {
+		resp.Techniques = append(resp.Techniques, TechniqueUse{Technique: use.Technique, Count: use.Count})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// cached returns the solution of grid from the cache, if there is a cache and it has one, and tells the client
+// whether it did in the X-Cache header.
+func (s *Server) cached(w http.ResponseWriter, grid map[string]rune) (map[string]rune, bool) {
+	if s.Cache == nil {
+		return nil, false
+	}
+	solution, ok := s.Cache.Get(sudokux.CacheKey(sudokux.ShapeOf(grid), sudokux.VariantClassic, grid))
+	if ok {
+		w.Header().Set("X-Cache", "hit")
+	} else {
+		w.Header().Set("X-Cache", "miss")
+	}
+	return solution, ok
+}
+
+// remember stores the unique solution of grid in the cache, if there is one. Failures to store only cost a search
+// the next time.
+func (s *Server) remember(grid, solution map[string]rune) {
+	if s.Cache != nil {
+		s.Cache.Put(sudokux.CacheKey(sudokux.ShapeOf(grid), sudokux.VariantClassic, grid), solution)
+	}
+}
+
+// limits returns the options bounding a search that the request allows requestedMs for.
+func (s *Server) limits(requestedMs int64) []sudokux.Option {
+	var opts []sudokux.Option
+	if timeout := s.timeout(requestedMs); timeout > 0 {
+		opts = append(opts, sudokux.WithTimeout(timeout))
+	}
+	if s.MaxNodes > 0 {
+		opts = append(opts, sudokux.WithMaxNodes(s.MaxNodes))
+	}
+	return opts
+}
+
+// timeout returns the shorter of the server's limit and the request's limit, or 0 if neither is set.
+func (s *Server) timeout(requestedMs int64) time.Duration {
+	timeout, requested := s.MaxTimeout, time.Duration(requestedMs)*time.Millisecond
+	if requested > 0 && (timeout == 0 || requested < timeout) {
+		timeout = requested
+	}
+	return timeout
+}
+
+// decode checks the JSON body of r against the schema of the given name, then reads it into req.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, name string, req any) error {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBodyBytes()))
+	if err != nil {
+		return err
+	}
+	return s.unmarshal(name, data, req)
+}
+
+// maxBodyBytes returns the largest body of a request the server reads.
+func (s *Server) maxBodyBytes() int64 {
+	if s.MaxBodyBytes > 0 {
+		return s.MaxBodyBytes
+	}
+	return sudokux.MaxInputBytes
+}
+
+// parse reads a puzzle sent as rows, or as a single string of cells.
+func parse(lines []string) (map[string]rune, error) {
+	return sudokux.ParseBytes([]byte(strings.Join(lines, "\n")))
+}
+
+// writeJSON answers with v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with err as a JSON error.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse(err))
+}
+
+// errorResponse returns the body of the answer to a request that failed with err, with the problems of the body of
+// the request if that is what err is about.
+func errorResponse(err error) ErrorResponse {
+	resp := ErrorResponse{Error: err.Error()}
+	var invalid *bodyError
+	if errors.As(err, &invalid) {
+		resp.Problems = invalid.problems
+	}
+	return resp
+}
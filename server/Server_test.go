@@ -0,0 +1,112 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+
+	"sudokux"
+)
+
+// readmePuzzle is the puzzle of the readme.
+const readmePuzzle = "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"
+
+// post sends body to the given endpoint of server, returning the status and the body of the answer.
+func post(t *testing.T, server *httptest.Server, path, body string) (int, string) {
+	t.Helper()
+	resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var answer json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(answer)
+}
+
+func TestEndpoints(t *testing.T) {
+	server := httptest.NewServer((&Server{}).Handler())
+	defer server.Close()
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		wantCode int
+		want     string // Substring of the body of the answer
+	}{
+		{"solve", "/solve", `{"puzzle": ["` + readmePuzzle + `"]}`, http.StatusOK, `"534678912"`},
+		{"solve rows with dlx", "/solve", `{"puzzle": ["1...", ".2..", "..3.", "...4"], "engine": "dlx"}`, http.StatusOK, `"status":"multiple"`},
+		{"solve without solution", "/solve", `{"puzzle": ["1..4", ".4..", "..2.", "3..."]}`, http.StatusOK, `"status":"no-solution"`},
+		{"solve bad cell", "/solve", `{"puzzle": ["x` + readmePuzzle[1:] + `"]}`, http.StatusBadRequest, `"error"`},
+		{"solve unknown engine", "/solve", `{"puzzle": ["` + readmePuzzle + `"], "engine": "quantum"}`, http.StatusBadRequest, `"error"`},
+		{"rate", "/rate", `{"puzzle": ["` + readmePuzzle + `"]}`, http.StatusOK, `"difficulty":"easy"`},
+		{"rate multiple", "/rate", `{"puzzle": ["1...", ".2..", "..3.", "...4"]}`, http.StatusUnprocessableEntity, `"error"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := post(t, server, tt.path, tt.body)
+			if code != tt.wantCode || !strings.Contains(body, tt.want) {
+				t.Errorf("POST %s = %d %s, want %d with %s", tt.path, code, body, tt.wantCode, tt.want)
+			}
+		})
+	}
+}
+
+// stream sends request to /solve/stream and returns the kinds of the messages answered.
+func stream(t *testing.T, server *httptest.Server, request string) []string {
+	t.Helper()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/solve/stream", "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	if err := websocket.Message.Send(ws, request); err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for {
+		var msg struct {
+			Kind string `json:"kind"`
+		}
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return kinds // The server closes the connection after the last message
+		}
+		kinds = append(kinds, msg.Kind)
+	}
+}
+
+func TestStream(t *testing.T) {
+	server := httptest.NewServer((&Server{}).Handler())
+	defer server.Close()
+
+	kinds := stream(t, server, `{"puzzle": ["`+readmePuzzle+`"]}`)
+	if len(kinds) < 2 || kinds[0] != KindTechnique || kinds[len(kinds)-1] != KindResult {
+		t.Errorf("/solve/stream sent %v, want techniques then a result", kinds)
+	}
+	kinds = stream(t, server, `{"puzzle": ["`+readmePuzzle+`"], "engine": "dlx"}`)
+	if len(kinds) != 1 || kinds[0] != KindError {
+		t.Errorf("/solve/stream with dlx sent %v, want a single error", kinds)
+	}
+}
+
+// TestCache checks that a puzzle is only searched once with a Cache, by /solve and /rate alike.
+func TestCache(t *testing.T) {
+	server := httptest.NewServer((&Server{Cache: sudokux.NewLRUCache(10)}).Handler())
+	defer server.Close()
+	for _, step := range []struct{ path, want string }{{"/solve", "miss"}, {"/solve", "hit"}, {"/rate", "hit"}} {
+		resp, err := http.Post(server.URL+step.path, "application/json", strings.NewReader(`{"puzzle": ["`+readmePuzzle+`"]}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("X-Cache"); got != step.want {
+			t.Errorf("POST %s: X-Cache = %q, want %q", step.path, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+/*
+This file implements `GET /solve/stream`, which solves a puzzle over a WebSocket and sends every step of the
+solve as it happens, for pages that animate the search in the browser. The client sends one request, as the body
+of /solve, and the server answers with one JSON message per step, then closes the connection:
+
+	{"kind": "technique", "technique": "hidden single", "pos": "A3", "digit": "4", "reason": "..."}
+	{"kind": "place", "pos": "B7", "digit": "2", "depth": 1, "nodes": 1}
+	{"kind": "backtrack", "pos": "B7", "digit": "2", "depth": 1, "nodes": 9}
+	{"kind": "result", "schema_version": 1, "shape": {...}, "puzzle": [...], "solution": [...], "status": "solved", ...}
+
+As with `solve --step`, the logical techniques fill what they can first (the "technique" messages), then the
+backtracking search finishes the grid (the "place" and "backtrack" messages, the events of sudokux.Observer but
+the eliminations). The last message is the document of the puzzle, as /solve answers it, or an "error" message
+if the request can't be read or its givens conflict. Other engines don't report their steps, so the request
+can't choose one.
+*/
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"sudokux"
+)
+
+// Kinds of the messages of /solve/stream that aren't events of the search.
+const (
+	KindTechnique = "technique" // A logical technique placed a digit
+	KindResult    = "result"    // The search is over
+	KindError     = "error"     // The request can't be solved
+)
+
+// TechniqueMessage is a message of /solve/stream for a deduction of the logical techniques.
+type TechniqueMessage struct {
+	Kind      string `json:"kind"` // KindTechnique
+	Technique string `json:"technique"`
+	Pos       string `json:"pos"`
+	Digit     string `json:"digit"`
+	Reason    string `json:"reason"`
+}
+
+// ResultMessage is the last message of /solve/stream once the search is over: the answer of /solve.
+type ResultMessage struct {
+	Kind string `json:"kind"` // KindResult
+	SolveResponse
+}
+
+// ErrorMessage is the only message of /solve/stream when the request can't be solved.
+type ErrorMessage struct {
+	Kind string `json:"kind"` // KindError
+	ErrorResponse
+}
+
+// requestTimeout is how long a client has to send its request once connected.
+const requestTimeout = 10 * time.Second
+
+// stream returns the handler of GET /solve/stream.
+func (s *Server) stream() websocket.Server {
+	return websocket.Server{
+		Handshake: func(*websocket.Config, *http.Request) error { return nil }, // Pages of any origin can connect
+		Handler:   s.streamSolve,
+	}
+}
+
+// streamSolve reads the request of ws and sends the steps of its solve.
+func (s *Server) streamSolve(ws *websocket.Conn) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = int(s.maxBodyBytes())
+	ws.SetReadDeadline(time.Now().Add(requestTimeout))
+	ws.SetWriteDeadline(time.Time{}) // The steps are sent for as long as the search runs, whatever the write timeout of the server
+	var data []byte
+	if err := websocket.Message.Receive(ws, &data); err != nil {
+		sendError(ws, err)
+		return
+	}
+	var req SolveRequest
+	if err := s.unmarshal("SolveRequest", data, &req); err != nil {
+		sendError(ws, err)
+		return
+	}
+	if req.Engine != "" && req.Engine != (sudokux.BacktrackingEngine{}).Name() {
+		sendError(ws, fmt.Errorf("only the backtracking search can stream its steps, not %q", req.Engine))
+		return
+	}
+	puzzle, err := parse(req.Puzzle)
+	if err == nil {
+		err = sudokux.Validate(puzzle) // Logic only makes sense if the givens don't conflict
+	}
+	if err != nil {
+		err = puzzleError(err)
+		sendError(ws, err)
+		return
+	}
+
+	var sendErr error // Once the client is gone, the search runs out without sending anything
+	send := func(msg any) {
+		if sendErr == nil {
+			sendErr = websocket.JSON.Send(ws, msg)
+		}
+	}
+	grid, deductions, _ := sudokux.SolveLogically(puzzle)
+	for _, d := range deductions {
+		send(TechniqueMessage{Kind: KindTechnique, Technique: d.Technique, Pos: d.Pos, Digit: string(d.Digit), Reason: d.Reason})
+	}
+	observer := sudokux.ObserverFuncs{
+		Place:     func(event sudokux.StepEvent) { send(event) },
+		Backtrack: func(event sudokux.StepEvent) { send(event) },
+	}
+	opts := append(s.limits(req.TimeoutMs), sudokux.WithObserver(observer))
+	result, err := sudokux.Solve(grid, opts...)
+	msg := ResultMessage{Kind: KindResult, SolveResponse: SolveResponse{Document: sudokux.NewDocument(sudokux.ShapeOf(puzzle), puzzle, &result)}}
+	if err != nil {
+		msg.Error = err.Error()
+	}
+	send(msg)
+}
+
+// sendError sends err as the only message of ws.
+func sendError(ws *websocket.Conn, err error) {
+	websocket.JSON.Send(ws, ErrorMessage{Kind: KindError, ErrorResponse: errorResponse(err)})
+}
@@ -0,0 +1,161 @@
+/*
+This file checks the bodies of the requests against their schemas in the OpenAPI document (see OpenAPI.go) before
+the handlers read them, so that a bad request fails with every problem it has rather than with the first error of
+encoding/json, or with a field silently ignored because its name has a typo:
+
+	400 {"error": "/puzzle: is required; /timeout_ms: must be at least 0", "problems": [
+	     {"path": "/puzzle", "message": "is required"}, {"path": "/timeout_ms", "message": "must be at least 0"}]}
+
+Each problem names the value at fault with a JSON Pointer. Puzzles that match the schema but can't be read are
+reported the same way, at /puzzle.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem is one way in which the body of a request is wrong.
+type Problem struct {
+	Path    string `json:"path"`    // JSON Pointer to the value at fault, such as "/puzzle/2" ("" for the whole body)
+	Message string `json:"message"` // What is wrong with it
+}
+
+// ErrorResponse is the body of the answers to the requests that fail.
+type ErrorResponse struct {
+	Error    string    `json:"error"`              // What went wrong
+	Problems []Problem `json:"problems,omitempty"` // Every problem of the body of the request, if that is what went wrong
+}
+
+// bodyError is the error of a request whose body is wrong.
+type bodyError struct {
+	problems []Problem
+}
+
+// Error lists the problems on one line.
+func (e *bodyError) Error() string {
+	messages := make([]string, len(e.problems))
+	for i, problem := range e.problems {
+		messages[i] = problem.Message
+		if problem.Path != "" {
+			messages[i] = problem.Path + ": " + problem.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// puzzleError returns err, the reason the puzzle of a request can't be read, as a problem of the body.
+func puzzleError(err error) error {
+	return &bodyError{problems: []Problem{{Path: "/puzzle", Message: err.Error()}}}
+}
+
+// unmarshal checks data against the schema of the given name, then reads it into req.
+func (s *Server) unmarshal(name string, data []byte, req any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep numbers as written, to tell integers apart
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return &bodyError{problems: []Problem{{Message: fmt.Sprintf("the body is not valid JSON: %v", err)}}}
+	}
+	if decoder.More() {
+		return &bodyError{problems: []Problem{{Message: "the body holds more than one JSON value"}}}
+	}
+	if problems := s.validate(s.schemas[name], value, ""); len(problems) > 0 {
+		return &bodyError{problems: problems}
+	}
+	return json.Unmarshal(data, req)
+}
+
+// pointerEscaper escapes the names of properties in JSON Pointers.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// validate returns every problem of value, found at path, against sch.
+func (s *Server) validate(sch *schema, value any, path string) []Problem {
+	if sch.Ref != "" {
+		sch = s.schemas[strings.TrimPrefix(sch.Ref, "#/components/schemas/")]
+	}
+	problem := func(format string, args ...any) []Problem {
+		return []Problem{{Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+	switch sch.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return problem("must be an object")
+		}
+		var problems []Problem
+		for _, name := range sch.Required {
+			if _, ok := object[name]; !ok {
+				problems = append(problems, Problem{Path: path + "/" + pointerEscaper.Replace(name), Message: "is required"})
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		slices.Sort(names) // Report the problems in the same order every time
+		for _, name := range names {
+			property, ok := sch.Properties[name]
+			switch {
+			case ok:
+				problems = append(problems, s.validate(property, object[name], path+"/"+pointerEscaper.Replace(name))...)
+			case sch.AdditionalProperties != nil && !*sch.AdditionalProperties:
+				problems = append(problems, Problem{Path: path + "/" + pointerEscaper.Replace(name), Message: "is not a known field"})
+			}
+		}
+		return problems
+	case "array":
+		array, ok := value.([]any)
+		switch {
+		case !ok:
+			return problem("must be an array")
+		case sch.MinItems != nil && len(array) < *sch.MinItems:
+			return problem("must have at least %d items", *sch.MinItems)
+		case sch.MaxItems != nil && len(array) > *sch.MaxItems:
+			return problem("must have at most %d items", *sch.MaxItems)
+		}
+		var problems []Problem
+		for i, item := range array {
+			problems = append(problems, s.validate(sch.Items, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+		return problems
+	case "string":
+		str, ok := value.(string)
+		length := utf8.RuneCountInString(str)
+		switch {
+		case !ok:
+			return problem("must be a string")
+		case sch.MinLength != nil && *sch.MinLength == 1 && length == 0:
+			return problem("must not be empty")
+		case sch.MinLength != nil && length < *sch.MinLength:
+			return problem("must be at least %d characters long", *sch.MinLength)
+		case sch.MaxLength != nil && length > *sch.MaxLength:
+			return problem("must be at most %d characters long", *sch.MaxLength)
+		case sch.Enum != nil && !slices.Contains(sch.Enum, str):
+			return problem("must be one of %s", strings.Join(sch.Enum, ", "))
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return problem("must be a number")
+		}
+		n, err := number.Int64()
+		switch {
+		case sch.Type == "integer" && err != nil:
+			return problem("must be an integer")
+		case sch.Minimum != nil && err == nil && n < int64(*sch.Minimum):
+			return problem("must be at least %d", *sch.Minimum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return problem("must be true or false")
+		}
+	}
+	return nil
+}
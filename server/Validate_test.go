@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	server := httptest.NewServer((&Server{}).Handler())
+	defer server.Close()
+	tests := []struct {
+		name string
+		body string
+		want []Problem
+	}{
+		{"empty", `{}`, []Problem{{"/puzzle", "is required"}}},
+		{"not json", `{"puzzle":`, nil},
+		{"two values", `{"puzzle": ["` + readmePuzzle + `"]} {}`, []Problem{{"", "the body holds more than one JSON value"}}},
+		{"typo", `{"puzzle": ["` + readmePuzzle + `"], "timeout": 5}`, []Problem{{"/timeout", "is not a known field"}}},
+		{"every problem", `{"puzzle": [1], "timeout_ms": -1}`, []Problem{{"/puzzle/0", "must be a string"}, {"/timeout_ms", "must be at least 0"}}},
+		{"bad puzzle", `{"puzzle": ["x` + readmePuzzle[1:] + `"]}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := post(t, server, "/solve", tt.body)
+			var resp ErrorResponse
+			if err := json.Unmarshal([]byte(body), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if code != http.StatusBadRequest || len(resp.Problems) == 0 {
+				t.Fatalf("POST /solve = %d %s, want 400 with problems", code, body)
+			}
+			if tt.want != nil && !reflect.DeepEqual(resp.Problems, tt.want) {
+				t.Errorf("POST /solve problems = %v, want %v", resp.Problems, tt.want)
+			}
+		})
+	}
+}
+
+// TestOpenAPI checks that the document lists the endpoints, with the schemas the bodies are checked against.
+func TestOpenAPI(t *testing.T) {
+	server := httptest.NewServer((&Server{}).Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var document struct {
+		Paths      map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/solve", "/rate", "/solve/stream"} {
+		if document.Paths[path] == nil {
+			t.Errorf("the document doesn't describe %s", path)
+		}
+	}
+	for _, name := range []string{"SolveRequest", "RateRequest", "SolveResponse", "RateResponse", "Error"} {
+		if document.Components.Schemas[name] == nil {
+			t.Errorf("the document doesn't define the schema %s", name)
+		}
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		t.Errorf("Content-Type = %q, want JSON", resp.Header.Get("Content-Type"))
+	}
+}